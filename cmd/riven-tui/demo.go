@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/fakeriven"
+)
+
+const (
+	demoToken       = "demo"
+	demoScrapeDelay = 2 * time.Second
+	demoTick        = 5 * time.Second
+)
+
+// startDemo serves a fake Riven instance on a random local port and returns
+// a configuration pointing at it. UI preferences are still read from the
+// configuration file when one exists; the API section is always replaced.
+func startDemo(configPath string) (func(), *config.Config, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fake := fakeriven.New(
+		fakeriven.WithToken(demoToken),
+		fakeriven.WithScrapeDelay(demoScrapeDelay),
+	)
+	server := &http.Server{Handler: fake}
+	go server.Serve(listener)
+
+	// Keep the library moving so the screens have something to show
+	ctx, cancel := context.WithCancel(context.Background())
+	go fake.Run(ctx, demoTick)
+
+	cfg.API.Endpoint = "http://" + listener.Addr().String()
+	cfg.API.Token = demoToken
+
+	stop := func() {
+		cancel()
+		server.Close()
+	}
+	return stop, cfg, nil
+}
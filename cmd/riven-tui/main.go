@@ -9,7 +9,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"riven-tui/pkg/api"
 	"riven-tui/pkg/config"
+	"riven-tui/pkg/settings"
 	"riven-tui/pkg/tui"
 )
 
@@ -17,6 +19,7 @@ var (
 	configPath = flag.String("config", "", "Path to configuration file")
 	version    = flag.Bool("version", false, "Show version information")
 	help       = flag.Bool("help", false, "Show help information")
+	demo       = flag.Bool("demo", false, "Run against a built-in fake Riven server")
 )
 
 const (
@@ -47,9 +50,30 @@ func main() {
 	}
 
 	// Load configuration
-	cfg, err := config.LoadConfig(*configPath)
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+	var cfg *config.Config
+	if *demo {
+		stop, demoCfg, err := startDemo(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to start demo server: %v", err)
+		}
+		defer stop()
+		cfg = demoCfg
+	} else {
+		var err error
+		cfg, err = config.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load configuration: %v", err)
+		}
+	}
+
+	// Subcommands run against the API without the TUI
+	if flag.Arg(0) == "settings" {
+		history := settings.NewHistory(cfg.Settings.HistoryDir, cfg.Settings.HistoryLimit)
+		if err := runSettings(api.NewClient(cfg), history, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Validate that we can connect to the API
@@ -78,11 +102,20 @@ A Terminal User Interface (TUI) client for Riven media management system.
 
 USAGE:
     riven-tui [OPTIONS]
+    riven-tui [OPTIONS] settings <export|diff|apply|history|rollback|save|load> [FLAGS]
 
 OPTIONS:
     -config <path>    Path to configuration file
     -version          Show version information
     -help             Show this help message
+    -demo             Explore the TUI against a built-in fake Riven server
+
+SETTINGS:
+    riven-tui settings export -o riven.yaml      Export settings, secrets redacted
+    riven-tui settings diff riven.yaml           Compare a file with the live settings
+    riven-tui settings apply riven.yaml -dry-run Show what applying would change
+    riven-tui settings history                   List recorded changes to roll back
+    Run "riven-tui settings help" for all flags.
 
 CONFIGURATION:
     The application looks for configuration in the following order:
@@ -119,6 +152,7 @@ NAVIGATION:
     m:                 Media items
     s:                 Settings
     l:                 Logs
+    e:                 Events
 
 For more information, visit: https://github.com/rivenmedia/riven
 `, appName, appVersion)
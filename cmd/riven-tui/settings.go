@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+	"riven-tui/pkg/settings"
+)
+
+const settingsUsage = `USAGE:
+    riven-tui [OPTIONS] settings <command> [FLAGS]
+
+COMMANDS:
+    export [-o file] [-format yaml|json] [-secrets]
+        Write the live settings to a file, or stdout without -o. Secrets are
+        redacted unless -secrets is given.
+    diff <file>
+        Compare a settings file with the live settings, path by path.
+    apply <file> [-dry-run] [-replace] [-save]
+        Apply a settings file. Only the settings that differ are sent, unless
+        -replace sends the whole tree. -dry-run prints the changes only and
+        -save writes the result to Riven's settings.json.
+    history [id]
+        List the recorded settings changes, or show one of them.
+    rollback <id> [-dry-run]
+        Restore the values a recorded change replaced.
+    save
+        Save the live settings to Riven's settings.json.
+    load
+        Reload the settings from Riven's settings.json.
+`
+
+// runSettings runs the settings subcommand. Writes are recorded in the
+// history before they are sent.
+func runSettings(client api.SettingsAPI, history *settings.History, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(out, settingsUsage)
+		return fmt.Errorf("missing settings command")
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "export":
+		return exportSettings(ctx, client, args[1:], out)
+	case "diff":
+		return diffSettings(ctx, client, args[1:], out)
+	case "apply":
+		return applySettings(ctx, settings.Record(client, history), args[1:], out)
+	case "history":
+		return showHistory(ctx, client, history, args[1:], out)
+	case "rollback":
+		return rollbackSettings(ctx, settings.Record(client, history), history, args[1:], out)
+	case "save":
+		resp, err := client.SaveSettings(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to save settings: %w", err)
+		}
+		fmt.Fprintln(out, resp.Message)
+		return nil
+	case "load":
+		resp, err := client.LoadSettings(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load settings: %w", err)
+		}
+		fmt.Fprintln(out, resp.Message)
+		return nil
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(out, settingsUsage)
+		return nil
+	}
+	fmt.Fprint(out, settingsUsage)
+	return fmt.Errorf("unknown settings command %q", args[0])
+}
+
+// exportSettings writes the live settings to a file or stdout
+func exportSettings(ctx context.Context, client api.SettingsAPI, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("settings export", flag.ContinueOnError)
+	fs.SetOutput(out)
+	output := fs.String("o", "", "Output file (.yaml, .yml or .json); stdout if empty")
+	format := fs.String("format", "", "Output format for stdout: yaml or json (default yaml)")
+	secrets := fs.Bool("secrets", false, "Include secrets instead of redacting them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	live, schema, err := fetchSettings(ctx, client, !*secrets)
+	if err != nil {
+		return err
+	}
+	if !*secrets {
+		live = settings.Redact(live, schema)
+	}
+
+	if *output != "" {
+		if err := settings.WriteFile(*output, live); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Exported settings to %s\n", *output)
+		return nil
+	}
+
+	fileFormat := settings.FormatYAML
+	switch *format {
+	case "", "yaml", "yml":
+	case "json":
+		fileFormat = settings.FormatJSON
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	data, err := settings.Marshal(live, fileFormat)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+// diffSettings prints the differences between a file and the live settings
+func diffSettings(ctx context.Context, client api.SettingsAPI, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: riven-tui settings diff <file>")
+	}
+	file, err := settings.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	live, schema, err := fetchSettings(ctx, client, true)
+	if err != nil {
+		return err
+	}
+
+	changes := settings.Diff(live, file)
+	printChanges(out, changes, schema)
+	return nil
+}
+
+// applySettings applies a settings file, or shows what it would change
+func applySettings(ctx context.Context, client api.SettingsAPI, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("settings apply", flag.ContinueOnError)
+	fs.SetOutput(out)
+	dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+	replace := fs.Bool("replace", false, "Send the whole settings tree instead of the changed settings")
+	save := fs.Bool("save", false, "Save the settings to Riven's settings.json afterwards")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: riven-tui settings apply <file> [-dry-run] [-replace] [-save]")
+	}
+
+	file, err := settings.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	live, schema, err := fetchSettings(ctx, client, true)
+	if err != nil {
+		return err
+	}
+
+	changes := settings.Diff(live, file)
+	printChanges(out, changes, schema)
+
+	var requests []api.SetSettingsRequest
+	for _, c := range changes {
+		switch c.Kind {
+		case settings.Unknown:
+			return fmt.Errorf("%s is not a Riven setting; fix the file before applying it", c.Path)
+		case settings.Changed:
+			if err := schema.FieldFor(c.Path, c.Old).Validate(c.New); err != nil {
+				return fmt.Errorf("%s: %w", c.Path, err)
+			}
+			requests = append(requests, api.SetSettingsRequest{Key: c.Path, Value: c.New})
+		}
+	}
+
+	if *dryRun {
+		fmt.Fprintln(out, "Dry run: nothing was applied")
+		return nil
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	ctx = settings.WithDescription(ctx, fmt.Sprintf("Applied %s", fs.Arg(0)))
+	var resp *models.MessageResponse
+	if *replace {
+		resp, err = client.SetAllSettings(api.WithRetry(ctx), settings.Merge(live, file))
+	} else {
+		resp, err = client.SetSettings(api.WithRetry(ctx), requests)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply settings: %w", err)
+	}
+	fmt.Fprintln(out, resp.Message)
+
+	if *save {
+		r, err := client.SaveSettings(ctx)
+		if err != nil {
+			return fmt.Errorf("settings applied but not saved: %w", err)
+		}
+		fmt.Fprintln(out, r.Message)
+	}
+	return nil
+}
+
+// showHistory lists the recorded changes, or shows the changes of one
+func showHistory(ctx context.Context, client api.SettingsAPI, history *settings.History, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		snapshots, err := history.List()
+		if err != nil {
+			return err
+		}
+		if len(snapshots) == 0 {
+			fmt.Fprintf(out, "No settings changes recorded in %s\n", history.Dir())
+			return nil
+		}
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(out, "%s  %s  %s (%d)\n", snapshot.ID, snapshot.Time.Local().Format("2006-01-02 15:04:05"), snapshot.Description, len(snapshot.Before))
+		}
+		return nil
+	}
+
+	snapshot, err := history.Load(args[0])
+	if err != nil {
+		return err
+	}
+	_, schema, err := fetchSettings(ctx, client, true)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s  %s\n", snapshot.Time.Local().Format("2006-01-02 15:04:05"), snapshot.Description)
+	for _, path := range snapshot.Paths() {
+		c := settings.Change{Path: path, Kind: settings.Changed, Old: snapshot.Before[path], New: snapshot.After[path]}
+		fmt.Fprintln(out, settings.FormatChange(c, schema))
+	}
+	return nil
+}
+
+// rollbackSettings restores the values a recorded change replaced
+func rollbackSettings(ctx context.Context, client api.SettingsAPI, history *settings.History, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("settings rollback", flag.ContinueOnError)
+	fs.SetOutput(out)
+	dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: riven-tui settings rollback <id> [-dry-run]")
+	}
+
+	snapshot, err := history.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	live, schema, err := fetchSettings(ctx, client, true)
+	if err != nil {
+		return err
+	}
+
+	requests := settings.Rollback(*snapshot)
+	for _, r := range requests {
+		current, _ := settings.Lookup(live, r.Key)
+		fmt.Fprintln(out, settings.FormatChange(settings.Change{Path: r.Key, Kind: settings.Changed, Old: current, New: r.Value}, schema))
+	}
+	if len(requests) == 0 {
+		fmt.Fprintln(out, "Nothing to roll back: the settings did not exist before")
+		return nil
+	}
+	if *dryRun {
+		fmt.Fprintln(out, "Dry run: nothing was applied")
+		return nil
+	}
+
+	ctx = settings.WithDescription(api.WithRetry(ctx), fmt.Sprintf("Rollback of %s (%s)", snapshot.ID, snapshot.Description))
+	resp, err := client.SetSettings(ctx, requests)
+	if err != nil {
+		return fmt.Errorf("failed to roll back settings: %w", err)
+	}
+	fmt.Fprintln(out, resp.Message)
+	return nil
+}
+
+// fetchSettings fetches the live settings, and the schema when needed to
+// recognise secrets or validate values
+func fetchSettings(ctx context.Context, client api.SettingsAPI, withSchema bool) (map[string]interface{}, *settings.Schema, error) {
+	raw, err := client.GetAllSettings(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch settings: %w", err)
+	}
+	live, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected settings format")
+	}
+
+	schema := settings.ParseSchema(nil)
+	if withSchema {
+		// Without the schema secrets are recognised by name
+		if raw, err := client.GetSettingsSchema(ctx); err == nil {
+			schema = settings.ParseSchema(raw)
+		}
+	}
+	return live, schema, nil
+}
+
+// printChanges prints a diff, changes first. Settings missing from the
+// file are only counted, since files often hold a subset.
+func printChanges(out io.Writer, changes []settings.Change, schema *settings.Schema) {
+	order := map[settings.ChangeKind]int{settings.Changed: 0, settings.Unknown: 1}
+	sort.SliceStable(changes, func(i, j int) bool { return order[changes[i].Kind] < order[changes[j].Kind] })
+
+	counts := map[settings.ChangeKind]int{}
+	for _, c := range changes {
+		counts[c.Kind]++
+		if c.Kind != settings.Missing {
+			fmt.Fprintln(out, settings.FormatChange(c, schema))
+		}
+	}
+	fmt.Fprintf(out, "%d changed, %d unknown, %d not in file\n", counts[settings.Changed], counts[settings.Unknown], counts[settings.Missing])
+}
+
+// reorderArgs moves flags before positional arguments, so that
+// "apply file.yaml -dry-run" works like "apply -dry-run file.yaml"
+func reorderArgs(args []string) []string {
+	var flags, positional []string
+	for _, arg := range args {
+		if len(arg) > 1 && arg[0] == '-' {
+			flags = append(flags, arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/fakeriven"
+	"riven-tui/pkg/models"
+	"riven-tui/pkg/settings"
+)
+
+// writeCounter records the settings writes that reach the server, and how
+// many snapshots the history held when each was sent
+type writeCounter struct {
+	api.SettingsAPI
+	history   *settings.History
+	writes    []string
+	snapshots []int
+	all       map[string]interface{}
+}
+
+func (w *writeCounter) SetSettings(ctx context.Context, requests []api.SetSettingsRequest) (*models.MessageResponse, error) {
+	w.record("set")
+	return w.SettingsAPI.SetSettings(ctx, requests)
+}
+
+func (w *writeCounter) SetAllSettings(ctx context.Context, values map[string]interface{}) (*models.MessageResponse, error) {
+	w.record("set all")
+	w.all = values
+	return w.SettingsAPI.SetAllSettings(ctx, values)
+}
+
+func (w *writeCounter) record(kind string) {
+	snapshots, _ := w.history.List()
+	w.writes = append(w.writes, kind)
+	w.snapshots = append(w.snapshots, len(snapshots))
+}
+
+// newSettingsTest returns a client of a demo server, counting its writes,
+// and an empty history
+func newSettingsTest(t *testing.T) *writeCounter {
+	t.Helper()
+
+	srv := httptest.NewServer(fakeriven.New(fakeriven.WithToken("cli")))
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.API.Endpoint = srv.URL
+	cfg.API.Token = "cli"
+	return &writeCounter{SettingsAPI: api.NewClient(cfg), history: settings.NewHistory(t.TempDir(), 0)}
+}
+
+// writeSettingsFile writes a settings file raising the Jackett timeout to
+// 30, with the Plex token redacted
+func writeSettingsFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	data := `{"scraping": {"jackett": {"timeout": 30}}, "updaters": {"plex": {"token": "` + settings.Mask + `"}}}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// liveSetting returns a setting of the demo server
+func liveSetting(t *testing.T, client api.SettingsAPI, path string) interface{} {
+	t.Helper()
+	live, _, err := fetchSettings(context.Background(), client, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, _ := settings.Lookup(live, path)
+	return value
+}
+
+func TestSettingsApplyDryRun(t *testing.T) {
+	client := newSettingsTest(t)
+	if err := runSettings(client, client.history, []string{"apply", writeSettingsFile(t), "-dry-run"}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.writes) != 0 {
+		t.Errorf("expected a dry run to send nothing, got %v", client.writes)
+	}
+	if got := liveSetting(t, client, "scraping.jackett.timeout"); got != float64(10) {
+		t.Errorf("expected the timeout to be unchanged, got %v", got)
+	}
+}
+
+func TestSettingsApplyAndRollback(t *testing.T) {
+	client := newSettingsTest(t)
+	if err := runSettings(client, client.history, []string{"apply", writeSettingsFile(t)}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.writes) != 1 || client.writes[0] != "set" || client.snapshots[0] != 1 {
+		t.Fatalf("expected one write, recorded in the history first, got %v with %v snapshots", client.writes, client.snapshots)
+	}
+	if got := liveSetting(t, client, "scraping.jackett.timeout"); got != float64(30) {
+		t.Fatalf("expected the timeout to be applied, got %v", got)
+	}
+
+	snapshots, _ := client.history.List()
+	if len(snapshots) != 1 || snapshots[0].Before["scraping.jackett.timeout"] != float64(10) {
+		t.Fatalf("expected the previous timeout in the history, got %+v", snapshots)
+	}
+	if err := runSettings(client, client.history, []string{"rollback", snapshots[0].ID}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if got := liveSetting(t, client, "scraping.jackett.timeout"); got != float64(10) {
+		t.Errorf("expected the rollback to restore the timeout, got %v", got)
+	}
+}
+
+func TestSettingsApplyReplaceKeepsSecrets(t *testing.T) {
+	client := newSettingsTest(t)
+	if err := runSettings(client, client.history, []string{"apply", "-replace", writeSettingsFile(t)}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.writes) != 1 || client.writes[0] != "set all" {
+		t.Fatalf("expected the whole tree to be sent, got %v", client.writes)
+	}
+	if token, _ := settings.Lookup(client.all, "updaters.plex.token"); token != "plex-demo-token" {
+		t.Errorf("expected the redacted token to keep its live value, got %v", token)
+	}
+	if timeout, _ := settings.Lookup(client.all, "scraping.jackett.timeout"); timeout != float64(30) {
+		t.Errorf("expected the new timeout in the tree, got %v", timeout)
+	}
+	if got := liveSetting(t, client, "updaters.plex.token"); got != "plex-demo-token" {
+		t.Errorf("expected the live token to be kept, got %v", got)
+	}
+}
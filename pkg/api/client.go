@@ -1,7 +1,6 @@
 package api
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -21,6 +20,10 @@ type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+	// streamClient has no overall timeout so long-lived event streams are
+	// not cut off; see openEventStream
+	streamClient *http.Client
+	retry        RetryPolicy
 }
 
 // NewClient creates a new Riven API client
@@ -31,36 +34,71 @@ func NewClient(cfg *config.Config) *Client {
 		httpClient: &http.Client{
 			Timeout: cfg.API.Timeout,
 		},
+		streamClient: &http.Client{},
+		retry:        NewRetryPolicy(cfg.API.Retry),
 	}
 }
 
-// doRequest performs an HTTP request with authentication
+// doRequest performs an HTTP request with authentication, retrying
+// connection errors and transient statuses according to the retry policy
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	attempts := 1
+	if retryAllowed(ctx, method) {
+		attempts = c.retry.MaxAttempts
 	}
 
-	// Add authentication header
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	for attempt := 1; ; attempt++ {
+		var reqBody io.Reader
+		if jsonData != nil {
+			reqBody = bytes.NewReader(jsonData)
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Add authentication header
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if attempt >= attempts || !retryableError(ctx, err) {
+				return nil, fmt.Errorf("request failed: %w", err)
+			}
+		} else {
+			if attempt >= attempts || !retryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+		}
+
+		delay := c.retry.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				delay = after
+				if c.retry.MaxDelay > 0 && delay > c.retry.MaxDelay {
+					delay = c.retry.MaxDelay
+				}
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
 
-	return resp, nil
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+	}
 }
 
 // parseResponse parses the HTTP response into the target struct
@@ -68,8 +106,7 @@ func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return newAPIError(resp)
 	}
 
 	if target == nil {
@@ -234,7 +271,7 @@ func (c *Client) GetItems(ctx context.Context, params *ItemsParams) (*models.Ite
 }
 
 // GetItem gets a single media item by ID
-func (c *Client) GetItem(ctx context.Context, id string, mediaType *models.MediaType, withStreams *bool) (map[string]interface{}, error) {
+func (c *Client) GetItem(ctx context.Context, id string, mediaType *models.MediaType, withStreams *bool) (*models.MediaItem, error) {
 	path := fmt.Sprintf("/api/v1/items/%s", id)
 
 	query := url.Values{}
@@ -254,9 +291,9 @@ func (c *Client) GetItem(ctx context.Context, id string, mediaType *models.Media
 		return nil, err
 	}
 
-	var result map[string]interface{}
+	var result models.MediaItem
 	err = c.parseResponse(resp, &result)
-	return result, err
+	return &result, err
 }
 
 // AddItems adds media items by TMDB or TVDB IDs
@@ -359,7 +396,7 @@ func (c *Client) UnpauseItems(ctx context.Context, ids string) (*models.PauseRes
 }
 
 // GetItemsByIMDBIds gets media items by IMDB IDs
-func (c *Client) GetItemsByIMDBIds(ctx context.Context, imdbIds string) ([]map[string]interface{}, error) {
+func (c *Client) GetItemsByIMDBIds(ctx context.Context, imdbIds string) ([]models.MediaItem, error) {
 	path := fmt.Sprintf("/api/v1/items/imdb/%s", url.PathEscape(imdbIds))
 
 	resp, err := c.doRequest(ctx, "GET", path, nil)
@@ -367,7 +404,7 @@ func (c *Client) GetItemsByIMDBIds(ctx context.Context, imdbIds string) ([]map[s
 		return nil, err
 	}
 
-	var result []map[string]interface{}
+	var result []models.MediaItem
 	err = c.parseResponse(resp, &result)
 	return result, err
 }
@@ -431,7 +468,7 @@ func (c *Client) UpdateNewReleases(ctx context.Context, params *UpdateNewRelease
 }
 
 // GetItemStreams gets streams for a specific item
-func (c *Client) GetItemStreams(ctx context.Context, itemID int) (interface{}, error) {
+func (c *Client) GetItemStreams(ctx context.Context, itemID int) (*models.ItemStreamsResponse, error) {
 	path := fmt.Sprintf("/api/v1/items/%d/streams", itemID)
 
 	resp, err := c.doRequest(ctx, "GET", path, nil)
@@ -439,13 +476,13 @@ func (c *Client) GetItemStreams(ctx context.Context, itemID int) (interface{}, e
 		return nil, err
 	}
 
-	var result interface{}
+	var result models.ItemStreamsResponse
 	err = c.parseResponse(resp, &result)
-	return result, err
+	return &result, err
 }
 
 // BlacklistStream blacklists a stream for an item
-func (c *Client) BlacklistStream(ctx context.Context, itemID, streamID int) (interface{}, error) {
+func (c *Client) BlacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error) {
 	path := fmt.Sprintf("/api/v1/items/%d/streams/%d/blacklist", itemID, streamID)
 
 	resp, err := c.doRequest(ctx, "POST", path, nil)
@@ -453,13 +490,13 @@ func (c *Client) BlacklistStream(ctx context.Context, itemID, streamID int) (int
 		return nil, err
 	}
 
-	var result interface{}
+	var result models.MessageResponse
 	err = c.parseResponse(resp, &result)
-	return result, err
+	return &result, err
 }
 
 // UnblacklistStream removes a stream from blacklist for an item
-func (c *Client) UnblacklistStream(ctx context.Context, itemID, streamID int) (interface{}, error) {
+func (c *Client) UnblacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error) {
 	path := fmt.Sprintf("/api/v1/items/%d/streams/%d/unblacklist", itemID, streamID)
 
 	resp, err := c.doRequest(ctx, "POST", path, nil)
@@ -467,13 +504,13 @@ func (c *Client) UnblacklistStream(ctx context.Context, itemID, streamID int) (i
 		return nil, err
 	}
 
-	var result interface{}
+	var result models.MessageResponse
 	err = c.parseResponse(resp, &result)
-	return result, err
+	return &result, err
 }
 
 // ResetItemStreams resets all streams for a media item
-func (c *Client) ResetItemStreams(ctx context.Context, itemID int) (interface{}, error) {
+func (c *Client) ResetItemStreams(ctx context.Context, itemID int) (*models.MessageResponse, error) {
 	path := fmt.Sprintf("/api/v1/items/%d/streams/reset", itemID)
 
 	resp, err := c.doRequest(ctx, "POST", path, nil)
@@ -481,9 +518,9 @@ func (c *Client) ResetItemStreams(ctx context.Context, itemID int) (interface{},
 		return nil, err
 	}
 
-	var result interface{}
+	var result models.MessageResponse
 	err = c.parseResponse(resp, &result)
-	return result, err
+	return &result, err
 }
 
 // ReindexParams represents parameters for reindexing items
@@ -904,48 +941,24 @@ func (c *Client) StreamEvents(ctx context.Context, eventType string) (*models.St
 	return &result, err
 }
 
-// StreamEventsSSE streams real-time events using Server-Sent Events
+// StreamEventsSSE streams real-time events using Server-Sent Events. It
+// reconnects like SubscribeEvents and blocks until the context is cancelled
+// or the server rejects the stream. Undecodable events are skipped; use
+// SubscribeEvents to observe them and the connection state.
 func (c *Client) StreamEventsSSE(ctx context.Context, eventChan chan<- models.Event) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/events/stream", nil)
-	if err != nil {
-		return err
+	stream := c.SubscribeEvents(ctx, nil)
+	for event := range stream.Events() {
+		select {
+		case eventChan <- event:
+		case <-ctx.Done():
+		}
 	}
+	<-stream.Done()
 
-	// Add authentication header
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	if err := stream.Err(); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("event stream failed with status: %d", resp.StatusCode)
-	}
-
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Parse SSE format
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
-
-			var event models.Event
-			if err := json.Unmarshal([]byte(data), &event); err == nil {
-				select {
-				case eventChan <- event:
-				case <-ctx.Done():
-					return ctx.Err()
-				}
-			}
-		}
-	}
-
-	return scanner.Err()
+	return ctx.Err()
 }
 
 // Webhook endpoints
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/fakeriven"
+	"riven-tui/pkg/models"
+)
+
+// newFakeClient returns a client talking to a fresh fake Riven server
+func newFakeClient(t *testing.T, opts ...fakeriven.Option) (*Client, *fakeriven.Server) {
+	t.Helper()
+
+	fake := fakeriven.New(append([]fakeriven.Option{fakeriven.WithToken("e2e")}, opts...)...)
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.API.Endpoint = srv.URL
+	cfg.API.Token = "e2e"
+	cfg.API.Timeout = 5 * time.Second
+	return NewClient(cfg), fake
+}
+
+// findItemID returns the ID of the first item matching the search
+func findItemID(t *testing.T, client *Client, search string) string {
+	t.Helper()
+
+	resp, err := client.GetItems(context.Background(), &ItemsParams{Search: &search})
+	if err != nil {
+		t.Fatalf("GetItems(%q) failed: %v", search, err)
+	}
+	if len(resp.Items) == 0 {
+		t.Fatalf("GetItems(%q) returned no items", search)
+	}
+	return resp.Items[0].ID
+}
+
+func TestE2EUnauthorized(t *testing.T) {
+	client, _ := newFakeClient(t)
+	client.token = "wrong"
+
+	_, err := client.GetStats(context.Background())
+	if !IsUnauthorized(err) {
+		t.Fatalf("Expected unauthorized error, got %v", err)
+	}
+}
+
+func TestE2EItemsPaging(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	limit, page := 5, 2
+	resp, err := client.GetItems(ctx, &ItemsParams{Limit: &limit, Page: &page})
+	if err != nil {
+		t.Fatalf("GetItems failed: %v", err)
+	}
+	if resp.Page != 2 || len(resp.Items) != 5 {
+		t.Errorf("Expected 5 items on page 2, got %d on page %d", len(resp.Items), resp.Page)
+	}
+	if resp.TotalPages != (resp.TotalItems+4)/5 {
+		t.Errorf("Inconsistent paging: %d items over %d pages", resp.TotalItems, resp.TotalPages)
+	}
+
+	limit = 0
+	if _, err := client.GetItems(ctx, &ItemsParams{Limit: &limit}); !IsValidation(err) {
+		t.Errorf("Expected validation error for limit=0, got %v", err)
+	}
+}
+
+func TestE2EItemLifecycle(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	id := findItemID(t, client, "Matrix")
+
+	extended := true
+	item, err := client.GetItem(ctx, id, nil, &extended)
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	if item.Title != "The Matrix" {
+		t.Errorf("Expected The Matrix, got %v", item.Title)
+	}
+
+	if _, err := client.PauseItems(ctx, id); err != nil {
+		t.Fatalf("PauseItems failed: %v", err)
+	}
+	item, _ = client.GetItem(ctx, id, nil, nil)
+	if item.State != models.StatePaused {
+		t.Errorf("Expected Paused state, got %v", item.State)
+	}
+
+	if _, err := client.RemoveItems(ctx, id); err != nil {
+		t.Fatalf("RemoveItems failed: %v", err)
+	}
+	if _, err := client.GetItem(ctx, id, nil, nil); !IsNotFound(err) {
+		t.Errorf("Expected not found after removal, got %v", err)
+	}
+}
+
+func TestE2EItemStreams(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	id, _ := strconv.Atoi(findItemID(t, client, "Matrix"))
+	streams, err := client.GetItemStreams(ctx, id)
+	if err != nil {
+		t.Fatalf("GetItemStreams failed: %v", err)
+	}
+	if len(streams.Streams) == 0 || streams.Streams[0].ParsedData.Resolution == "" {
+		t.Fatalf("Expected parsed streams, got %+v", streams)
+	}
+
+	stream := streams.Streams[0]
+	if _, err := client.BlacklistStream(ctx, id, stream.ID); err != nil {
+		t.Fatalf("BlacklistStream failed: %v", err)
+	}
+	streams, _ = client.GetItemStreams(ctx, id)
+	if len(streams.BlacklistedStreams) != 1 || streams.BlacklistedStreams[0].ID != stream.ID {
+		t.Errorf("Expected stream %d to be blacklisted, got %+v", stream.ID, streams.BlacklistedStreams)
+	}
+
+	if _, err := client.UnblacklistStream(ctx, id, stream.ID); err != nil {
+		t.Fatalf("UnblacklistStream failed: %v", err)
+	}
+	if _, err := client.ResetItemStreams(ctx, id); err != nil {
+		t.Fatalf("ResetItemStreams failed: %v", err)
+	}
+	streams, _ = client.GetItemStreams(ctx, id)
+	if len(streams.BlacklistedStreams) != 0 {
+		t.Errorf("Expected no blacklisted streams after reset, got %d", len(streams.BlacklistedStreams))
+	}
+}
+
+func TestE2ESettings(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	_, err := client.SetSettings(ctx, []SetSettingsRequest{{Key: "debug", Value: "yes"}})
+	if !IsValidation(err) {
+		t.Fatalf("Expected validation error for wrong type, got %v", err)
+	}
+
+	if _, err := client.SetSettings(ctx, []SetSettingsRequest{{Key: "debug", Value: true}}); err != nil {
+		t.Fatalf("SetSettings failed: %v", err)
+	}
+	values, err := client.GetSettings(ctx, "debug")
+	if err != nil {
+		t.Fatalf("GetSettings failed: %v", err)
+	}
+	if values["debug"] != true {
+		t.Errorf("Expected debug=true, got %v", values["debug"])
+	}
+
+	// Loading discards unsaved changes
+	if _, err := client.LoadSettings(ctx); err != nil {
+		t.Fatalf("LoadSettings failed: %v", err)
+	}
+	values, _ = client.GetSettings(ctx, "debug")
+	if values["debug"] != false {
+		t.Errorf("Expected debug to be restored, got %v", values["debug"])
+	}
+}
+
+func TestE2EManualScrape(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	id := findItemID(t, client, "Arrival")
+	magnet := "magnet:?xt=urn:btih:" + "0123456789abcdef0123456789abcdef01234567"
+
+	session, err := client.StartManualSession(ctx, &StartManualSessionParams{ItemID: &id, Magnet: &magnet})
+	if err != nil {
+		t.Fatalf("StartManualSession failed: %v", err)
+	}
+	if session.Containers == nil || len(session.Containers.Files) == 0 {
+		t.Fatal("Expected torrent files in the session")
+	}
+
+	if _, err := client.CompleteManualSession(ctx, session.SessionID); err == nil {
+		t.Error("Expected completing an empty session to fail")
+	}
+
+	file := session.Containers.Files[0]
+	container := models.Container{InfoHash: session.Containers.InfoHash, Files: []models.DebridFile{file}}
+	if _, err := client.SelectFiles(ctx, session.SessionID, container); err != nil {
+		t.Fatalf("SelectFiles failed: %v", err)
+	}
+	if _, err := client.UpdateAttributes(ctx, session.SessionID, file); err != nil {
+		t.Fatalf("UpdateAttributes failed: %v", err)
+	}
+	if _, err := client.CompleteManualSession(ctx, session.SessionID); err != nil {
+		t.Fatalf("CompleteManualSession failed: %v", err)
+	}
+
+	item, _ := client.GetItem(ctx, id, nil, nil)
+	if item.State != models.StateDownloaded {
+		t.Errorf("Expected Downloaded state, got %v", item.State)
+	}
+	if _, err := client.AbortManualSession(ctx, session.SessionID); !IsNotFound(err) {
+		t.Errorf("Expected completed session to be gone, got %v", err)
+	}
+}
+
+func TestE2EEventStream(t *testing.T) {
+	client, fake := newFakeClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan models.Event, 16)
+	go client.StreamEventsSSE(ctx, events)
+
+	// Wait for the stream to connect before publishing
+	for fake.Subscribers() == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("Event stream never connected")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	fake.Publish("item_update", "test", map[string]interface{}{"item_id": "1"})
+
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == "item_update" && ev.Data["item_id"] == "1" {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("Did not receive the published event")
+		}
+	}
+}
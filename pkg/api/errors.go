@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"riven-tui/pkg/models"
+)
+
+// APIError represents a non-2xx response returned by the Riven API
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Body       []byte
+	RequestID  string
+
+	// Detail holds the "detail" message when the API returns a plain string
+	Detail string
+	// Validation holds the field-level errors of a 422 response
+	Validation []models.ValidationError
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var msg string
+	switch {
+	case len(e.Validation) > 0:
+		msg = strings.Join(e.FieldMessages(), "; ")
+	case e.Detail != "":
+		msg = e.Detail
+	default:
+		msg = strings.TrimSpace(string(e.Body))
+	}
+
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	return fmt.Sprintf("API error (status %d) on %s %s: %s", e.StatusCode, e.Method, e.Path, msg)
+}
+
+// FieldMessages returns one human readable line per validation error
+func (e *APIError) FieldMessages() []string {
+	var messages []string
+	for _, v := range e.Validation {
+		field := FieldPath(v)
+		if field == "" {
+			messages = append(messages, v.Msg)
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", field, v.Msg))
+	}
+	return messages
+}
+
+// FieldPath joins the location of a validation error into a dotted path,
+// dropping the leading "body"/"query"/"path" segment added by the server
+func FieldPath(v models.ValidationError) string {
+	var parts []string
+	for i, loc := range v.Loc {
+		part := fmt.Sprintf("%v", loc)
+		if i == 0 && len(v.Loc) > 1 {
+			switch part {
+			case "body", "query", "path", "header":
+				continue
+			}
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// newAPIError builds an APIError from an HTTP response, decoding the
+// validation payload when the server returned one
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiErr.Path = resp.Request.URL.Path
+		}
+	}
+
+	// FastAPI uses a list for validation errors and a string for everything else
+	var payload struct {
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || len(payload.Detail) == 0 {
+		return apiErr
+	}
+
+	var validation models.HTTPValidationError
+	if err := json.Unmarshal(body, &validation); err == nil && len(validation.Detail) > 0 {
+		apiErr.Validation = validation.Detail
+		return apiErr
+	}
+
+	var detail string
+	if err := json.Unmarshal(payload.Detail, &detail); err == nil {
+		apiErr.Detail = detail
+	}
+
+	return apiErr
+}
+
+// AsAPIError returns the APIError wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// hasStatus reports whether err is an APIError with one of the given status codes
+func hasStatus(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 or 403 response
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsValidation reports whether err is a 422 response or carries validation details
+func IsValidation(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusUnprocessableEntity || len(apiErr.Validation) > 0
+}
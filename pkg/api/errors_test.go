@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"riven-tui/pkg/config"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(&config.Config{
+		API: config.APIConfig{
+			Endpoint: server.URL,
+			Token:    "test-token",
+			Timeout:  5 * time.Second,
+		},
+	})
+}
+
+func TestAPIErrorValidation(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-42")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"detail":[{"loc":["query","ids"],"msg":"field required","type":"value_error.missing"}]}`))
+	})
+
+	_, err := client.RetryItems(context.Background(), "")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if !IsValidation(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if IsNotFound(err) || IsUnauthorized(err) {
+		t.Errorf("validation error matched the wrong sentinel: %v", err)
+	}
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Method != "POST" || apiErr.Path != "/api/v1/items/retry" {
+		t.Errorf("unexpected request info: %s %s", apiErr.Method, apiErr.Path)
+	}
+	if apiErr.RequestID != "req-42" {
+		t.Errorf("expected request ID req-42, got %q", apiErr.RequestID)
+	}
+
+	messages := apiErr.FieldMessages()
+	if len(messages) != 1 || messages[0] != "ids: field required" {
+		t.Errorf("unexpected field messages: %v", messages)
+	}
+}
+
+func TestAPIErrorStatusHelpers(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		notFound     bool
+		unauthorized bool
+		detail       string
+	}{
+		{
+			name:     "not found with detail",
+			status:   http.StatusNotFound,
+			body:     `{"detail":"Item not found"}`,
+			notFound: true,
+			detail:   "Item not found",
+		},
+		{
+			name:         "unauthorized",
+			status:       http.StatusUnauthorized,
+			body:         `{"detail":"Invalid token"}`,
+			unauthorized: true,
+			detail:       "Invalid token",
+		},
+		{
+			name:   "plain text body",
+			status: http.StatusInternalServerError,
+			body:   "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := client.GetStats(context.Background())
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if IsNotFound(err) != tt.notFound {
+				t.Errorf("IsNotFound = %v, want %v", IsNotFound(err), tt.notFound)
+			}
+			if IsUnauthorized(err) != tt.unauthorized {
+				t.Errorf("IsUnauthorized = %v, want %v", IsUnauthorized(err), tt.unauthorized)
+			}
+
+			apiErr, _ := AsAPIError(err)
+			if apiErr.Detail != tt.detail {
+				t.Errorf("expected detail %q, got %q", tt.detail, apiErr.Detail)
+			}
+			if !strings.Contains(err.Error(), "status") {
+				t.Errorf("error message should mention the status: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+const (
+	// defaultReconnectDelay is used when neither the server nor the retry
+	// configuration provide a delay
+	defaultReconnectDelay = time.Second
+	// defaultMaxReconnectDelay caps the reconnect backoff when the retry
+	// configuration has no maximum
+	defaultMaxReconnectDelay = 30 * time.Second
+)
+
+// StreamState is the connection state of an EventStream
+type StreamState int
+
+const (
+	StreamConnecting StreamState = iota
+	StreamConnected
+	StreamDisconnected
+	StreamClosed
+)
+
+// String returns the display name of the state
+func (s StreamState) String() string {
+	switch s {
+	case StreamConnecting:
+		return "connecting"
+	case StreamConnected:
+		return "connected"
+	case StreamDisconnected:
+		return "disconnected"
+	case StreamClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// EventStreamOptions configures SubscribeEvents
+type EventStreamOptions struct {
+	// LastEventID resumes a previous stream after the given event
+	LastEventID string
+	// OnStateChange is called from the stream goroutine whenever the
+	// connection state changes. err is the reason for a disconnect or close.
+	OnStateChange func(state StreamState, err error)
+	// IdleTimeout drops and reopens the connection when nothing, not even a
+	// keep-alive comment, arrives for this long. Zero disables the check.
+	IdleTimeout time.Duration
+	// MaxReconnectDelay caps the reconnect backoff. Zero uses the retry
+	// configuration's maximum delay.
+	MaxReconnectDelay time.Duration
+	// BufferSize is the capacity of the events channel (default 64)
+	BufferSize int
+}
+
+// EventDecodeError reports an event whose payload could not be decoded
+type EventDecodeError struct {
+	Event SSEEvent
+	Err   error
+}
+
+// Error implements error
+func (e *EventDecodeError) Error() string {
+	return fmt.Sprintf("failed to decode %q event %s: %v", e.Event.Event, e.Event.ID, e.Err)
+}
+
+// Unwrap returns the underlying decode error
+func (e *EventDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// EventStream is a live, automatically reconnecting subscription to the
+// Riven event stream
+type EventStream struct {
+	client *Client
+	opts   EventStreamOptions
+
+	events chan models.Event
+	errors chan error
+	done   chan struct{}
+
+	mu          sync.Mutex
+	state       StreamState
+	lastEventID string
+	retry       time.Duration
+	err         error
+}
+
+// SubscribeEvents opens the event stream in the background. It reconnects
+// with backoff, resuming from the last received event, until the context is
+// cancelled or the server rejects the request (401, 403 or 404).
+func (c *Client) SubscribeEvents(ctx context.Context, opts *EventStreamOptions) *EventStream {
+	s := &EventStream{client: c}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.BufferSize <= 0 {
+		s.opts.BufferSize = 64
+	}
+	s.lastEventID = s.opts.LastEventID
+	s.events = make(chan models.Event, s.opts.BufferSize)
+	s.errors = make(chan error, 16)
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	return s
+}
+
+// Events returns the decoded events. It is closed when the stream ends.
+func (s *EventStream) Events() <-chan models.Event {
+	return s.events
+}
+
+// Errors returns decode errors and the final error of the stream. Errors are
+// dropped when nobody reads them. It is closed when the stream ends.
+func (s *EventStream) Errors() <-chan error {
+	return s.errors
+}
+
+// Done is closed when the stream has ended
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the reason the stream ended, nil if the context was cancelled
+func (s *EventStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// State returns the current connection state
+func (s *EventStream) State() StreamState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// LastEventID returns the ID of the last event received
+func (s *EventStream) LastEventID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID
+}
+
+// run keeps the stream connected until it is cancelled or rejected
+func (s *EventStream) run(ctx context.Context) {
+	defer func() {
+		close(s.events)
+		close(s.errors)
+		close(s.done)
+	}()
+
+	failures := 0
+	for {
+		s.setState(StreamConnecting, nil)
+		received, err := s.consume(ctx)
+
+		if ctx.Err() != nil {
+			s.setState(StreamClosed, nil)
+			return
+		}
+		if permanentStreamError(err) {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			s.report(err)
+			s.setState(StreamClosed, err)
+			return
+		}
+
+		if received {
+			failures = 0
+		}
+		failures++
+
+		if err == nil {
+			err = io.EOF
+		}
+		s.setState(StreamDisconnected, err)
+		if sleep(ctx, s.reconnectDelay(failures)) != nil {
+			s.setState(StreamClosed, nil)
+			return
+		}
+	}
+}
+
+// consume reads one connection until it ends. received reports whether any
+// event arrived, which resets the reconnect backoff.
+func (s *EventStream) consume(ctx context.Context) (received bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resp, err := s.client.openEventStream(ctx, s.LastEventID())
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	s.setState(StreamConnected, nil)
+
+	var body io.Reader = resp.Body
+	if s.opts.IdleTimeout > 0 {
+		idle := newIdleReader(resp.Body, s.opts.IdleTimeout, cancel)
+		defer idle.stop()
+		body = idle
+	}
+
+	// The last event ID carries over reconnects, so events without an id
+	// field keep the one the stream resumes from
+	reader := NewSSEReader(body)
+	reader.lastEventID = s.LastEventID()
+	for {
+		raw, err := reader.Next()
+		if retry := reader.Retry(); retry > 0 {
+			s.mu.Lock()
+			s.retry = retry
+			s.mu.Unlock()
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) && ctx.Err() != nil && s.opts.IdleTimeout > 0 {
+				err = fmt.Errorf("no data for %s", s.opts.IdleTimeout)
+			}
+			return received, err
+		}
+
+		s.mu.Lock()
+		s.lastEventID = raw.ID
+		s.mu.Unlock()
+		received = true
+
+		event, err := decodeEvent(raw)
+		if err != nil {
+			s.report(err)
+			continue
+		}
+
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return received, ctx.Err()
+		}
+	}
+}
+
+// reconnectDelay returns the jittered delay before the given reconnect
+func (s *EventStream) reconnectDelay(failures int) time.Duration {
+	s.mu.Lock()
+	base := s.retry
+	s.mu.Unlock()
+
+	if base <= 0 {
+		base = s.client.retry.InitialDelay
+	}
+	if base <= 0 {
+		base = defaultReconnectDelay
+	}
+
+	max := s.opts.MaxReconnectDelay
+	if max <= 0 {
+		max = s.client.retry.MaxDelay
+	}
+	if max <= 0 {
+		max = defaultMaxReconnectDelay
+	}
+	if base > max {
+		base = max
+	}
+
+	return RetryPolicy{InitialDelay: base, MaxDelay: max}.backoff(failures)
+}
+
+// setState records and announces a state change
+func (s *EventStream) setState(state StreamState, err error) {
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+
+	if s.opts.OnStateChange != nil {
+		s.opts.OnStateChange(state, err)
+	}
+}
+
+// report sends an error without blocking the stream
+func (s *EventStream) report(err error) {
+	select {
+	case s.errors <- err:
+	default:
+	}
+}
+
+// permanentStreamError reports whether reconnecting cannot help
+func permanentStreamError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// decodeEvent converts an SSE event into a Riven event. Payloads that are
+// not shaped like models.Event become its Data.
+func decodeEvent(raw *SSEEvent) (models.Event, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw.Data), &fields); err != nil {
+		return models.Event{}, &EventDecodeError{Event: *raw, Err: err}
+	}
+
+	var event models.Event
+	_, hasType := fields["type"]
+	_, hasData := fields["data"]
+	if hasType || hasData {
+		if err := json.Unmarshal([]byte(raw.Data), &event); err != nil {
+			return models.Event{}, &EventDecodeError{Event: *raw, Err: err}
+		}
+	} else {
+		json.Unmarshal([]byte(raw.Data), &event.Data)
+	}
+
+	if event.Type == "" {
+		event.Type = raw.Event
+	}
+	event.ID = raw.ID
+	return event, nil
+}
+
+// openEventStream connects to the event stream. The configured timeout only
+// applies until the response headers arrive; the stream itself is unbounded.
+func (c *Client) openEventStream(ctx context.Context, lastEventID string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/v1/events/stream", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var timedOut atomic.Bool
+	if c.httpClient.Timeout > 0 {
+		timer := time.AfterFunc(c.httpClient.Timeout, func() {
+			timedOut.Store(true)
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	resp, err := c.streamClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		if timedOut.Load() {
+			return nil, fmt.Errorf("event stream did not respond within %s", c.httpClient.Timeout)
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected event stream content type %q", mediaType)
+	}
+
+	return resp, nil
+}
+
+// cancelBody releases the request context when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// idleReader cancels the connection when no data is read for a while
+type idleReader struct {
+	r       io.Reader
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func newIdleReader(r io.Reader, timeout time.Duration, cancel context.CancelFunc) *idleReader {
+	return &idleReader{r: r, timeout: timeout, timer: time.AfterFunc(timeout, cancel)}
+}
+
+// Read implements io.Reader
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+func (r *idleReader) stop() {
+	r.timer.Stop()
+}
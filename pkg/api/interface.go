@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+
+	"riven-tui/pkg/models"
+)
+
+// SystemAPI covers health, statistics and other instance-wide endpoints
+type SystemAPI interface {
+	Health(ctx context.Context) (*models.MessageResponse, error)
+	GetRoot(ctx context.Context) (*models.RootResponse, error)
+	GetStats(ctx context.Context) (*models.StatsResponse, error)
+	GetServices(ctx context.Context) (models.ServicesResponse, error)
+	GetLogs(ctx context.Context) (*models.LogsResponse, error)
+	GetEvents(ctx context.Context) (*models.EventResponse, error)
+	GetRDUser(ctx context.Context) (*models.RDUser, error)
+	GenerateAPIKey(ctx context.Context) (*models.MessageResponse, error)
+	GetMount(ctx context.Context) (*models.MountResponse, error)
+	UploadLogs(ctx context.Context) (*models.UploadLogsResponse, error)
+	GetCalendar(ctx context.Context) (*models.CalendarResponse, error)
+	InitiateTraktOAuth(ctx context.Context) (*models.TraktOAuthInitiateResponse, error)
+	TraktOAuthCallback(ctx context.Context, code string) (*models.MessageResponse, error)
+}
+
+// ItemsAPI covers the media item and item stream endpoints
+type ItemsAPI interface {
+	GetStates(ctx context.Context) (*models.StateResponse, error)
+	GetItems(ctx context.Context, params *ItemsParams) (*models.ItemsResponse, error)
+	GetItem(ctx context.Context, id string, mediaType *models.MediaType, withStreams *bool) (*models.MediaItem, error)
+	AddItems(ctx context.Context, tmdbIds, tvdbIds *string, mediaType *models.MediaType) (*models.MessageResponse, error)
+	RemoveItems(ctx context.Context, ids string) (*models.RemoveResponse, error)
+	RetryItems(ctx context.Context, ids string) (*models.RetryResponse, error)
+	ResetItems(ctx context.Context, ids string) (*models.ResetResponse, error)
+	PauseItems(ctx context.Context, ids string) (*models.PauseResponse, error)
+	UnpauseItems(ctx context.Context, ids string) (*models.PauseResponse, error)
+	GetItemsByIMDBIds(ctx context.Context, imdbIds string) ([]models.MediaItem, error)
+	RetryLibraryItems(ctx context.Context) (*models.RetryResponse, error)
+	UpdateOngoingItems(ctx context.Context) (*models.UpdateOngoingResponse, error)
+	UpdateNewReleases(ctx context.Context, params *UpdateNewReleasesParams) (*models.UpdateNewReleasesResponse, error)
+	GetItemStreams(ctx context.Context, itemID int) (*models.ItemStreamsResponse, error)
+	BlacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error)
+	UnblacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error)
+	ResetItemStreams(ctx context.Context, itemID int) (*models.MessageResponse, error)
+	ReindexItem(ctx context.Context, params *ReindexParams) (*models.ReindexResponse, error)
+	FfprobeMediaFiles(ctx context.Context, id int) (*models.FfprobeResponse, error)
+}
+
+// SettingsAPI covers the settings endpoints
+type SettingsAPI interface {
+	GetSettingsSchema(ctx context.Context) (map[string]interface{}, error)
+	LoadSettings(ctx context.Context) (*models.MessageResponse, error)
+	SaveSettings(ctx context.Context) (*models.MessageResponse, error)
+	GetAllSettings(ctx context.Context) (interface{}, error)
+	GetSettings(ctx context.Context, paths string) (map[string]interface{}, error)
+	SetAllSettings(ctx context.Context, settings map[string]interface{}) (*models.MessageResponse, error)
+	SetSettings(ctx context.Context, settings []SetSettingsRequest) (*models.MessageResponse, error)
+}
+
+// ScrapingAPI covers scraping and manual scrape session endpoints
+type ScrapingAPI interface {
+	ScrapeItem(ctx context.Context, params *ScrapeItemParams) (*models.ScrapeItemResponse, error)
+	StartManualSession(ctx context.Context, params *StartManualSessionParams) (*models.StartSessionResponse, error)
+	SelectFiles(ctx context.Context, sessionID string, container interface{}) (*models.SelectFilesResponse, error)
+	UpdateAttributes(ctx context.Context, sessionID string, data interface{}) (*models.UpdateAttributesResponse, error)
+	AbortManualSession(ctx context.Context, sessionID string) (*models.SessionResponse, error)
+	CompleteManualSession(ctx context.Context, sessionID string) (*models.SessionResponse, error)
+	ParseTorrentTitles(ctx context.Context, titles []string) (*models.ParseTorrentTitleResponse, error)
+}
+
+// StreamingAPI covers the event streaming endpoints
+type StreamingAPI interface {
+	GetEventTypes(ctx context.Context) (interface{}, error)
+	StreamEvents(ctx context.Context, eventType string) (*models.StreamEventResponse, error)
+	StreamEventsSSE(ctx context.Context, eventChan chan<- models.Event) error
+	SubscribeEvents(ctx context.Context, opts *EventStreamOptions) *EventStream
+}
+
+// RivenAPI is the full Riven API as used by the TUI. *Client satisfies it;
+// tests and alternative backends (caching, recording) can provide their own.
+type RivenAPI interface {
+	SystemAPI
+	ItemsAPI
+	SettingsAPI
+	ScrapingAPI
+	StreamingAPI
+}
+
+var _ RivenAPI = (*Client)(nil)
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"riven-tui/pkg/config"
+)
+
+func newRetryTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return NewClient(&config.Config{
+		API: config.APIConfig{
+			Endpoint: server.URL,
+			Token:    "test-token",
+			Timeout:  5 * time.Second,
+			Retry: config.RetryConfig{
+				MaxAttempts:  3,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     5 * time.Millisecond,
+			},
+		},
+	})
+}
+
+func TestRetryOnTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"total_items": 7}`))
+	})
+
+	stats, err := client.GetStats(context.Background())
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if stats.TotalItems != 7 {
+		t.Errorf("expected 7 items, got %d", stats.TotalItems)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.GetStats(context.Background())
+	if apiErr, ok := AsAPIError(err); !ok || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetrySkipsPostUnlessOptedIn(t *testing.T) {
+	var calls atomic.Int32
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"message": "ok", "ids": ["1"]}`))
+	})
+
+	if _, err := client.RetryItems(context.Background(), "1"); err == nil {
+		t.Fatal("expected POST without opt-in to fail on 429")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+	if _, err := client.RetryItems(WithRetry(context.Background()), "1"); err != nil {
+		t.Fatalf("expected opted-in POST to succeed, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if _, err := client.GetStats(context.Background()); !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetryBackoffBounds(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for retry := 1; retry <= 5; retry++ {
+		delay := policy.backoff(retry)
+		if delay < 50*time.Millisecond || delay > 300*time.Millisecond {
+			t.Errorf("retry %d: delay %v out of bounds", retry, delay)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent is a single event read from a text/event-stream
+type SSEEvent struct {
+	// ID is the last event ID seen on the stream, which may have been set by
+	// an earlier event
+	ID string
+	// Event is the event type, "message" when the server did not set one
+	Event string
+	// Data is the payload; multiple data lines are joined with "\n"
+	Data string
+}
+
+// SSEReader parses a text/event-stream as described by the HTML Living
+// Standard. Lines may end in "\n", "\r\n" or "\r" and have no length limit.
+type SSEReader struct {
+	r           *bufio.Reader
+	lastEventID string
+	retry       time.Duration
+	// skipLF is set after a "\r" so a following "\n" is not read as an
+	// empty line. Peeking instead would block until the next byte arrives.
+	skipLF bool
+}
+
+// NewSSEReader creates a reader for the given stream
+func NewSSEReader(r io.Reader) *SSEReader {
+	return &SSEReader{r: bufio.NewReader(r)}
+}
+
+// LastEventID returns the last event ID set by the server
+func (r *SSEReader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Retry returns the reconnection delay requested by the server, or zero
+func (r *SSEReader) Retry() time.Duration {
+	return r.retry
+}
+
+// Next returns the next event. It returns io.EOF when the stream ends; an
+// event that is not terminated by a blank line is discarded.
+func (r *SSEReader) Next() (*SSEEvent, error) {
+	var data strings.Builder
+	var eventType string
+	hasData := false
+
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// A blank line dispatches the event
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return &SSEEvent{
+				ID:    r.lastEventID,
+				Event: eventType,
+				Data:  strings.TrimSuffix(data.String(), "\n"),
+			}, nil
+		}
+
+		// Comment lines are used as keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// readLine reads a line without its terminator
+func (r *SSEReader) readLine() (string, error) {
+	var line bytes.Buffer
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+
+		skipLF := r.skipLF
+		r.skipLF = false
+
+		switch b {
+		case '\n':
+			if skipLF {
+				continue
+			}
+			return line.String(), nil
+		case '\r':
+			r.skipLF = true
+			return line.String(), nil
+		default:
+			line.WriteByte(b)
+		}
+	}
+}
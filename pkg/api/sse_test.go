@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSSEReaderParsesAllFields(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	stream := ": comment\r\n" +
+		"retry: 2500\r\n" +
+		"\r\n" +
+		"id: 7\n" +
+		"event: item_update\n" +
+		"data: {\"a\":\n" +
+		"data:1}\n" +
+		"\n" +
+		"data: " + long + "\r" +
+		"\r" +
+		"id: 9\n" +
+		"data: incomplete"
+
+	r := NewSSEReader(strings.NewReader(stream))
+
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if ev.ID != "7" || ev.Event != "item_update" || ev.Data != "{\"a\":\n1}" {
+		t.Errorf("Unexpected first event: %+v", ev)
+	}
+	if r.Retry() != 2500*time.Millisecond {
+		t.Errorf("Expected retry of 2.5s, got %v", r.Retry())
+	}
+
+	ev, err = r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if ev.Event != "message" || ev.ID != "7" || ev.Data != long {
+		t.Errorf("Unexpected second event: type %q id %q, %d bytes", ev.Event, ev.ID, len(ev.Data))
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected the unterminated event to be discarded with EOF, got %v", err)
+	}
+}
+
+func TestEventStreamReconnectsWithLastEventID(t *testing.T) {
+	var mu sync.Mutex
+	var lastIDs []string
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		n := len(lastIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "retry: 1\nid: %d\nevent: item_update\ndata: {\"item_id\": \"%d\"}\n\n", n, n)
+		fmt.Fprint(w, "data: not json\n\n")
+		// Returning closes the connection and forces a reconnect
+	})
+
+	var states []StreamState
+	var statesMu sync.Mutex
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := client.SubscribeEvents(ctx, &EventStreamOptions{
+		LastEventID: "0",
+		OnStateChange: func(state StreamState, err error) {
+			statesMu.Lock()
+			states = append(states, state)
+			statesMu.Unlock()
+		},
+	})
+
+	for i := 1; i <= 2; i++ {
+		ev := <-stream.Events()
+		if ev.Type != "item_update" || ev.ID != fmt.Sprint(i) || ev.Data["item_id"] != fmt.Sprint(i) {
+			t.Fatalf("Unexpected event %d: %+v", i, ev)
+		}
+	}
+
+	var decodeErr *EventDecodeError
+	if err := <-stream.Errors(); !errors.As(err, &decodeErr) {
+		t.Errorf("Expected a decode error, got %v", err)
+	}
+
+	cancel()
+	<-stream.Done()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if lastIDs[0] != "0" || lastIDs[1] != "1" {
+		t.Errorf("Expected Last-Event-ID 0 then 1, got %v", lastIDs)
+	}
+
+	statesMu.Lock()
+	defer statesMu.Unlock()
+	if states[0] != StreamConnecting || states[1] != StreamConnected || states[2] != StreamDisconnected {
+		t.Errorf("Unexpected state sequence: %v", states)
+	}
+	if states[len(states)-1] != StreamClosed {
+		t.Errorf("Expected the stream to end closed, got %v", states[len(states)-1])
+	}
+}
+
+func TestEventStreamKeepsLastEventIDAcrossReconnects(t *testing.T) {
+	var mu sync.Mutex
+	var lastIDs []string
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		n := len(lastIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if n == 1 {
+			fmt.Fprint(w, "retry: 1\nid: 5\nevent: item_update\ndata: {}\n\n")
+			return
+		}
+		// Later connections send events without an id
+		fmt.Fprint(w, "retry: 1\nevent: item_update\ndata: {}\n\n")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := client.SubscribeEvents(ctx, nil)
+
+	for i := 1; i <= 3; i++ {
+		if ev := <-stream.Events(); ev.ID != "5" {
+			t.Fatalf("Event %d: expected the last event ID 5, got %q", i, ev.ID)
+		}
+	}
+	cancel()
+	<-stream.Done()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(lastIDs) < 3 || lastIDs[0] != "" || lastIDs[1] != "5" || lastIDs[2] != "5" {
+		t.Errorf("Expected every reconnect to resume from 5, got %v", lastIDs)
+	}
+}
+
+func TestEventStreamStopsOnUnauthorized(t *testing.T) {
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"detail": "Invalid token"}`))
+	})
+
+	stream := client.SubscribeEvents(context.Background(), nil)
+	select {
+	case <-stream.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the stream to give up")
+	}
+	if !IsUnauthorized(stream.Err()) {
+		t.Errorf("Expected unauthorized error, got %v", stream.Err())
+	}
+}
+
+func TestEventStreamOutlivesRequestTimeout(t *testing.T) {
+	client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		time.Sleep(150 * time.Millisecond)
+		fmt.Fprint(w, "data: {\"type\": \"late\"}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	client.httpClient.Timeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream := client.SubscribeEvents(ctx, nil)
+
+	ev := <-stream.Events()
+	if ev.Type != "late" {
+		t.Errorf("Expected the late event, got %+v", ev)
+	}
+	if stream.State() != StreamConnected {
+		t.Errorf("Expected to still be connected, got %v", stream.State())
+	}
+}
@@ -11,8 +11,9 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	API APIConfig `yaml:"api"`
-	UI  UIConfig  `yaml:"ui"`
+	API      APIConfig      `yaml:"api"`
+	UI       UIConfig       `yaml:"ui"`
+	Settings SettingsConfig `yaml:"settings"`
 }
 
 // APIConfig represents API-related configuration
@@ -20,6 +21,14 @@ type APIConfig struct {
 	Endpoint string        `yaml:"endpoint"`
 	Token    string        `yaml:"token" json:"token"`
 	Timeout  time.Duration `yaml:"timeout"`
+	Retry    RetryConfig   `yaml:"retry"`
+}
+
+// RetryConfig represents the retry policy for API requests
+type RetryConfig struct {
+	MaxAttempts  int           `yaml:"max_attempts"`
+	InitialDelay time.Duration `yaml:"initial_delay"`
+	MaxDelay     time.Duration `yaml:"max_delay"`
 }
 
 // UIConfig represents UI-related configuration
@@ -29,6 +38,12 @@ type UIConfig struct {
 	PageSize        int           `yaml:"page_size"`
 }
 
+// SettingsConfig represents the local history of Riven settings changes
+type SettingsConfig struct {
+	HistoryDir   string `yaml:"history_dir"`
+	HistoryLimit int    `yaml:"history_limit"`
+}
+
 // DefaultConfig returns a configuration with default values
 func DefaultConfig() *Config {
 	return &Config{
@@ -36,12 +51,21 @@ func DefaultConfig() *Config {
 			Endpoint: "http://localhost:8080",
 			Token:    "",
 			Timeout:  30 * time.Second,
+			Retry: RetryConfig{
+				MaxAttempts:  3,
+				InitialDelay: 1 * time.Second,
+				MaxDelay:     10 * time.Second,
+			},
 		},
 		UI: UIConfig{
 			RefreshInterval: 5 * time.Second,
 			Theme:           "default",
 			PageSize:        50,
 		},
+		Settings: SettingsConfig{
+			HistoryDir:   filepath.Join(os.Getenv("HOME"), ".config", "riven-tui", "settings-history"),
+			HistoryLimit: 200,
+		},
 	}
 }
 
@@ -136,6 +160,10 @@ func loadFromEnv(config *Config) {
 	if theme := os.Getenv("RIVEN_UI_THEME"); theme != "" {
 		config.UI.Theme = theme
 	}
+
+	if historyDir := os.Getenv("RIVEN_SETTINGS_HISTORY_DIR"); historyDir != "" {
+		config.Settings.HistoryDir = historyDir
+	}
 }
 
 // validateConfig validates the configuration
@@ -152,6 +180,18 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("API timeout must be positive")
 	}
 
+	if config.API.Retry.MaxAttempts <= 0 {
+		config.API.Retry.MaxAttempts = 1 // A single attempt disables retries
+	}
+
+	if config.API.Retry.InitialDelay < 0 || config.API.Retry.MaxDelay < 0 {
+		return fmt.Errorf("API retry delays must not be negative")
+	}
+
+	if config.API.Retry.MaxDelay > 0 && config.API.Retry.InitialDelay > config.API.Retry.MaxDelay {
+		return fmt.Errorf("API retry initial_delay must not exceed max_delay")
+	}
+
 	if config.UI.RefreshInterval <= 0 {
 		return fmt.Errorf("UI refresh interval must be positive")
 	}
@@ -160,6 +200,14 @@ func validateConfig(config *Config) error {
 		config.UI.PageSize = 50 // Set default if invalid
 	}
 
+	if config.Settings.HistoryDir == "" {
+		config.Settings.HistoryDir = DefaultConfig().Settings.HistoryDir
+	}
+
+	if config.Settings.HistoryLimit < 0 {
+		config.Settings.HistoryLimit = 0 // Keep every snapshot
+	}
+
 	return nil
 }
 
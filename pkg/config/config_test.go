@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -144,3 +145,34 @@ func TestConfigPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadRetryConfigFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`api:
+  endpoint: "http://localhost:8080"
+  token: "test-token"
+  timeout: 30s
+  retry:
+    max_attempts: 5
+    initial_delay: 500ms
+    max_delay: 20s
+`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if config.API.Retry.MaxAttempts != 5 {
+		t.Errorf("Expected 5 retry attempts, got %d", config.API.Retry.MaxAttempts)
+	}
+	if config.API.Retry.InitialDelay != 500*time.Millisecond {
+		t.Errorf("Expected initial delay 500ms, got %v", config.API.Retry.InitialDelay)
+	}
+	if config.API.Retry.MaxDelay != 20*time.Second {
+		t.Errorf("Expected max delay 20s, got %v", config.API.Retry.MaxDelay)
+	}
+}
@@ -0,0 +1,157 @@
+package fakeriven
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+// maxEvents is how many events are kept for Last-Event-ID replay
+const maxEvents = 256
+
+// heartbeatInterval is how often an idle event stream sends a comment line
+const heartbeatInterval = 15 * time.Second
+
+// sseEvent is a published server-sent event
+type sseEvent struct {
+	id    int
+	event string
+	data  []byte
+}
+
+// publish records an event and fans it out to the connected streams. The
+// caller must hold s.mu. Events are not published while seeding.
+func (s *Server) publish(eventType, message string, data map[string]interface{}) {
+	if s.seeding {
+		return
+	}
+
+	payload, _ := json.Marshal(models.Event{
+		Type:      eventType,
+		Timestamp: s.now().Format(time.RFC3339),
+		Data:      data,
+		Message:   message,
+	})
+
+	s.nextEventID++
+	ev := sseEvent{id: s.nextEventID, event: eventType, data: payload}
+
+	s.events = append(s.events, ev)
+	if len(s.events) > maxEvents {
+		s.events = s.events[len(s.events)-maxEvents:]
+	}
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			// Slow consumer; it will catch up through Last-Event-ID on reconnect
+		}
+	}
+}
+
+// Publish emits a custom event, mainly for tests
+func (s *Server) Publish(eventType, message string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publish(eventType, message, data)
+}
+
+// Subscribers returns the number of connected event streams
+func (s *Server) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
+
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeDetail(w, http.StatusInternalServerError, "Streaming unsupported")
+		return
+	}
+
+	lastID, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	ch := make(chan sseEvent, 64)
+
+	s.mu.Lock()
+	var replay []sseEvent
+	if lastID > 0 {
+		for _, ev := range s.events {
+			if ev.id > lastID {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	for _, ev := range replay {
+		writeEvent(w, ev)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			writeEvent(w, ev)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes a single event in text/event-stream format
+func writeEvent(w http.ResponseWriter, ev sseEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.id, ev.event, ev.data)
+}
+
+func (s *Server) handleEventTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":     "Available event types",
+		"event_types": []string{"item_update", "item_removed", "logging"},
+	})
+}
+
+func (s *Server) handleStreamEvent(w http.ResponseWriter, r *http.Request) {
+	eventType := r.PathValue("type")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest map[string]interface{}
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if s.events[i].event == eventType {
+			json.Unmarshal(s.events[i].data, &latest)
+			break
+		}
+	}
+	if latest == nil {
+		latest = map[string]interface{}{}
+	}
+
+	writeJSON(w, http.StatusOK, models.StreamEventResponse{Data: latest})
+}
@@ -0,0 +1,582 @@
+package fakeriven
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"riven-tui/pkg/models"
+)
+
+func (s *Server) handleStates(w http.ResponseWriter, r *http.Request) {
+	states := []string{}
+	for _, st := range []models.States{
+		models.StateUnknown, models.StateUnreleased, models.StateOngoing,
+		models.StateRequested, models.StateIndexed, models.StateScraped,
+		models.StateDownloaded, models.StateSymlinked, models.StateCompleted,
+		models.StatePartiallyCompleted, models.StateFailed, models.StatePaused,
+	} {
+		states = append(states, string(st))
+	}
+	writeJSON(w, http.StatusOK, models.StateResponse{Success: true, States: states})
+}
+
+func (s *Server) handleItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, page := 50, 1
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeValidation(w, models.ValidationError{
+				Loc: []interface{}{"query", "limit"}, Msg: "Input should be greater than or equal to 1", Type: "greater_than_equal",
+			})
+			return
+		}
+		limit = n
+	}
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeValidation(w, models.ValidationError{
+				Loc: []interface{}{"query", "page"}, Msg: "Input should be greater than or equal to 1", Type: "greater_than_equal",
+			})
+			return
+		}
+		page = n
+	}
+
+	types := map[string]bool{"movie": true, "show": true}
+	if v := query.Get("type"); v != "" {
+		types = map[string]bool{}
+		for _, t := range splitIDs(v) {
+			if t == "tv" {
+				t = "show"
+			}
+			types[t] = true
+		}
+	}
+
+	var states map[models.States]bool
+	if v := query.Get("states"); v != "" {
+		states = map[models.States]bool{}
+		for _, st := range splitIDs(v) {
+			states[models.States(st)] = true
+		}
+	}
+
+	search := strings.ToLower(query.Get("search"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*item
+	for _, it := range s.items {
+		if !types[it.kind] {
+			continue
+		}
+		if states != nil && !states[it.state] {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(it.displayTitle()), search) &&
+			search != strconv.Itoa(it.id) && search != strings.ToLower(it.imdbID) {
+			continue
+		}
+		matched = append(matched, it)
+	}
+
+	switch models.SortOrder(query.Get("sort")) {
+	case models.SortDateAsc:
+		sort.Slice(matched, func(i, j int) bool { return lessByDate(matched[i], matched[j]) })
+	case models.SortTitleAsc:
+		sort.Slice(matched, func(i, j int) bool { return strings.ToLower(matched[i].title) < strings.ToLower(matched[j].title) })
+	case models.SortTitleDesc:
+		sort.Slice(matched, func(i, j int) bool { return strings.ToLower(matched[i].title) > strings.ToLower(matched[j].title) })
+	default:
+		sort.Slice(matched, func(i, j int) bool { return lessByDate(matched[j], matched[i]) })
+	}
+
+	total := len(matched)
+	totalPages := (total + limit - 1) / limit
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	extended := query.Get("extended") == "true"
+	items := []map[string]interface{}{}
+	for _, it := range matched[start:end] {
+		items = append(items, it.toMap(extended, false))
+	}
+
+	// Items are sent as dictionaries, as Riven does
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"items":       items,
+		"page":        page,
+		"limit":       limit,
+		"total_items": total,
+		"total_pages": totalPages,
+	})
+}
+
+// lessByDate orders items by request date, breaking ties by ID
+func lessByDate(a, b *item) bool {
+	if a.requestedAt.Equal(b.requestedAt) {
+		return a.id < b.id
+	}
+	return a.requestedAt.Before(b.requestedAt)
+}
+
+// lookup finds an item by its string ID
+func (s *Server) lookup(id string) (*item, bool) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false
+	}
+	it, ok := s.items[n]
+	return it, ok
+}
+
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	withStreams := r.URL.Query().Get("with_streams") == "true"
+	writeJSON(w, http.StatusOK, it.toMap(true, withStreams))
+}
+
+// handleItemSubpath routes /items/imdb/{ids} and /items/{id}/streams, which
+// ServeMux cannot tell apart on its own
+func (s *Server) handleItemSubpath(w http.ResponseWriter, r *http.Request) {
+	first, second := r.PathValue("first"), r.PathValue("second")
+	switch {
+	case first == "imdb":
+		r.SetPathValue("ids", second)
+		s.handleItemsByIMDB(w, r)
+	case second == "streams":
+		r.SetPathValue("id", first)
+		s.handleItemStreams(w, r)
+	default:
+		writeDetail(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) handleItemsByIMDB(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := map[string]bool{}
+	for _, id := range splitIDs(r.PathValue("ids")) {
+		wanted[id] = true
+	}
+
+	result := []map[string]interface{}{}
+	for _, it := range s.sortedItems() {
+		if it.imdbID != "" && wanted[it.imdbID] {
+			result = append(result, it.toMap(false, false))
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// sortedItems returns all items ordered by ID
+func (s *Server) sortedItems() []*item {
+	items := make([]*item, 0, len(s.items))
+	for _, it := range s.items {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].id < items[j].id })
+	return items
+}
+
+func (s *Server) handleAddItems(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	tmdbIDs := splitIDs(query.Get("tmdb_ids"))
+	tvdbIDs := splitIDs(query.Get("tvdb_ids"))
+	mediaType := query.Get("media_type")
+
+	if len(tmdbIDs) == 0 && len(tvdbIDs) == 0 {
+		writeDetail(w, http.StatusBadRequest, "No IDs provided")
+		return
+	}
+	if mediaType != "" && mediaType != string(models.MediaTypeMovie) && mediaType != string(models.MediaTypeTV) {
+		writeValidation(w, models.ValidationError{
+			Loc: []interface{}{"query", "media_type"}, Msg: "Input should be 'movie' or 'tv'", Type: "enum",
+		})
+		return
+	}
+	for _, id := range append(append([]string{}, tmdbIDs...), tvdbIDs...) {
+		if _, err := strconv.Atoi(id); err != nil {
+			writeDetail(w, http.StatusBadRequest, fmt.Sprintf("Invalid ID: %s", id))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known := map[string]bool{}
+	for _, it := range s.items {
+		if it.tmdbID != "" {
+			known["tmdb:"+it.tmdbID] = true
+		}
+		if it.tvdbID != "" {
+			known["tvdb:"+it.tvdbID] = true
+		}
+	}
+
+	response := models.MessageResponse{}
+	for _, id := range tmdbIDs {
+		if known["tmdb:"+id] {
+			continue
+		}
+		kind := "movie"
+		if mediaType == string(models.MediaTypeTV) {
+			kind = "show"
+		}
+		it := s.newItem(kind, fmt.Sprintf("TMDB %s", id))
+		it.tmdbID = id
+		s.addPlaceholder(it)
+		known["tmdb:"+id] = true
+		response.TMDBIds = append(response.TMDBIds, id)
+	}
+	for _, id := range tvdbIDs {
+		if known["tvdb:"+id] {
+			continue
+		}
+		it := s.newItem("show", fmt.Sprintf("TVDB %s", id))
+		it.tvdbID = id
+		s.addPlaceholder(it)
+		known["tvdb:"+id] = true
+		response.TVDBIds = append(response.TVDBIds, id)
+	}
+
+	added := len(response.TMDBIds) + len(response.TVDBIds)
+	response.Message = fmt.Sprintf("Added %d item(s) to the queue", added)
+	writeJSON(w, http.StatusOK, response)
+}
+
+// addPlaceholder fills in a newly requested item until it gets indexed
+func (s *Server) addPlaceholder(it *item) {
+	it.year = s.now().Year()
+	it.overview = "Requested through the API; metadata will be filled in by the indexer."
+	if it.kind == "show" {
+		season := s.newItem("season", "Season 1")
+		season.number = 1
+		season.parent = it
+		it.children = append(it.children, season)
+		for n := 1; n <= 3; n++ {
+			ep := s.newItem("episode", fmt.Sprintf("Episode %d", n))
+			ep.number = n
+			ep.parent = season
+			aired := s.now().AddDate(0, 0, 7*(n-2))
+			ep.airedAt = &aired
+			if aired.After(s.now()) {
+				ep.state = models.StateUnreleased
+			}
+			s.generateStreams(ep)
+			season.children = append(season.children, ep)
+		}
+		s.recompute(season)
+	} else {
+		aired := s.now().AddDate(0, -1, 0)
+		it.airedAt = &aired
+		s.generateStreams(it)
+	}
+	s.publish("item_update", fmt.Sprintf("%s was requested", it.displayTitle()), map[string]interface{}{
+		"item_id": strconv.Itoa(it.id),
+		"type":    it.kind,
+		"title":   it.displayTitle(),
+		"state":   string(it.state),
+	})
+	s.logf("INFO", "program.add_items", "Added %s to the queue", it.displayTitle())
+}
+
+// handleItemAction runs an action on every item in the "ids" query and
+// reports the IDs that were actually processed
+func (s *Server) handleItemAction(verb string, action func(*item) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ids := splitIDs(r.URL.Query().Get("ids"))
+		if len(ids) == 0 {
+			writeValidation(w, missingQuery("ids"))
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		processed := []string{}
+		for _, id := range ids {
+			it, ok := s.lookup(id)
+			if !ok {
+				continue
+			}
+			if action(it) {
+				processed = append(processed, id)
+			}
+		}
+
+		if len(processed) == 0 {
+			writeDetail(w, http.StatusNotFound, "No matching items found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message": fmt.Sprintf("%s items with id's %s", verb, strings.Join(processed, ", ")),
+			"ids":     processed,
+		})
+	}
+}
+
+func (s *Server) removeAction(it *item) bool {
+	s.logf("INFO", "program.remove_items", "Removed %s", it.displayTitle())
+	s.publish("item_removed", fmt.Sprintf("%s was removed", it.displayTitle()), map[string]interface{}{
+		"item_id": strconv.Itoa(it.id),
+		"type":    it.kind,
+		"title":   it.displayTitle(),
+	})
+	s.removeItem(it)
+	return true
+}
+
+func (s *Server) retryAction(it *item) bool {
+	for _, leaf := range it.leaves() {
+		if leaf.state != models.StateCompleted && leaf.state != models.StateUnreleased && leaf.state != models.StatePaused {
+			s.setState(leaf, models.StateRequested)
+		}
+	}
+	return true
+}
+
+func (s *Server) resetAction(it *item) bool {
+	for _, leaf := range it.leaves() {
+		if leaf.state == models.StateUnreleased {
+			continue
+		}
+		for _, st := range leaf.streams {
+			st.blacklisted = false
+		}
+		s.setState(leaf, models.StateRequested)
+	}
+	return true
+}
+
+func (s *Server) pauseAction(it *item) bool {
+	changed := false
+	for _, leaf := range it.leaves() {
+		if leaf.state != models.StatePaused {
+			s.setState(leaf, models.StatePaused)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (s *Server) unpauseAction(it *item) bool {
+	changed := false
+	for _, leaf := range it.leaves() {
+		if leaf.state != models.StatePaused {
+			continue
+		}
+		target := leaf.lastState
+		if target == "" || target == models.StatePaused {
+			target = models.StateRequested
+		}
+		s.setState(leaf, target)
+		changed = true
+	}
+	return changed
+}
+
+func (s *Server) handleRetryLibrary(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []string{}
+	for _, it := range s.sortedItems() {
+		if (it.kind == "movie" || it.kind == "episode") && it.state == models.StateFailed {
+			s.setState(it, models.StateRequested)
+			ids = append(ids, strconv.Itoa(it.id))
+		}
+	}
+	writeJSON(w, http.StatusOK, models.RetryResponse{
+		Message: fmt.Sprintf("Retried %d items", len(ids)),
+		IDs:     ids,
+	})
+}
+
+func (s *Server) handleUpdateOngoing(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := []map[string]interface{}{}
+	for _, it := range s.sortedItems() {
+		if it.state == models.StateUnreleased && it.airedAt != nil && !it.airedAt.After(s.now()) {
+			s.setState(it, models.StateRequested)
+			updated = append(updated, map[string]interface{}{"item_id": it.id, "title": it.displayTitle(), "state": string(it.state)})
+		}
+	}
+	writeJSON(w, http.StatusOK, models.UpdateOngoingResponse{
+		Message:      fmt.Sprintf("Updated %d items", len(updated)),
+		UpdatedItems: updated,
+	})
+}
+
+func (s *Server) handleUpdateNewReleases(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.UpdateNewReleasesResponse{
+		Message:      "Updated 0 items",
+		UpdatedItems: []map[string]interface{}{},
+	})
+}
+
+func (s *Server) handleReindex(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var target *item
+	if id := query.Get("item_id"); id != "" {
+		target, _ = s.lookup(id)
+	}
+	for _, it := range s.items {
+		if target != nil {
+			break
+		}
+		switch {
+		case query.Get("tmdb_id") != "" && it.tmdbID == query.Get("tmdb_id"),
+			query.Get("tvdb_id") != "" && it.tvdbID == query.Get("tvdb_id"),
+			query.Get("imdb_id") != "" && it.imdbID == query.Get("imdb_id"):
+			target = it
+		}
+	}
+	if target == nil {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	target.updatedAt = s.now()
+	s.logf("INFO", "program.reindex", "Reindexed %s", target.displayTitle())
+	writeJSON(w, http.StatusOK, models.ReindexResponse{
+		Message: fmt.Sprintf("Successfully reindexed %s", target.displayTitle()),
+	})
+}
+
+func (s *Server) handleFfprobe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.lookup(r.URL.Query().Get("id"))
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+	if it.file == "" {
+		writeDetail(w, http.StatusBadRequest, "Item has no media file to probe")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.FfprobeResponse{Data: map[string]interface{}{
+		"filename": it.file,
+		"duration": 7260.5,
+		"format":   "matroska,webm",
+		"video":    []map[string]interface{}{{"codec": "hevc", "width": 3840, "height": 2160, "frame_rate": 23.976}},
+		"audio":    []map[string]interface{}{{"codec": "truehd", "channels": 8, "language": "eng"}},
+	}})
+}
+
+func (s *Server) handleItemStreams(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	streams := []models.Stream{}
+	blacklisted := []models.Stream{}
+	for _, st := range it.streams {
+		if st.blacklisted {
+			blacklisted = append(blacklisted, st.toModel(it))
+		} else {
+			streams = append(streams, st.toModel(it))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":             fmt.Sprintf("Retrieved streams for item %d", it.id),
+		"streams":             streams,
+		"blacklisted_streams": blacklisted,
+	})
+}
+
+func (s *Server) handleResetStreams(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	it.streams = nil
+	s.generateStreams(it)
+	s.logf("INFO", "program.reset_streams", "Reset streams for %s", it.displayTitle())
+	writeJSON(w, http.StatusOK, models.MessageResponse{
+		Message: fmt.Sprintf("Successfully reset streams for item %d", it.id),
+	})
+}
+
+func (s *Server) handleBlacklist(blacklist bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		it, ok := s.lookup(r.PathValue("id"))
+		if !ok {
+			writeDetail(w, http.StatusNotFound, "Item not found")
+			return
+		}
+		streamID, err := strconv.Atoi(r.PathValue("stream"))
+		if err != nil {
+			writeValidation(w, models.ValidationError{
+				Loc: []interface{}{"path", "stream_id"}, Msg: "Input should be a valid integer", Type: "int_parsing",
+			})
+			return
+		}
+
+		for _, st := range it.streams {
+			if st.id != streamID {
+				continue
+			}
+			st.blacklisted = blacklist
+			verb := "Unblacklisted"
+			if blacklist {
+				verb = "Blacklisted"
+			}
+			s.logf("INFO", "program.blacklist", "%s stream %s for %s", verb, st.infoHash, it.displayTitle())
+			writeJSON(w, http.StatusOK, models.MessageResponse{
+				Message: fmt.Sprintf("%s stream %s for item %d", verb, st.infoHash, it.id),
+			})
+			return
+		}
+
+		writeDetail(w, http.StatusNotFound, "Stream not found")
+	}
+}
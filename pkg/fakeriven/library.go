@@ -0,0 +1,576 @@
+package fakeriven
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+// pipeline is the order in which Riven moves an item towards completion
+var pipeline = []models.States{
+	models.StateRequested,
+	models.StateIndexed,
+	models.StateScraped,
+	models.StateDownloaded,
+	models.StateSymlinked,
+	models.StateCompleted,
+}
+
+// item is the in-memory representation of a movie, show, season or episode
+type item struct {
+	id          int
+	kind        string // movie, show, season, episode
+	title       string
+	state       models.States
+	lastState   models.States
+	year        int
+	tmdbID      string
+	tvdbID      string
+	imdbID      string
+	overview    string
+	genres      []string
+	number      int
+	requestedAt time.Time
+	updatedAt   time.Time
+	airedAt     *time.Time
+	parent      *item
+	children    []*item
+	streams     []*stream
+	file        string // path of the downloaded file inside the rclone mount
+}
+
+// stream is a torrent candidate ranked for an item
+type stream struct {
+	id          int
+	infoHash    string
+	rawTitle    string
+	resolution  string
+	codec       string
+	hdr         []string
+	audio       []string
+	languages   []string
+	size        string
+	rank        int
+	levRatio    float64
+	cached      bool
+	trash       bool
+	blacklisted bool
+}
+
+// movieSeed and showSeed describe the demo library
+type movieSeed struct {
+	title  string
+	year   int
+	tmdbID string
+	imdbID string
+	state  models.States
+	genres []string
+}
+
+type showSeed struct {
+	title    string
+	year     int
+	tvdbID   string
+	imdbID   string
+	seasons  []int // episode count per season
+	complete int   // number of leading episodes already completed
+	genres   []string
+}
+
+var movieSeeds = []movieSeed{
+	{"The Matrix", 1999, "603", "tt0133093", models.StateCompleted, []string{"action", "science fiction"}},
+	{"Inception", 2010, "27205", "tt1375666", models.StateCompleted, []string{"action", "thriller"}},
+	{"Dune: Part Two", 2024, "693134", "tt15239678", models.StateDownloaded, []string{"science fiction", "adventure"}},
+	{"Oppenheimer", 2023, "872585", "tt15398776", models.StateFailed, []string{"drama", "history"}},
+	{"Past Lives", 2023, "666277", "tt13238346", models.StateScraped, []string{"drama", "romance"}},
+	{"Arrival", 2016, "329865", "tt2543164", models.StateIndexed, []string{"drama", "science fiction"}},
+	{"Blade Runner 2049", 2017, "335984", "tt1856101", models.StateRequested, []string{"science fiction"}},
+	{"Parasite", 2019, "496243", "tt6751668", models.StateCompleted, []string{"comedy", "thriller"}},
+	{"Spirited Away", 2001, "129", "tt0245429", models.StatePaused, []string{"animation", "fantasy"}},
+	{"Everything Everywhere All at Once", 2022, "545611", "tt6710474", models.StateFailed, []string{"action", "comedy"}},
+	{"The Grand Budapest Hotel", 2014, "120467", "tt2278388", models.StateSymlinked, []string{"comedy"}},
+	{"Interstellar", 2014, "157336", "tt0816692", models.StateCompleted, []string{"adventure", "science fiction"}},
+}
+
+var showSeeds = []showSeed{
+	{"Severance", 2022, "371980", "tt11280740", []int{9, 10}, 14, []string{"drama", "mystery"}},
+	{"The Bear", 2022, "403245", "tt14452776", []int{8, 10, 10}, 28, []string{"comedy", "drama"}},
+	{"Shōgun", 2024, "421077", "tt2788316", []int{10}, 4, []string{"drama", "history"}},
+	{"Slow Horses", 2022, "372218", "tt5875444", []int{6, 6, 6, 6, 6}, 30, []string{"drama", "thriller"}},
+}
+
+// seed populates the library with the demo movies and shows
+func (s *Server) seed() {
+	now := s.now()
+
+	for i, seed := range movieSeeds {
+		it := s.newItem("movie", seed.title)
+		it.year = seed.year
+		it.tmdbID = seed.tmdbID
+		it.imdbID = seed.imdbID
+		it.genres = seed.genres
+		it.state = seed.state
+		it.overview = fmt.Sprintf("%s (%d) is part of the demo library.", seed.title, seed.year)
+		it.requestedAt = now.Add(-time.Duration(len(movieSeeds)-i) * 26 * time.Hour)
+		it.updatedAt = it.requestedAt.Add(2 * time.Hour)
+		aired := time.Date(seed.year, time.Month(1+i%12), 1+i, 0, 0, 0, 0, time.UTC)
+		it.airedAt = &aired
+		if seed.state == models.StatePaused {
+			it.lastState = models.StateScraped
+		}
+		s.generateStreams(it)
+		s.syncFile(it)
+	}
+
+	for i, seed := range showSeeds {
+		show := s.newItem("show", seed.title)
+		show.year = seed.year
+		show.tvdbID = seed.tvdbID
+		show.imdbID = seed.imdbID
+		show.genres = seed.genres
+		show.overview = fmt.Sprintf("%s is a demo show with %d seasons.", seed.title, len(seed.seasons))
+		show.requestedAt = now.Add(-time.Duration(len(showSeeds)-i) * 72 * time.Hour)
+		show.updatedAt = show.requestedAt
+
+		total := 0
+		for _, count := range seed.seasons {
+			total += count
+		}
+
+		index := 0
+		for sn, count := range seed.seasons {
+			season := s.newItem("season", fmt.Sprintf("Season %d", sn+1))
+			season.number = sn + 1
+			season.parent = show
+			season.requestedAt = show.requestedAt
+			season.updatedAt = show.updatedAt
+			show.children = append(show.children, season)
+
+			for en := 0; en < count; en++ {
+				ep := s.newItem("episode", fmt.Sprintf("Episode %d", en+1))
+				ep.number = en + 1
+				ep.parent = season
+				ep.requestedAt = show.requestedAt
+				ep.updatedAt = show.updatedAt
+
+				// Space episodes a week apart, ending with the last one
+				// airing in the near future so every show has an agenda
+				aired := now.AddDate(0, 0, 7*(index-total+2)).Truncate(24 * time.Hour)
+				ep.airedAt = &aired
+
+				switch {
+				case aired.After(now):
+					ep.state = models.StateUnreleased
+				case index < seed.complete:
+					ep.state = models.StateCompleted
+				case index == seed.complete:
+					ep.state = models.StateFailed
+				default:
+					ep.state = models.StateRequested
+				}
+				s.generateStreams(ep)
+				s.syncFile(ep)
+
+				season.children = append(season.children, ep)
+				index++
+			}
+			s.recompute(season)
+		}
+		s.recompute(show)
+	}
+}
+
+// newItem allocates a new item and registers it in the library
+func (s *Server) newItem(kind, title string) *item {
+	s.nextItemID++
+	it := &item{
+		id:          s.nextItemID,
+		kind:        kind,
+		title:       title,
+		state:       models.StateRequested,
+		requestedAt: s.now(),
+		updatedAt:   s.now(),
+	}
+	s.items[it.id] = it
+	return it
+}
+
+// removeItem detaches an item from its parent and deletes it, together with
+// all of its children, from the library
+func (s *Server) removeItem(it *item) {
+	if it.parent != nil {
+		var siblings []*item
+		for _, c := range it.parent.children {
+			if c != it {
+				siblings = append(siblings, c)
+			}
+		}
+		it.parent.children = siblings
+		s.recompute(it.parent)
+	}
+	s.forget(it)
+}
+
+// forget deletes an item and its children from the index
+func (s *Server) forget(it *item) {
+	for _, child := range it.children {
+		s.forget(child)
+	}
+	delete(s.items, it.id)
+}
+
+// root returns the top level movie or show an item belongs to
+func (it *item) root() *item {
+	for it.parent != nil {
+		it = it.parent
+	}
+	return it
+}
+
+// displayTitle returns the title including the show/season context
+func (it *item) displayTitle() string {
+	switch it.kind {
+	case "season":
+		return fmt.Sprintf("%s S%02d", it.root().title, it.number)
+	case "episode":
+		return fmt.Sprintf("%s S%02dE%02d", it.root().title, it.parent.number, it.number)
+	}
+	return it.title
+}
+
+// leaves returns the movies and episodes under an item
+func (it *item) leaves() []*item {
+	if it.kind == "movie" || it.kind == "episode" {
+		return []*item{it}
+	}
+	var leaves []*item
+	for _, child := range it.children {
+		leaves = append(leaves, child.leaves()...)
+	}
+	return leaves
+}
+
+// pipelineIndex returns the position of a state in the pipeline, or -1
+func pipelineIndex(state models.States) int {
+	for i, st := range pipeline {
+		if st == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// setState changes the state of an item and records the transition
+func (s *Server) setState(it *item, state models.States) {
+	if it.state == state {
+		return
+	}
+	previous := it.state
+	it.lastState = previous
+	it.state = state
+	it.updatedAt = s.now()
+	s.syncFile(it)
+
+	s.publish("item_update", fmt.Sprintf("%s moved to %s", it.displayTitle(), state), map[string]interface{}{
+		"item_id":    strconv.Itoa(it.id),
+		"type":       it.kind,
+		"title":      it.displayTitle(),
+		"state":      string(state),
+		"last_state": string(previous),
+	})
+	s.logf("INFO", "program.state_transition", "%s changed state from %s to %s", it.displayTitle(), previous, state)
+
+	if it.parent != nil {
+		s.recompute(it.parent)
+	}
+}
+
+// recompute derives the state of a show or season from its children
+func (s *Server) recompute(it *item) {
+	if len(it.children) == 0 {
+		return
+	}
+
+	counts := map[models.States]int{}
+	lowest := len(pipeline)
+	for _, child := range it.children {
+		counts[child.state]++
+		if idx := pipelineIndex(child.state); idx >= 0 && idx < lowest {
+			lowest = idx
+		}
+	}
+
+	total := len(it.children)
+	var state models.States
+	switch {
+	case counts[models.StateCompleted] == total:
+		state = models.StateCompleted
+	case counts[models.StateUnreleased] == total:
+		state = models.StateUnreleased
+	case counts[models.StatePaused] == total:
+		state = models.StatePaused
+	case counts[models.StateCompleted]+counts[models.StateUnreleased] == total:
+		state = models.StateOngoing
+	case counts[models.StateCompleted] > 0 || counts[models.StatePartiallyCompleted] > 0:
+		state = models.StatePartiallyCompleted
+	case counts[models.StateFailed] > 0:
+		state = models.StateFailed
+	case lowest < len(pipeline):
+		state = pipeline[lowest]
+	default:
+		state = models.StateUnknown
+	}
+
+	if state != it.state {
+		s.setState(it, state)
+	} else if it.parent != nil {
+		s.recompute(it.parent)
+	}
+}
+
+// advance moves up to limit items one step along the pipeline. Unreleased
+// episodes whose air date has passed are requested.
+func (s *Server) advance(limit int) int {
+	var candidates []*item
+	for _, it := range s.items {
+		if it.kind != "movie" && it.kind != "episode" {
+			continue
+		}
+		switch {
+		case it.state == models.StateUnreleased && it.airedAt != nil && !it.airedAt.After(s.now()):
+			candidates = append(candidates, it)
+		case pipelineIndex(it.state) >= 0 && it.state != models.StateCompleted:
+			candidates = append(candidates, it)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].updatedAt.Before(candidates[j].updatedAt) ||
+			(candidates[i].updatedAt.Equal(candidates[j].updatedAt) && candidates[i].id < candidates[j].id)
+	})
+
+	moved := 0
+	for _, it := range candidates {
+		if moved >= limit {
+			break
+		}
+		if it.state == models.StateUnreleased {
+			s.setState(it, models.StateRequested)
+		} else {
+			s.setState(it, pipeline[pipelineIndex(it.state)+1])
+		}
+		moved++
+	}
+	return moved
+}
+
+// syncFile keeps the mount path of an item in line with its state
+func (s *Server) syncFile(it *item) {
+	if it.kind != "movie" && it.kind != "episode" {
+		return
+	}
+	switch it.state {
+	case models.StateSymlinked, models.StateCompleted:
+		// Leave a few completed items without a file so the mount browser
+		// has something to flag
+		if it.id%11 == 0 {
+			it.file = ""
+			return
+		}
+		if it.file == "" {
+			it.file = s.filePath(it)
+		}
+	default:
+		it.file = ""
+	}
+}
+
+// filePath builds the library path Riven would symlink an item to
+func (s *Server) filePath(it *item) string {
+	if it.kind == "movie" {
+		name := fmt.Sprintf("%s (%d)", it.title, it.year)
+		return fmt.Sprintf("/movies/%s {tmdb-%s}/%s.mkv", name, it.tmdbID, name)
+	}
+	show := it.root()
+	name := fmt.Sprintf("%s (%d)", show.title, show.year)
+	return fmt.Sprintf("/shows/%s {tvdb-%s}/Season %02d/%s - s%02de%02d.mkv",
+		name, show.tvdbID, it.parent.number, name, it.parent.number, it.number)
+}
+
+// rclonePath returns the file inside the debrid mount the symlink points to
+func (s *Server) rclonePath(it *item) string {
+	best := ""
+	for _, st := range it.streams {
+		if !st.blacklisted {
+			best = st.rawTitle
+			break
+		}
+	}
+	if best == "" {
+		best = strings.ReplaceAll(it.displayTitle(), " ", ".")
+	}
+	return fmt.Sprintf("/mnt/zurg/__all__/%s/%s.mkv", best, best)
+}
+
+// generateStreams creates a deterministic set of streams for an item
+func (s *Server) generateStreams(it *item) {
+	if it.kind != "movie" && it.kind != "episode" {
+		return
+	}
+
+	base := strings.ReplaceAll(it.displayTitle(), " ", ".")
+	variants := []struct {
+		resolution string
+		codec      string
+		hdr        []string
+		audio      []string
+		size       string
+		rank       int
+		cached     bool
+		trash      bool
+		group      string
+	}{
+		{"2160p", "hevc", []string{"DV", "HDR10"}, []string{"Atmos", "TrueHD"}, "58.3 GB", 9800, true, false, "FraMeSToR"},
+		{"2160p", "hevc", []string{"HDR10"}, []string{"DTS-HD MA"}, "21.7 GB", 8600, false, false, "SWTYBLZ"},
+		{"1080p", "avc", nil, []string{"DTS"}, "12.1 GB", 7200, true, false, "SPARKS"},
+		{"1080p", "hevc", nil, []string{"AAC"}, "2.4 GB", 5400, true, false, "YTS"},
+		{"720p", "avc", nil, []string{"AC3"}, "1.1 GB", 3100, false, false, "GalaxyRG"},
+		{"480p", "xvid", nil, []string{"MP3"}, "700 MB", -1200, false, true, "CAM"},
+	}
+
+	for i, v := range variants {
+		s.nextStreamID++
+		raw := fmt.Sprintf("%s.%s.%s.%s-%s", base, v.resolution, strings.ToUpper(v.codec), strings.Join(append(append([]string{}, v.hdr...), v.audio...), "."), v.group)
+		raw = strings.ReplaceAll(raw, "..", ".")
+		languages := []string{"en"}
+		if (it.id+i)%4 == 0 {
+			languages = append(languages, "fr", "de")
+		}
+		it.streams = append(it.streams, &stream{
+			id:         s.nextStreamID,
+			infoHash:   fmt.Sprintf("%040x", it.id*1000003+i*7919),
+			rawTitle:   raw,
+			resolution: v.resolution,
+			codec:      v.codec,
+			hdr:        v.hdr,
+			audio:      v.audio,
+			languages:  languages,
+			size:       v.size,
+			rank:       v.rank - (it.id%5)*10,
+			levRatio:   0.9 + float64(i%3)/30,
+			cached:     v.cached,
+			trash:      v.trash,
+		})
+	}
+}
+
+// toModel converts a stream into the API representation
+func (st *stream) toModel(it *item) models.Stream {
+	parsed := models.ParsedData{
+		RawTitle:        st.rawTitle,
+		ParsedTitle:     it.root().title,
+		NormalizedTitle: strings.ToLower(it.root().title),
+		Trash:           st.trash,
+		Resolution:      st.resolution,
+		Languages:       st.languages,
+		HDR:             st.hdr,
+		Codec:           models.StringPtr(st.codec),
+		Audio:           st.audio,
+		Size:            models.StringPtr(st.size),
+	}
+	if it.root().year != 0 {
+		parsed.Year = models.IntPtr(it.root().year)
+	}
+	if it.kind == "episode" {
+		parsed.Seasons = []int{it.parent.number}
+		parsed.Episodes = []int{it.number}
+	}
+
+	return models.Stream{
+		ID:          st.id,
+		InfoHash:    st.infoHash,
+		RawTitle:    st.rawTitle,
+		ParsedTitle: it.root().title,
+		ParsedData:  parsed,
+		Rank:        st.rank,
+		LevRatio:    st.levRatio,
+		IsCached:    st.cached,
+		Blacklisted: st.blacklisted,
+	}
+}
+
+// toMap converts an item into the dictionary returned by the API. Extended
+// dictionaries include seasons/episodes, and optionally streams.
+func (it *item) toMap(extended, withStreams bool) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":           strconv.Itoa(it.id),
+		"title":        it.title,
+		"type":         it.kind,
+		"state":        string(it.state),
+		"tmdb_id":      nullable(it.tmdbID),
+		"tvdb_id":      nullable(it.tvdbID),
+		"imdb_id":      nullable(it.imdbID),
+		"requested_at": it.requestedAt.Format(time.RFC3339),
+		"updated_at":   it.updatedAt.Format(time.RFC3339),
+		"requested_by": "fakeriven",
+		"is_anime":     false,
+		"genres":       it.genres,
+		"overview":     it.overview,
+	}
+	if it.year != 0 {
+		m["year"] = it.year
+	} else {
+		m["year"] = nil
+	}
+	if it.lastState != "" {
+		m["last_state"] = string(it.lastState)
+	}
+	if it.airedAt != nil {
+		m["aired_at"] = it.airedAt.Format("2006-01-02 15:04:05")
+	}
+	if it.number != 0 {
+		m["number"] = it.number
+	}
+	if it.parent != nil {
+		m["parent_id"] = strconv.Itoa(it.parent.id)
+	}
+
+	if extended {
+		switch it.kind {
+		case "show":
+			var seasons []map[string]interface{}
+			for _, season := range it.children {
+				seasons = append(seasons, season.toMap(true, withStreams))
+			}
+			m["seasons"] = seasons
+		case "season":
+			var episodes []map[string]interface{}
+			for _, ep := range it.children {
+				episodes = append(episodes, ep.toMap(true, withStreams))
+			}
+			m["episodes"] = episodes
+		}
+	}
+
+	if withStreams {
+		streams := []models.Stream{}
+		for _, st := range it.streams {
+			streams = append(streams, st.toModel(it))
+		}
+		m["streams"] = streams
+	}
+
+	return m
+}
+
+// nullable returns nil for empty strings so they encode as JSON null
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package fakeriven
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+// logIcons mirrors the level icons of Riven's loguru configuration
+var logIcons = map[string]string{
+	"TRACE":    "✏️",
+	"DEBUG":    "🤖",
+	"INFO":     "📰",
+	"SUCCESS":  "✔️",
+	"WARNING":  "⚠️",
+	"ERROR":    "❌",
+	"CRITICAL": "💥",
+	"PROGRAM":  "🤖",
+}
+
+// formatLog formats a line the way Riven writes its log file
+func formatLog(at time.Time, level, module, message string) string {
+	return fmt.Sprintf("%s | %s %-9s | %s - %s", at.Format("06-01-02 15:04:05"), logIcons[level], level, module, message)
+}
+
+// logf appends a log line. The caller must hold s.mu.
+func (s *Server) logf(level, module, format string, args ...interface{}) {
+	if s.seeding {
+		return
+	}
+	s.appendLog(formatLog(s.now(), level, module, fmt.Sprintf(format, args...)))
+}
+
+// appendLog appends a formatted line, dropping the oldest beyond maxLogs.
+// Every line is also published as a logging event.
+func (s *Server) appendLog(line string) {
+	s.logs = append(s.logs, line)
+	if len(s.logs) > s.maxLogs {
+		s.logs = s.logs[len(s.logs)-s.maxLogs:]
+	}
+	s.publish("logging", line, map[string]interface{}{"message": line})
+}
+
+// seedLogs writes the startup log of a running Riven instance
+func (s *Server) seedLogs() {
+	start := s.now().Add(-10 * time.Minute)
+	lines := []struct {
+		level, module, message string
+	}{
+		{"PROGRAM", "program.start", "Riven v" + Version + " starting!"},
+		{"INFO", "program.db.db", "Database connection established"},
+		{"DEBUG", "program.settings.manager", "Loaded settings from settings.json"},
+		{"INFO", "program.services.indexers.trakt", "Trakt initialized!"},
+		{"INFO", "program.services.scrapers.torrentio", "Torrentio initialized!"},
+		{"WARNING", "program.services.scrapers.jackett", "Jackett is not configured and will not be used."},
+		{"INFO", "program.services.downloaders.realdebrid", "Real-Debrid initialized!"},
+		{"INFO", "program.symlink", "Rclone path symlinks are pointed to: /mnt/zurg/__all__"},
+		{"ERROR", "program.services.updaters.plex", "Failed to connect to Plex at http://plex:32400, retrying in 30s"},
+		{"SUCCESS", "program.services.updaters.plex", "Plex Updater initialized!"},
+		{"DEBUG", "program.program", "Scheduled retry_library every 24h"},
+		{"PROGRAM", "program.program", fmt.Sprintf("Riven is running with %d items in the library", len(s.items))},
+	}
+
+	for i, l := range lines {
+		s.appendLog(formatLog(start.Add(time.Duration(i)*7*time.Second), l.level, l.module, l.message))
+	}
+	// The startup lines predate any subscriber, so they do not need to be events
+	s.events = nil
+	s.nextEventID = 0
+}
+
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := make([]string, len(s.logs))
+	copy(logs, s.logs)
+	writeJSON(w, http.StatusOK, models.LogsResponse{Logs: logs})
+}
+
+func (s *Server) handleUploadLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logf("INFO", "program.api", "Uploaded %d log lines", len(s.logs))
+	writeJSON(w, http.StatusOK, models.UploadLogsResponse{
+		Success: true,
+		URL:     fmt.Sprintf("https://paste.c-net.org/Demo%d", s.now().Unix()%100000),
+	})
+}
@@ -0,0 +1,430 @@
+package fakeriven
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+// sessionTTL is how long a manual scraping session stays valid
+const sessionTTL = 5 * time.Minute
+
+// session is a manual scraping session
+type session struct {
+	id         string
+	item       *item
+	infoHash   string
+	name       string
+	files      []models.DebridFile
+	selected   []models.DebridFile
+	attributes json.RawMessage
+	expiresAt  time.Time
+}
+
+var (
+	magnetHash  = regexp.MustCompile(`(?i)xt=urn:btih:([a-f0-9]{40})`)
+	bareHash    = regexp.MustCompile(`(?i)^[a-f0-9]{40}$`)
+	episodeCode = regexp.MustCompile(`(?i)s(\d{1,2})e(\d{1,3})`)
+	resolutions = regexp.MustCompile(`(?i)(2160p|1080p|720p|480p)`)
+	codecs      = regexp.MustCompile(`(?i)(hevc|x265|avc|x264|xvid|av1)`)
+)
+
+// findTarget resolves the item addressed by the item_id/tmdb_id/tvdb_id/imdb_id
+// query parameters
+func (s *Server) findTarget(r *http.Request) (*item, bool) {
+	query := r.URL.Query()
+	if id := query.Get("item_id"); id != "" {
+		return s.lookup(id)
+	}
+	for _, it := range s.sortedItems() {
+		if it.kind != "movie" && it.kind != "show" {
+			continue
+		}
+		switch {
+		case query.Get("tmdb_id") != "" && it.tmdbID == query.Get("tmdb_id"),
+			query.Get("tvdb_id") != "" && it.tvdbID == query.Get("tvdb_id"),
+			query.Get("imdb_id") != "" && it.imdbID == query.Get("imdb_id"):
+			return it, true
+		}
+	}
+	return nil, false
+}
+
+// scrapeStreams returns the streams a scrape would find for an item. Shows
+// and seasons return the streams of their episodes plus season packs.
+func (s *Server) scrapeStreams(it *item) map[string]models.Stream {
+	result := map[string]models.Stream{}
+
+	for _, leaf := range it.leaves() {
+		for _, st := range leaf.streams {
+			if !st.blacklisted {
+				result[st.infoHash] = st.toModel(leaf)
+			}
+		}
+		if len(result) >= 40 {
+			break
+		}
+	}
+
+	if it.kind == "show" || it.kind == "season" {
+		seasons := it.children
+		if it.kind == "season" {
+			seasons = []*item{it}
+		}
+		for _, season := range seasons {
+			for i, res := range []string{"2160p", "1080p"} {
+				hash := fmt.Sprintf("%040x", season.id*7777777+i)
+				raw := fmt.Sprintf("%s.S%02d.COMPLETE.%s.WEB-DL.DDP5.1.H.265-NTb", strings.ReplaceAll(season.root().title, " ", "."), season.number, res)
+				size := fmt.Sprintf("%d.%d GB", 8*(2-i)+len(season.children), season.id%10)
+				result[hash] = models.Stream{
+					InfoHash:    hash,
+					RawTitle:    raw,
+					ParsedTitle: season.root().title,
+					Rank:        9000 - i*1500 + len(season.children),
+					LevRatio:    1,
+					IsCached:    i == 1 || season.number%2 == 0,
+					ParsedData: models.ParsedData{
+						RawTitle:    raw,
+						ParsedTitle: season.root().title,
+						Resolution:  res,
+						Seasons:     []int{season.number},
+						Complete:    true,
+						Languages:   []string{"en"},
+						Codec:       models.StringPtr("hevc"),
+						Audio:       []string{"Dolby Digital Plus"},
+						Size:        models.StringPtr(size),
+					},
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *Server) handleScrape(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("item_id") == "" && query.Get("tmdb_id") == "" && query.Get("tvdb_id") == "" && query.Get("imdb_id") == "" {
+		writeDetail(w, http.StatusBadRequest, "No item ID provided")
+		return
+	}
+
+	if s.scrapeDelay > 0 {
+		select {
+		case <-time.After(s.scrapeDelay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.findTarget(r)
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	streams := s.scrapeStreams(it)
+	s.logf("INFO", "scrapers.scrape", "Scraped %d streams for %s", len(streams), it.displayTitle())
+	writeJSON(w, http.StatusOK, models.ScrapeItemResponse{
+		Message: fmt.Sprintf("Manually scraped streams for item %s", it.displayTitle()),
+		Streams: streams,
+	})
+}
+
+func (s *Server) handleStartSession(w http.ResponseWriter, r *http.Request) {
+	magnet := r.URL.Query().Get("magnet")
+	if magnet == "" {
+		writeValidation(w, missingQuery("magnet"))
+		return
+	}
+
+	infoHash := ""
+	if m := magnetHash.FindStringSubmatch(magnet); m != nil {
+		infoHash = strings.ToLower(m[1])
+	} else if bareHash.MatchString(magnet) {
+		infoHash = strings.ToLower(magnet)
+	} else {
+		writeDetail(w, http.StatusBadRequest, "Invalid magnet link")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, ok := s.findTarget(r)
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Item not found")
+		return
+	}
+
+	s.expireSessions()
+
+	s.nextSession++
+	sess := &session{
+		id:        fmt.Sprintf("%08x-fake-4000-8000-%012x", s.nextSession, s.now().UnixNano()%0xffffffffffff),
+		item:      it,
+		infoHash:  infoHash,
+		expiresAt: s.now().Add(sessionTTL),
+	}
+	sess.name, sess.files = s.torrentFiles(it, infoHash)
+	s.sessions[sess.id] = sess
+
+	filesInfo := map[string]interface{}{}
+	var total int64
+	for i, f := range sess.files {
+		filesInfo[strconv.Itoa(i+1)] = map[string]interface{}{"filename": f.Filename, "bytes": f.Filesize}
+		total += f.Filesize
+	}
+
+	status := "downloaded"
+	progress := 100.0
+	s.logf("INFO", "program.manual_scrape", "Started manual session %s for %s", sess.id, it.displayTitle())
+	writeJSON(w, http.StatusOK, models.StartSessionResponse{
+		Message:   "Started manual scraping session",
+		SessionID: sess.id,
+		TorrentID: fmt.Sprintf("RD%d", s.nextSession),
+		TorrentInfo: models.TorrentInfo{
+			ID:       fmt.Sprintf("RD%d", s.nextSession),
+			Name:     sess.name,
+			Status:   &status,
+			InfoHash: &infoHash,
+			Progress: &progress,
+			Bytes:    &total,
+			Files:    filesInfo,
+		},
+		Containers: &models.TorrentContainer{InfoHash: infoHash, Files: sess.files},
+		ExpiresAt:  sess.expiresAt.UTC().Format("2006-01-02T15:04:05.999999"), // naive, as Riven sends it
+	})
+}
+
+// torrentFiles returns the name and file list of the torrent with the given
+// hash, as the debrid service would report it
+func (s *Server) torrentFiles(it *item, infoHash string) (string, []models.DebridFile) {
+	name := ""
+	for _, leaf := range it.leaves() {
+		for _, st := range leaf.streams {
+			if st.infoHash == infoHash {
+				name = st.rawTitle
+			}
+		}
+	}
+	base := strings.ReplaceAll(it.root().title, " ", ".")
+	if name == "" {
+		name = base + ".1080p.WEB-DL"
+	}
+
+	var files []models.DebridFile
+	add := func(filename string, size int64) {
+		files = append(files, models.DebridFile{
+			ID:       strconv.Itoa(len(files) + 1),
+			Filename: filename,
+			Filesize: size,
+		})
+	}
+
+	if it.kind == "movie" {
+		add(name+".mkv", 14_500_000_000+int64(it.id)*1_000_000)
+		add("Sample/"+name+".sample.mkv", 48_000_000)
+		add("RARBG.txt", 31)
+		return name, files
+	}
+
+	for _, leaf := range it.leaves() {
+		add(fmt.Sprintf("%s.S%02dE%02d.1080p.WEB-DL.mkv", base, leaf.parent.number, leaf.number), 2_100_000_000+int64(leaf.id)*1_000)
+	}
+	add("Sample/sample.mkv", 35_000_000)
+	add("NFO/"+name+".nfo", 4_200)
+	return name, files
+}
+
+// expireSessions drops sessions whose deadline has passed
+func (s *Server) expireSessions() {
+	for id, sess := range s.sessions {
+		if s.now().After(sess.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// activeSession returns the session named in the request path
+func (s *Server) activeSession(w http.ResponseWriter, r *http.Request) (*session, bool) {
+	s.expireSessions()
+	sess, ok := s.sessions[r.PathValue("session")]
+	if !ok {
+		writeDetail(w, http.StatusNotFound, "Session not found or expired")
+		return nil, false
+	}
+	return sess, true
+}
+
+func (s *Server) handleSelectFiles(w http.ResponseWriter, r *http.Request) {
+	var container models.Container
+	if err := json.NewDecoder(r.Body).Decode(&container); err != nil {
+		writeValidation(w, models.ValidationError{Loc: []interface{}{"body"}, Msg: "Input should be a valid container", Type: "model_type"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.activeSession(w, r)
+	if !ok {
+		return
+	}
+
+	known := map[string]bool{}
+	for _, f := range sess.files {
+		known[f.Filename] = true
+	}
+	if len(container.Files) == 0 {
+		writeDetail(w, http.StatusBadRequest, "No files selected")
+		return
+	}
+	for _, f := range container.Files {
+		if !known[f.Filename] {
+			writeDetail(w, http.StatusBadRequest, fmt.Sprintf("File %s is not part of the torrent", f.Filename))
+			return
+		}
+	}
+
+	sess.selected = container.Files
+	writeJSON(w, http.StatusOK, models.SelectFilesResponse{
+		Message:      fmt.Sprintf("Selected %d files for %s", len(container.Files), sess.item.displayTitle()),
+		DownloadType: models.DownloadTypeCached,
+	})
+}
+
+func (s *Server) handleUpdateAttributes(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeValidation(w, models.ValidationError{Loc: []interface{}{"body"}, Msg: "Invalid JSON", Type: "json_invalid"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.activeSession(w, r)
+	if !ok {
+		return
+	}
+	if len(sess.selected) == 0 {
+		writeDetail(w, http.StatusBadRequest, "Select files before updating attributes")
+		return
+	}
+
+	if sess.item.kind == "movie" {
+		var file models.DebridFile
+		if err := json.Unmarshal(body, &file); err != nil || file.Filename == "" {
+			writeValidation(w, models.ValidationError{Loc: []interface{}{"body", "filename"}, Msg: "Field required", Type: "missing"})
+			return
+		}
+	} else {
+		var data models.ShowFileData
+		if err := json.Unmarshal(body, &data); err != nil || len(data) == 0 {
+			writeValidation(w, models.ValidationError{Loc: []interface{}{"body"}, Msg: "Input should be a mapping of seasons to episodes", Type: "dict_type"})
+			return
+		}
+	}
+
+	sess.attributes = body
+	writeJSON(w, http.StatusOK, models.UpdateAttributesResponse{
+		Message: fmt.Sprintf("Updated given data to %s", sess.item.displayTitle()),
+	})
+}
+
+func (s *Server) handleAbortSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.activeSession(w, r)
+	if !ok {
+		return
+	}
+	delete(s.sessions, sess.id)
+	s.logf("INFO", "program.manual_scrape", "Aborted manual session %s", sess.id)
+	writeJSON(w, http.StatusOK, models.SessionResponse{Message: fmt.Sprintf("Aborted session %s", sess.id)})
+}
+
+func (s *Server) handleCompleteSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.activeSession(w, r)
+	if !ok {
+		return
+	}
+	if len(sess.selected) == 0 || sess.attributes == nil {
+		writeDetail(w, http.StatusBadRequest, "Session is incomplete: select files and update attributes first")
+		return
+	}
+
+	if sess.item.kind == "movie" {
+		s.setState(sess.item, models.StateDownloaded)
+	} else {
+		var data models.ShowFileData
+		json.Unmarshal(sess.attributes, &data)
+		for _, leaf := range sess.item.leaves() {
+			if episodes, ok := data[leaf.parent.number]; ok {
+				if _, ok := episodes[leaf.number]; ok {
+					s.setState(leaf, models.StateDownloaded)
+				}
+			}
+		}
+	}
+
+	delete(s.sessions, sess.id)
+	s.logf("INFO", "program.manual_scrape", "Completed manual session %s for %s", sess.id, sess.item.displayTitle())
+	writeJSON(w, http.StatusOK, models.SessionResponse{Message: "Session completed successfully"})
+}
+
+func (s *Server) handleParse(w http.ResponseWriter, r *http.Request) {
+	var titles []string
+	if err := json.NewDecoder(r.Body).Decode(&titles); err != nil {
+		writeValidation(w, models.ValidationError{Loc: []interface{}{"body"}, Msg: "Input should be a valid list", Type: "list_type"})
+		return
+	}
+
+	data := []map[string]interface{}{}
+	for _, title := range titles {
+		parsed := map[string]interface{}{"raw_title": title}
+		if m := resolutions.FindString(title); m != "" {
+			parsed["resolution"] = strings.ToLower(m)
+		}
+		if m := codecs.FindString(title); m != "" {
+			parsed["codec"] = strings.ToLower(m)
+		}
+		if m := episodeCode.FindStringSubmatch(title); m != nil {
+			season, _ := strconv.Atoi(m[1])
+			episode, _ := strconv.Atoi(m[2])
+			parsed["seasons"] = []int{season}
+			parsed["episodes"] = []int{episode}
+		}
+		data = append(data, parsed)
+	}
+
+	writeJSON(w, http.StatusOK, models.ParseTorrentTitleResponse{Message: "Parsed torrent titles", Data: data})
+}
+
+// sessionIDs returns the IDs of the open sessions, mainly for tests
+func (s *Server) sessionIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
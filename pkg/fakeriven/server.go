@@ -0,0 +1,420 @@
+// Package fakeriven implements an in-process fake of the Riven API backed by
+// an in-memory library. It powers the offline demo mode and end-to-end tests
+// of the API client and the TUI screens.
+package fakeriven
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+// Version is the Riven version reported by the fake server
+const Version = "0.21.21-fake"
+
+// Server is a fake Riven API server. It implements http.Handler.
+type Server struct {
+	mu sync.Mutex
+
+	token string
+	clock func() time.Time
+	mux   *http.ServeMux
+
+	// Library
+	items        map[int]*item
+	nextItemID   int
+	nextStreamID int
+
+	// Settings
+	settings map[string]interface{}
+	saved    map[string]interface{}
+
+	// Scraping
+	sessions    map[string]*session
+	nextSession int
+	scrapeDelay time.Duration
+
+	// Logs and events
+	logs        []string
+	maxLogs     int
+	events      []sseEvent
+	nextEventID int
+	subscribers map[chan sseEvent]struct{}
+	seeding     bool
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithToken requires requests to carry the given bearer token
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithClock overrides the time source, mainly for deterministic tests
+func WithClock(clock func() time.Time) Option {
+	return func(s *Server) {
+		s.clock = clock
+	}
+}
+
+// WithScrapeDelay makes scrape requests take the given time, to mimic the
+// latency of real scrapers
+func WithScrapeDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.scrapeDelay = delay
+	}
+}
+
+// New creates a fake server seeded with the demo library
+func New(opts ...Option) *Server {
+	s := &Server{
+		clock:       time.Now,
+		items:       make(map[int]*item),
+		sessions:    make(map[string]*session),
+		subscribers: make(map[chan sseEvent]struct{}),
+		maxLogs:     1000,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.seeding = true
+	s.seed()
+	s.settings = defaultSettings()
+	s.saved = cloneMap(s.settings)
+	s.seeding = false
+	s.seedLogs()
+
+	s.routes()
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeDetail(w, http.StatusUnauthorized, "Invalid or missing bearer token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// Advance moves up to limit items one step through the state pipeline and
+// returns how many items changed state
+func (s *Server) Advance(limit int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.advance(limit)
+}
+
+// Run advances the library every interval until the context is cancelled
+func (s *Server) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Advance(2)
+		}
+	}
+}
+
+// now returns the current time of the fake server
+func (s *Server) now() time.Time {
+	return s.clock().UTC()
+}
+
+// routes registers every endpoint used by api.Client
+func (s *Server) routes() {
+	mux := http.NewServeMux()
+
+	// System
+	mux.HandleFunc("GET /api/v1/{$}", s.handleRoot)
+	mux.HandleFunc("GET /api/v1/health", s.handleHealth)
+	mux.HandleFunc("GET /api/v1/stats", s.handleStats)
+	mux.HandleFunc("GET /api/v1/services", s.handleServices)
+	mux.HandleFunc("GET /api/v1/logs", s.handleLogs)
+	mux.HandleFunc("POST /api/v1/upload_logs", s.handleUploadLogs)
+	mux.HandleFunc("GET /api/v1/events", s.handleEvents)
+	mux.HandleFunc("GET /api/v1/rd", s.handleRDUser)
+	mux.HandleFunc("POST /api/v1/generateapikey", s.handleGenerateAPIKey)
+	mux.HandleFunc("GET /api/v1/mount", s.handleMount)
+	mux.HandleFunc("GET /api/v1/calendar", s.handleCalendar)
+	mux.HandleFunc("GET /api/v1/trakt/oauth/initiate", s.handleTraktInitiate)
+	mux.HandleFunc("GET /api/v1/trakt/oauth/callback", s.handleTraktCallback)
+	mux.HandleFunc("POST /api/v1/webhook/overseerr", s.handleOverseerrWebhook)
+
+	// Items
+	mux.HandleFunc("GET /api/v1/items", s.handleItems)
+	mux.HandleFunc("GET /api/v1/items/states", s.handleStates)
+	mux.HandleFunc("GET /api/v1/items/{id}", s.handleItem)
+	mux.HandleFunc("POST /api/v1/items/add", s.handleAddItems)
+	mux.HandleFunc("DELETE /api/v1/items/remove", s.handleItemAction("Removed", s.removeAction))
+	mux.HandleFunc("POST /api/v1/items/retry", s.handleItemAction("Retried", s.retryAction))
+	mux.HandleFunc("POST /api/v1/items/reset", s.handleItemAction("Reset", s.resetAction))
+	mux.HandleFunc("POST /api/v1/items/pause", s.handleItemAction("Paused", s.pauseAction))
+	mux.HandleFunc("POST /api/v1/items/unpause", s.handleItemAction("Unpaused", s.unpauseAction))
+	mux.HandleFunc("POST /api/v1/items/retry_library", s.handleRetryLibrary)
+	mux.HandleFunc("POST /api/v1/items/update_ongoing", s.handleUpdateOngoing)
+	mux.HandleFunc("POST /api/v1/items/update_new_releases", s.handleUpdateNewReleases)
+	mux.HandleFunc("POST /api/v1/items/reindex", s.handleReindex)
+	mux.HandleFunc("POST /api/v1/items/ffprobe", s.handleFfprobe)
+	mux.HandleFunc("GET /api/v1/items/{first}/{second}", s.handleItemSubpath)
+	mux.HandleFunc("POST /api/v1/items/{id}/streams/reset", s.handleResetStreams)
+	mux.HandleFunc("POST /api/v1/items/{id}/streams/{stream}/blacklist", s.handleBlacklist(true))
+	mux.HandleFunc("POST /api/v1/items/{id}/streams/{stream}/unblacklist", s.handleBlacklist(false))
+
+	// Settings
+	mux.HandleFunc("GET /api/v1/settings/schema", s.handleSettingsSchema)
+	mux.HandleFunc("GET /api/v1/settings/load", s.handleLoadSettings)
+	mux.HandleFunc("POST /api/v1/settings/save", s.handleSaveSettings)
+	mux.HandleFunc("GET /api/v1/settings/get/all", s.handleGetAllSettings)
+	mux.HandleFunc("GET /api/v1/settings/get/{paths}", s.handleGetSettings)
+	mux.HandleFunc("POST /api/v1/settings/set/all", s.handleSetAllSettings)
+	mux.HandleFunc("POST /api/v1/settings/set", s.handleSetSettings)
+
+	// Scraping
+	mux.HandleFunc("GET /api/v1/scrape/scrape", s.handleScrape)
+	mux.HandleFunc("POST /api/v1/scrape/scrape/start_session", s.handleStartSession)
+	mux.HandleFunc("POST /api/v1/scrape/scrape/select_files/{session}", s.handleSelectFiles)
+	mux.HandleFunc("POST /api/v1/scrape/scrape/update_attributes/{session}", s.handleUpdateAttributes)
+	mux.HandleFunc("POST /api/v1/scrape/scrape/abort_session/{session}", s.handleAbortSession)
+	mux.HandleFunc("POST /api/v1/scrape/scrape/complete_session/{session}", s.handleCompleteSession)
+	mux.HandleFunc("POST /api/v1/scrape/parse", s.handleParse)
+
+	// Streaming
+	mux.HandleFunc("GET /api/v1/stream/event_types", s.handleEventTypes)
+	mux.HandleFunc("GET /api/v1/stream/{type}", s.handleStreamEvent)
+	mux.HandleFunc("GET /api/v1/events/stream", s.handleEventStream)
+
+	s.mux = mux
+}
+
+// writeJSON writes a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeDetail writes a FastAPI style {"detail": "..."} error
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+// writeValidation writes a FastAPI style 422 response
+func writeValidation(w http.ResponseWriter, errs ...models.ValidationError) {
+	writeJSON(w, http.StatusUnprocessableEntity, models.HTTPValidationError{Detail: errs})
+}
+
+// missingQuery returns the validation error for a required query parameter
+func missingQuery(name string) models.ValidationError {
+	return models.ValidationError{
+		Loc:  []interface{}{"query", name},
+		Msg:  "Field required",
+		Type: "missing",
+	}
+}
+
+// splitIDs splits a comma separated list, dropping blanks
+func splitIDs(ids string) []string {
+	var out []string
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.RootResponse{Message: "Riven is running!", Version: Version})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.MessageResponse{Message: "True"})
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := models.StatsResponse{
+		IncompleteRetries: map[string]int{},
+		States:            map[models.States]int{},
+	}
+	for _, it := range s.items {
+		switch it.kind {
+		case "movie":
+			stats.TotalMovies++
+		case "show":
+			stats.TotalShows++
+		case "season":
+			stats.TotalSeasons++
+		case "episode":
+			stats.TotalEpisodes++
+		}
+		if it.kind == "movie" || it.kind == "show" {
+			stats.TotalItems++
+		}
+		stats.States[it.state]++
+		if it.file != "" {
+			stats.TotalSymlinks++
+		}
+		if (it.kind == "movie" || it.kind == "episode") &&
+			it.state != models.StateCompleted && it.state != models.StateUnreleased {
+			stats.IncompleteItems++
+			if it.state == models.StateFailed {
+				stats.IncompleteRetries[fmt.Sprintf("%s (%d)", it.displayTitle(), it.id)] = 3
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.ServicesResponse{
+		"TraktIndexer":         true,
+		"Overseerr":            false,
+		"PlexWatchlist":        true,
+		"Scraping":             true,
+		"Torrentio":            true,
+		"Jackett":              false,
+		"RealDebridDownloader": true,
+		"Symlinker":            true,
+		"PlexUpdater":          true,
+	})
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queues := map[string][]int{}
+	for _, it := range s.items {
+		var service string
+		switch it.state {
+		case models.StateRequested:
+			service = "TraktIndexer"
+		case models.StateIndexed:
+			service = "Scraping"
+		case models.StateScraped:
+			service = "RealDebridDownloader"
+		case models.StateDownloaded:
+			service = "Symlinker"
+		case models.StateSymlinked:
+			service = "PlexUpdater"
+		default:
+			continue
+		}
+		if it.kind == "movie" || it.kind == "episode" {
+			queues[service] = append(queues[service], it.id)
+		}
+	}
+	for _, ids := range queues {
+		sort.Ints(ids)
+	}
+
+	writeJSON(w, http.StatusOK, models.EventResponse{Events: queues})
+}
+
+func (s *Server) handleRDUser(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.RDUser{
+		ID:       424242,
+		Username: "demo",
+		Email:    "demo@example.com",
+		Points:   1250,
+		Locale:   "en",
+		Type:     models.UserTypePremium,
+		Premium:  87 * 24 * 60 * 60,
+	})
+}
+
+func (s *Server) handleGenerateAPIKey(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fmt.Sprintf("%032x", s.now().UnixNano())
+	s.settings["api_key"] = key
+	writeJSON(w, http.StatusOK, models.MessageResponse{Message: key})
+}
+
+func (s *Server) handleMount(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files := map[string]string{}
+	for _, it := range s.items {
+		if it.file != "" {
+			files[it.file] = s.rclonePath(it)
+		}
+	}
+	writeJSON(w, http.StatusOK, models.MountResponse{Files: files})
+}
+
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := map[string]interface{}{}
+	for _, it := range s.items {
+		if it.airedAt == nil || (it.kind != "movie" && it.kind != "episode") {
+			continue
+		}
+		entry := map[string]interface{}{
+			"item_id":    it.id,
+			"show_title": it.root().title,
+			"item_type":  it.kind,
+			"aired_at":   it.airedAt.Format("2006-01-02 15:04:05"),
+			"last_state": string(it.state),
+		}
+		if it.kind == "episode" {
+			entry["tvdb_id"] = it.root().tvdbID
+			entry["season"] = it.parent.number
+			entry["episode"] = it.number
+		} else {
+			entry["tmdb_id"] = it.tmdbID
+		}
+		data[strconv.Itoa(it.id)] = entry
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func (s *Server) handleTraktInitiate(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, models.TraktOAuthInitiateResponse{AuthURL: "https://trakt.tv/oauth/authorize?client_id=fakeriven"})
+}
+
+func (s *Server) handleTraktCallback(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("code") == "" {
+		writeValidation(w, missingQuery("code"))
+		return
+	}
+	writeJSON(w, http.StatusOK, models.MessageResponse{Message: "OAuth token obtained successfully"})
+}
+
+func (s *Server) handleOverseerrWebhook(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+}
+
+// cloneMap deep copies a JSON-like map
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(m)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
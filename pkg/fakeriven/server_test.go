@@ -0,0 +1,154 @@
+package fakeriven
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"riven-tui/pkg/models"
+)
+
+func TestAdvanceMovesItemsThroughPipeline(t *testing.T) {
+	s := New()
+
+	s.mu.Lock()
+	before := map[int]models.States{}
+	for id, it := range s.items {
+		before[id] = it.state
+	}
+	s.mu.Unlock()
+
+	if moved := s.Advance(3); moved != 3 {
+		t.Fatalf("Expected 3 items to move, got %d", moved)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := 0
+	for id, it := range s.items {
+		if it.kind != "movie" && it.kind != "episode" {
+			continue
+		}
+		if it.state != before[id] {
+			changed++
+			if pipelineIndex(it.state) <= pipelineIndex(before[id]) && before[id] != models.StateUnreleased {
+				t.Errorf("Item %d moved backwards from %s to %s", id, before[id], it.state)
+			}
+		}
+	}
+	if changed != 3 {
+		t.Errorf("Expected 3 leaves to change state, got %d", changed)
+	}
+}
+
+func TestShowStateIsAggregated(t *testing.T) {
+	s := New()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, it := range s.items {
+		if it.kind != "show" {
+			continue
+		}
+		for _, season := range it.children {
+			s.pauseAction(season)
+		}
+		if it.state != models.StatePaused {
+			t.Errorf("Expected %s to be Paused once every season is, got %s", it.title, it.state)
+		}
+	}
+}
+
+func TestRequiresToken(t *testing.T) {
+	s := New(WithToken("secret"))
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/health", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", rec.Code)
+	}
+}
+
+func TestEventStreamReplaysAfterLastEventID(t *testing.T) {
+	s := New()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	s.Publish("item_update", "first", map[string]interface{}{"item_id": "1"})
+	s.Publish("item_update", "second", map[string]interface{}{"item_id": "2"})
+
+	req, _ := http.NewRequest("GET", srv.URL+"/api/v1/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	timeout := time.After(2 * time.Second)
+	var id string
+	for {
+		select {
+		case line := <-lines:
+			if strings.HasPrefix(line, "id: ") {
+				id = strings.TrimPrefix(line, "id: ")
+			}
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var ev models.Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev); err != nil {
+				t.Fatalf("Invalid event payload: %v", err)
+			}
+			if id != "2" || ev.Message != "second" {
+				t.Errorf("Expected replay of event 2, got id %s (%s)", id, ev.Message)
+			}
+			return
+		case <-timeout:
+			t.Fatal("No event replayed")
+		}
+	}
+}
+
+func TestManualSessionExpires(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := New(WithClock(func() time.Time { return now }))
+
+	req := httptest.NewRequest("POST", "/api/v1/scrape/scrape/start_session?item_id=1&magnet=0123456789abcdef0123456789abcdef01234567", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected session to start, got %d: %s", rec.Code, rec.Body)
+	}
+	ids := s.sessionIDs()
+	if len(ids) != 1 {
+		t.Fatalf("Expected one session, got %v", ids)
+	}
+
+	now = now.Add(sessionTTL + time.Second)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/scrape/scrape/abort_session/"+ids[0], nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected expired session to be gone, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CalendarResponse represents the calendar response: the movies and
+// episodes of the library that have an air date, keyed by item ID
+type CalendarResponse struct {
+	Data map[string]CalendarEntry `json:"data,omitempty"`
+}
+
+// CalendarEntry is a movie or an episode on the calendar. Entries are
+// decoded as tolerantly as items, see MediaItem.
+type CalendarEntry struct {
+	ItemID    string     `json:"item_id,omitempty"`
+	ShowTitle string     `json:"show_title,omitempty"` // title of the movie, or of the episode's show
+	ItemType  string     `json:"item_type,omitempty"`
+	AiredAt   *time.Time `json:"aired_at,omitempty"`
+	LastState States     `json:"last_state,omitempty"`
+	Season    int        `json:"season,omitempty"`
+	Episode   int        `json:"episode,omitempty"`
+	TMDBId    string     `json:"tmdb_id,omitempty"`
+	TVDBId    string     `json:"tvdb_id,omitempty"`
+
+	// Metadata holds the fields not covered above, by their JSON name
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Entries returns the entries with an air date, oldest first
+func (r *CalendarResponse) Entries() []CalendarEntry {
+	var entries []CalendarEntry
+	for id, entry := range r.Data {
+		if entry.AiredAt == nil {
+			continue
+		}
+		if entry.ItemID == "" {
+			entry.ItemID = id
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.AiredAt.Equal(*b.AiredAt) {
+			return a.AiredAt.Before(*b.AiredAt)
+		}
+		if a.ShowTitle != b.ShowTitle {
+			return a.ShowTitle < b.ShowTitle
+		}
+		if a.Season != b.Season {
+			return a.Season < b.Season
+		}
+		return a.Episode < b.Episode
+	})
+	return entries
+}
+
+// Title names the entry: the movie, or the show and episode number
+func (e CalendarEntry) Title() string {
+	if e.ItemType == "episode" {
+		return fmt.Sprintf("%s S%02dE%02d", e.ShowTitle, e.Season, e.Episode)
+	}
+	return e.ShowTitle
+}
+
+// UnmarshalJSON decodes an entry tolerantly
+func (e *CalendarEntry) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode calendar entry: %w", err)
+	}
+
+	*e = CalendarEntry{}
+	for name, value := range fields {
+		if !e.decodeField(name, value) {
+			if e.Metadata == nil {
+				e.Metadata = make(map[string]interface{})
+			}
+			var v interface{}
+			json.Unmarshal(value, &v)
+			e.Metadata[name] = v
+		}
+	}
+	return nil
+}
+
+// decodeField decodes a known field, reporting false for unknown fields
+// and values that do not decode
+func (e *CalendarEntry) decodeField(name string, value json.RawMessage) bool {
+	switch name {
+	case "item_id":
+		return decodeString(value, &e.ItemID)
+	case "show_title":
+		return decodeString(value, &e.ShowTitle)
+	case "item_type":
+		return decodeString(value, &e.ItemType)
+	case "aired_at":
+		return decodeTime(value, &e.AiredAt)
+	case "last_state":
+		return decodeString(value, (*string)(&e.LastState))
+	case "season":
+		return decodeInt(value, &e.Season)
+	case "episode":
+		return decodeInt(value, &e.Episode)
+	case "tmdb_id":
+		return decodeString(value, &e.TMDBId)
+	case "tvdb_id":
+		return decodeString(value, &e.TVDBId)
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCalendarEntries(t *testing.T) {
+	var calendar CalendarResponse
+	err := json.Unmarshal([]byte(`{"data": {
+		"7": {"item_id": 7, "show_title": "Severance", "item_type": "episode", "aired_at": "2025-03-21 00:00:00", "last_state": "Completed", "season": 2, "episode": "10", "tvdb_id": 371980},
+		"3": {"item_id": 3, "show_title": "Dune: Part Two", "item_type": "movie", "aired_at": "2024-03-01", "tmdb_id": "693134", "rating": 8.5},
+		"9": {"show_title": "Severance", "item_type": "episode", "aired_at": "2025-03-21T00:00:00Z", "season": 2, "episode": 9},
+		"12": {"item_id": 12, "show_title": "Arrival", "item_type": "movie", "aired_at": null}
+	}}`), &calendar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := calendar.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected the entries with an air date, got %+v", entries)
+	}
+	// Oldest first, then by episode
+	if entries[0].ItemID != "3" || entries[1].ItemID != "9" || entries[2].ItemID != "7" {
+		t.Errorf("unexpected order %s, %s, %s", entries[0].ItemID, entries[1].ItemID, entries[2].ItemID)
+	}
+	if got := entries[2].Title(); got != "Severance S02E10" {
+		t.Errorf("unexpected title %q", got)
+	}
+	if entries[2].TVDBId != "371980" || entries[2].LastState != StateCompleted {
+		t.Errorf("unexpected entry %+v", entries[2])
+	}
+	if entries[0].Title() != "Dune: Part Two" || entries[0].Metadata["rating"] != 8.5 {
+		t.Errorf("expected unknown fields in the metadata, got %+v", entries[0])
+	}
+}
@@ -117,6 +117,8 @@ func TimePtr(t time.Time) *time.Time {
 
 // Event represents a real-time event from the server
 type Event struct {
+	// ID is the server-sent event ID, used to resume the stream
+	ID        string                 `json:"-"`
 	Type      string                 `json:"type"`
 	Timestamp string                 `json:"timestamp"`
 	Data      map[string]interface{} `json:"data"`
@@ -1,71 +1,18 @@
 package models
 
-import "time"
-
 // ItemsResponse represents the paginated items response
 type ItemsResponse struct {
 	Success    bool                     `json:"success"`
-	Items      []map[string]interface{} `json:"items"`
+	Items      []MediaItem              `json:"items"`
 	Page       int                      `json:"page"`
 	Limit      int                      `json:"limit"`
 	TotalItems int                      `json:"total_items"`
 	TotalPages int                      `json:"total_pages"`
 }
 
-// MediaItem represents a media item (simplified structure)
-type MediaItem struct {
-	ID           int                    `json:"id,omitempty"`
-	Title        string                 `json:"title,omitempty"`
-	Type         string                 `json:"type,omitempty"`
-	State        States                 `json:"state,omitempty"`
-	TMDBId       string                 `json:"tmdb_id,omitempty"`
-	TVDBId       string                 `json:"tvdb_id,omitempty"`
-	IMDBId       string                 `json:"imdb_id,omitempty"`
-	Year         *int                   `json:"year,omitempty"`
-	CreatedAt    *time.Time             `json:"created_at,omitempty"`
-	UpdatedAt    *time.Time             `json:"updated_at,omitempty"`
-	RequestedAt  *time.Time             `json:"requested_at,omitempty"`
-	LastState    States                 `json:"last_state,omitempty"`
-	Symlinked    bool                   `json:"symlinked,omitempty"`
-	Blacklisted  bool                   `json:"blacklisted,omitempty"`
-	Genres       []string               `json:"genres,omitempty"`
-	Network      string                 `json:"network,omitempty"`
-	Country      string                 `json:"country,omitempty"`
-	Language     string                 `json:"language,omitempty"`
-	Overview     string                 `json:"overview,omitempty"`
-	Runtime      *int                   `json:"runtime,omitempty"`
-	Seasons      []Season               `json:"seasons,omitempty"`
-	Episodes     []Episode              `json:"episodes,omitempty"`
-	Streams      []Stream               `json:"streams,omitempty"`
-	Metadata     map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// Season represents a TV show season
-type Season struct {
-	ID          int       `json:"id,omitempty"`
-	Number      int       `json:"number"`
-	Title       string    `json:"title,omitempty"`
-	State       States    `json:"state,omitempty"`
-	Episodes    []Episode `json:"episodes,omitempty"`
-	EpisodeCount int      `json:"episode_count,omitempty"`
-	AirDate     *string   `json:"air_date,omitempty"`
-}
-
-// Episode represents a TV show episode
-type Episode struct {
-	ID          int     `json:"id,omitempty"`
-	Number      int     `json:"number"`
-	Title       string  `json:"title,omitempty"`
-	State       States  `json:"state,omitempty"`
-	AirDate     *string `json:"air_date,omitempty"`
-	Runtime     *int    `json:"runtime,omitempty"`
-	Overview    string  `json:"overview,omitempty"`
-	SeasonNumber int    `json:"season_number,omitempty"`
-	Streams     []Stream `json:"streams,omitempty"`
-}
-
 // Stream represents a torrent stream
 type Stream struct {
+	ID           int        `json:"id,omitempty"`
 	InfoHash     string     `json:"infohash"`
 	RawTitle     string     `json:"raw_title"`
 	ParsedTitle  string     `json:"parsed_title"`
@@ -128,6 +75,14 @@ type ParsedData struct {
 	Scene           bool     `json:"scene"`
 }
 
+// ItemStreamsResponse represents the streams of an item. Blacklisted
+// streams are listed separately from the ones still in rotation.
+type ItemStreamsResponse struct {
+	Message            string   `json:"message"`
+	Streams            []Stream `json:"streams"`
+	BlacklistedStreams []Stream `json:"blacklisted_streams"`
+}
+
 // Action response types
 
 // ResetResponse represents reset action response
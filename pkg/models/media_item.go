@@ -0,0 +1,298 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaItem represents a media item: a movie, show, season or episode.
+//
+// Riven's item dictionaries vary between versions and endpoints, so items
+// are decoded tolerantly: IDs and numbers may be strings or numbers,
+// timestamps come in several formats, and a field that does not decode is
+// kept in Metadata with every field not listed here. Raw holds the item as
+// the server sent it.
+type MediaItem struct {
+	ID          string     `json:"id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	State       States     `json:"state,omitempty"`
+	LastState   States     `json:"last_state,omitempty"`
+	TMDBId      string     `json:"tmdb_id,omitempty"`
+	TVDBId      string     `json:"tvdb_id,omitempty"`
+	IMDBId      string     `json:"imdb_id,omitempty"`
+	Year        *int       `json:"year,omitempty"`
+	Number      int        `json:"number,omitempty"` // season or episode number
+	ParentID    string     `json:"parent_id,omitempty"`
+	AiredAt     *time.Time `json:"aired_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	RequestedAt *time.Time `json:"requested_at,omitempty"`
+	RequestedBy string     `json:"requested_by,omitempty"`
+	IsAnime     bool       `json:"is_anime,omitempty"`
+	Symlinked   bool       `json:"symlinked,omitempty"`
+	Blacklisted bool       `json:"blacklisted,omitempty"`
+	Genres      []string   `json:"genres,omitempty"`
+	Network     string     `json:"network,omitempty"`
+	Country     string     `json:"country,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	Overview    string     `json:"overview,omitempty"`
+	Runtime     *int       `json:"runtime,omitempty"`
+	Seasons     []Season   `json:"seasons,omitempty"`
+	Episodes    []Episode  `json:"episodes,omitempty"`
+	Streams     []Stream   `json:"streams,omitempty"`
+
+	// Metadata holds the fields not covered above, by their JSON name
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Raw is the item as decoded from the server, for anything the typed
+	// fields get wrong
+	Raw map[string]interface{} `json:"-"`
+}
+
+// Season is a season of a show. Its episodes are in Episodes.
+type Season = MediaItem
+
+// Episode is an episode of a season
+type Episode = MediaItem
+
+// timeLayouts are the timestamp formats Riven has used for items
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999",
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseTime parses an item timestamp in any of the formats Riven uses.
+// Timestamps without a zone are in UTC.
+func ParseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// UnmarshalJSON decodes an item tolerantly
+func (m *MediaItem) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode media item: %w", err)
+	}
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return fmt.Errorf("failed to decode media item: %w", err)
+	}
+
+	*m = MediaItem{Raw: raw}
+	for name, value := range fields {
+		if !m.decodeField(name, value) {
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]interface{})
+			}
+			m.Metadata[name] = raw[name]
+		}
+	}
+	return nil
+}
+
+// decodeField decodes a known field, reporting false for unknown fields
+// and values that do not decode
+func (m *MediaItem) decodeField(name string, value json.RawMessage) bool {
+	switch name {
+	case "id":
+		return decodeString(value, &m.ID)
+	case "title":
+		return decodeString(value, &m.Title)
+	case "type":
+		return decodeString(value, &m.Type)
+	case "state":
+		return decodeString(value, (*string)(&m.State))
+	case "last_state":
+		return decodeString(value, (*string)(&m.LastState))
+	case "tmdb_id":
+		return decodeString(value, &m.TMDBId)
+	case "tvdb_id":
+		return decodeString(value, &m.TVDBId)
+	case "imdb_id":
+		return decodeString(value, &m.IMDBId)
+	case "year":
+		return decodeIntPtr(value, &m.Year)
+	case "number":
+		return decodeInt(value, &m.Number)
+	case "parent_id":
+		return decodeString(value, &m.ParentID)
+	case "aired_at", "air_date":
+		// Either name may be present, and the other null
+		var at *time.Time
+		if !decodeTime(value, &at) {
+			return false
+		}
+		if at != nil {
+			m.AiredAt = at
+		}
+		return true
+	case "created_at":
+		return decodeTime(value, &m.CreatedAt)
+	case "updated_at":
+		return decodeTime(value, &m.UpdatedAt)
+	case "requested_at":
+		return decodeTime(value, &m.RequestedAt)
+	case "requested_by":
+		return decodeString(value, &m.RequestedBy)
+	case "is_anime":
+		return decodeBool(value, &m.IsAnime)
+	case "symlinked":
+		return decodeBool(value, &m.Symlinked)
+	case "blacklisted":
+		return decodeBool(value, &m.Blacklisted)
+	case "genres":
+		return json.Unmarshal(value, &m.Genres) == nil
+	case "network":
+		return decodeString(value, &m.Network)
+	case "country":
+		return decodeString(value, &m.Country)
+	case "language":
+		return decodeString(value, &m.Language)
+	case "overview":
+		return decodeString(value, &m.Overview)
+	case "runtime":
+		return decodeIntPtr(value, &m.Runtime)
+	case "seasons":
+		return json.Unmarshal(value, &m.Seasons) == nil
+	case "episodes":
+		return json.Unmarshal(value, &m.Episodes) == nil
+	case "streams":
+		return json.Unmarshal(value, &m.Streams) == nil
+	}
+	return false
+}
+
+// MarshalJSON encodes an item with its metadata fields back at the top
+// level, as the server sent them
+func (m MediaItem) MarshalJSON() ([]byte, error) {
+	type plain MediaItem
+	p := plain(m)
+	p.Metadata = nil
+	data, err := json.Marshal(p)
+	if err != nil || len(m.Metadata) == 0 {
+		return data, err
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	for name, value := range m.Metadata {
+		if _, ok := out[name]; !ok {
+			out[name] = value
+		}
+	}
+	return json.Marshal(out)
+}
+
+// decodeString decodes a string, number or boolean as a string. Null is
+// the empty string.
+func decodeString(value json.RawMessage, s *string) bool {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return false
+	}
+	switch v := v.(type) {
+	case nil:
+		*s = ""
+	case string:
+		*s = v
+	case json.Number:
+		*s = v.String()
+	case bool:
+		*s = strconv.FormatBool(v)
+	default:
+		return false
+	}
+	return true
+}
+
+// decodeIntPtr decodes a whole number, or a string holding one. Null and
+// the empty string are nil.
+func decodeIntPtr(value json.RawMessage, n **int) bool {
+	var s string
+	if !decodeString(value, &s) {
+		return false
+	}
+	if s == "" {
+		*n = nil
+		return true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int(f)) {
+		return false
+	}
+	i := int(f)
+	*n = &i
+	return true
+}
+
+// decodeInt decodes a whole number like decodeIntPtr, with null as zero
+func decodeInt(value json.RawMessage, n *int) bool {
+	var p *int
+	if !decodeIntPtr(value, &p) {
+		return false
+	}
+	*n = 0
+	if p != nil {
+		*n = *p
+	}
+	return true
+}
+
+// decodeBool decodes a boolean, or a string or number holding one
+func decodeBool(value json.RawMessage, b *bool) bool {
+	var s string
+	if !decodeString(value, &s) {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "", "false", "0":
+		*b = false
+	case "true", "1":
+		*b = true
+	default:
+		return false
+	}
+	return true
+}
+
+// decodeTime decodes a timestamp in any of the formats Riven uses, or a
+// Unix time in seconds. Null and the empty string are nil.
+func decodeTime(value json.RawMessage, t **time.Time) bool {
+	var s string
+	if !decodeString(value, &s) {
+		return false
+	}
+	if s == "" {
+		*t = nil
+		return true
+	}
+	if at, ok := ParseTime(s); ok {
+		*t = &at
+		return true
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		at := time.Unix(secs, 0).UTC()
+		*t = &at
+		return true
+	}
+	return false
+}
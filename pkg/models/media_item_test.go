@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMediaItemDecodesTolerantly(t *testing.T) {
+	var item MediaItem
+	err := json.Unmarshal([]byte(`{
+		"id": 42,
+		"title": "Severance",
+		"type": "show",
+		"state": "Ongoing",
+		"tvdb_id": 371980,
+		"year": "2022",
+		"aired_at": "2022-02-18 00:00:00",
+		"created_at": "2024-05-01T10:20:30.123456",
+		"updated_at": 1714558830,
+		"requested_at": null,
+		"is_anime": "false",
+		"runtime": "oops",
+		"rating": 8.7,
+		"seasons": [{"id": "43", "number": 1, "parent_id": 42, "episodes": [{"id": 44, "number": 1, "air_date": "2022-02-18"}]}]
+	}`), &item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.ID != "42" || item.TVDBId != "371980" || item.State != StateOngoing {
+		t.Errorf("unexpected identifiers %q %q %q", item.ID, item.TVDBId, item.State)
+	}
+	if item.Year == nil || *item.Year != 2022 {
+		t.Errorf("expected the year as a number, got %v", item.Year)
+	}
+	if item.AiredAt == nil || !item.AiredAt.Equal(time.Date(2022, 2, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected air date %v", item.AiredAt)
+	}
+	if item.CreatedAt == nil || item.CreatedAt.Nanosecond() != 123456000 {
+		t.Errorf("unexpected creation time %v", item.CreatedAt)
+	}
+	if item.UpdatedAt == nil || item.UpdatedAt.Unix() != 1714558830 {
+		t.Errorf("unexpected update time %v", item.UpdatedAt)
+	}
+	if item.RequestedAt != nil || item.IsAnime {
+		t.Error("expected null and false to decode as zero values")
+	}
+
+	// Unknown fields and values that do not decode are kept
+	if item.Runtime != nil || item.Metadata["runtime"] != "oops" {
+		t.Errorf("expected the bad runtime in the metadata, got %v", item.Metadata)
+	}
+	if rating, ok := item.Metadata["rating"].(json.Number); !ok || rating.String() != "8.7" {
+		t.Errorf("expected the rating in the metadata, got %v", item.Metadata)
+	}
+	if item.Raw["runtime"] != "oops" || item.Raw["title"] != "Severance" {
+		t.Errorf("expected the raw item, got %v", item.Raw)
+	}
+
+	if len(item.Seasons) != 1 || len(item.Seasons[0].Episodes) != 1 {
+		t.Fatalf("unexpected seasons %+v", item.Seasons)
+	}
+	season := item.Seasons[0]
+	if season.ParentID != "42" || season.Episodes[0].ID != "44" || season.Episodes[0].AiredAt == nil {
+		t.Errorf("unexpected season %+v", season)
+	}
+}
+
+func TestMediaItemMarshalsMetadataAtTopLevel(t *testing.T) {
+	var item MediaItem
+	if err := json.Unmarshal([]byte(`{"id": 1, "title": "The Matrix", "rating": 8.7}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var again MediaItem
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != "1" || again.Title != "The Matrix" || again.Metadata["rating"] == nil {
+		t.Errorf("expected the item to round trip, got %s", data)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for _, s := range []string{"2024-01-05T10:00:00Z", "2024-01-05T10:00:00.5", "2024-01-05 10:00:00", "2024-01-05"} {
+		if _, ok := ParseTime(s); !ok {
+			t.Errorf("expected %q to parse", s)
+		}
+	}
+	if _, ok := ParseTime("next week"); ok {
+		t.Error("expected an error for free text")
+	}
+}
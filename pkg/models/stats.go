@@ -60,12 +60,6 @@ type UploadLogsResponse struct {
 	URL     string `json:"url"` // URL to the uploaded log file. 50M Filesize limit. 180 day retention.
 }
 
-// CalendarResponse represents the calendar response
-type CalendarResponse struct {
-	// This would contain calendar data - structure not fully defined in the API spec
-	Data interface{} `json:"data,omitempty"`
-}
-
 // TraktOAuthInitiateResponse represents Trakt OAuth initiation response
 type TraktOAuthInitiateResponse struct {
 	AuthURL string `json:"auth_url"`
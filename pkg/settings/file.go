@@ -0,0 +1,232 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileFormat is the encoding of a settings file
+type FileFormat string
+
+const (
+	FormatYAML FileFormat = "yaml"
+	FormatJSON FileFormat = "json"
+)
+
+// FormatForPath picks the file format from a file name: YAML for .yaml and
+// .yml, JSON otherwise
+func FormatForPath(path string) FileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// Marshal encodes settings in the given format. Keys are written in order
+// so exports diff cleanly in version control.
+func Marshal(values map[string]interface{}, format FileFormat) ([]byte, error) {
+	if format == FormatYAML {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(values); err != nil {
+			return nil, fmt.Errorf("failed to encode settings: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode settings: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Unmarshal decodes a settings file. The result has the types of decoded
+// JSON (float64 numbers, []interface{} lists), whichever the format.
+func Unmarshal(data []byte, format FileFormat) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if format == FormatYAML {
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to decode settings: %w", err)
+		}
+		// Round-trip through JSON to get the same types as the API
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode settings: %w", err)
+		}
+		data = encoded
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode settings: %w", err)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("settings file is empty")
+	}
+	return values, nil
+}
+
+// ReadFile reads a YAML or JSON settings file
+func ReadFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file: %w", err)
+	}
+	return Unmarshal(data, FormatForPath(path))
+}
+
+// WriteFile writes settings to a YAML or JSON file. The file is private to
+// the user since it may hold secrets.
+func WriteFile(path string, values map[string]interface{}) error {
+	data, err := Marshal(values, FormatForPath(path))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write settings file: %w", err)
+	}
+	return nil
+}
+
+// Redact returns a copy of the settings with every secret that is set
+// replaced by Mask
+func Redact(values map[string]interface{}, schema *Schema) map[string]interface{} {
+	return redact("", values, schema)
+}
+
+func redact(prefix string, object map[string]interface{}, schema *Schema) map[string]interface{} {
+	out := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		path := join(prefix, key)
+		if child, ok := value.(map[string]interface{}); ok {
+			out[key] = redact(path, child, schema)
+			continue
+		}
+		if s, ok := value.(string); ok && s != "" && schema.FieldFor(path, value).Secret {
+			out[key] = Mask
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// Flatten returns the leaf values of the settings by dotted path. Lists
+// are leaves.
+func Flatten(values map[string]interface{}) map[string]interface{} {
+	leaves := map[string]interface{}{}
+	var walk func(prefix string, object map[string]interface{})
+	walk = func(prefix string, object map[string]interface{}) {
+		for key, value := range object {
+			path := join(prefix, key)
+			if child, ok := value.(map[string]interface{}); ok && len(child) > 0 {
+				walk(path, child)
+				continue
+			}
+			leaves[path] = value
+		}
+	}
+	walk("", values)
+	return leaves
+}
+
+// ChangeKind classifies a difference between a file and the live settings
+type ChangeKind string
+
+const (
+	// Changed settings have another value in the file
+	Changed ChangeKind = "changed"
+	// Unknown settings are in the file but not on the server
+	Unknown ChangeKind = "unknown"
+	// Missing settings are on the server but not in the file
+	Missing ChangeKind = "missing"
+)
+
+// Change is a setting that differs between a file and the live settings
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  interface{} // live value
+	New  interface{} // file value
+}
+
+// Diff compares a settings file with the live settings, path by path.
+// Redacted secrets in the file are not differences: they stand for the
+// live value.
+func Diff(live, file map[string]interface{}) []Change {
+	liveLeaves := Flatten(live)
+	fileLeaves := Flatten(file)
+
+	var changes []Change
+	for path, value := range fileLeaves {
+		old, ok := liveLeaves[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Kind: Unknown, New: value})
+		case value == Mask:
+		case !Equal(old, value):
+			changes = append(changes, Change{Path: path, Kind: Changed, Old: old, New: value})
+		}
+	}
+	for path, value := range liveLeaves {
+		if _, ok := fileLeaves[path]; !ok {
+			changes = append(changes, Change{Path: path, Kind: Missing, Old: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Merge returns the live settings overlaid with a file, for replacing all
+// settings at once. Redacted secrets keep their live value and settings
+// missing from the file are kept.
+func Merge(live, file map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(live))
+	for key, value := range live {
+		out[key] = value
+	}
+	for key, value := range file {
+		liveChild, liveObject := live[key].(map[string]interface{})
+		fileChild, fileObject := value.(map[string]interface{})
+		switch {
+		case liveObject && fileObject:
+			out[key] = Merge(liveChild, fileChild)
+		case value == Mask:
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// FormatChange renders a change as "path: old → new", masking secrets
+func FormatChange(c Change, schema *Schema) string {
+	field := schema.FieldFor(c.Path, c.Old)
+	switch c.Kind {
+	case Unknown:
+		return fmt.Sprintf("+ %s: %s (not a Riven setting)", c.Path, field.Format(c.New))
+	case Missing:
+		return fmt.Sprintf("  %s: %s (not in file, kept)", c.Path, field.Format(c.Old))
+	}
+	return fmt.Sprintf("~ %s: %s → %s", c.Path, field.Format(c.Old), field.Format(c.New))
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
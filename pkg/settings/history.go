@@ -0,0 +1,293 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// Snapshot records the settings a write changed, before and after, so the
+// write can be rolled back
+type Snapshot struct {
+	ID          string                 `json:"id"`
+	Time        time.Time              `json:"time"`
+	Description string                 `json:"description"`
+	Before      map[string]interface{} `json:"before"` // previous values by path
+	After       map[string]interface{} `json:"after"`  // written values by path
+	// Existed marks the paths that were set before the write, so a previous
+	// null can be told from a setting that did not exist
+	Existed map[string]bool `json:"existed,omitempty"`
+}
+
+// Paths returns the paths of the settings the snapshot covers in order
+func (s Snapshot) Paths() []string {
+	paths := make([]string, 0, len(s.Before))
+	for path := range s.Before {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// HadValue reports whether a setting existed before the write, even as
+// null. Snapshots from before Existed was recorded only know non-null
+// values.
+func (s Snapshot) HadValue(path string) bool {
+	if s.Existed == nil {
+		return s.Before[path] != nil
+	}
+	return s.Existed[path]
+}
+
+// History stores snapshots as JSON files in a directory, keeping the most
+// recent ones. Snapshots hold secrets in clear so they can be restored, so
+// the directory and files are private to the user.
+type History struct {
+	dir   string
+	limit int
+	now   func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewHistory creates a history in dir keeping at most limit snapshots. A
+// limit of zero keeps every snapshot.
+func NewHistory(dir string, limit int) *History {
+	return &History{dir: dir, limit: limit, now: time.Now}
+}
+
+// Dir returns the directory the snapshots are stored in
+func (h *History) Dir() string {
+	return h.dir
+}
+
+// Record stores a snapshot of a write. Settings whose value does not change
+// are left out; nil is returned if nothing changes.
+func (h *History) Record(description string, before, after map[string]interface{}) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Time:        h.now().UTC(),
+		Description: description,
+		Before:      map[string]interface{}{},
+		After:       map[string]interface{}{},
+		Existed:     map[string]bool{},
+	}
+	for path, value := range after {
+		if old, ok := before[path]; !ok || !Equal(old, value) {
+			snapshot.Before[path] = old
+			snapshot.After[path] = value
+			snapshot.Existed[path] = ok
+		}
+	}
+	if len(snapshot.Before) == 0 {
+		return nil, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// IDs sort by time; writes within the same millisecond get a suffix
+	base := strings.Replace(snapshot.Time.Format("20060102T150405.000Z"), ".", "", 1)
+	snapshot.ID = base
+	for n := 1; h.exists(snapshot.ID); n++ {
+		snapshot.ID = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	if err := os.MkdirAll(h.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create settings history: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings snapshot: %w", err)
+	}
+	if err := os.WriteFile(h.path(snapshot.ID), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write settings snapshot: %w", err)
+	}
+
+	h.prune()
+	return snapshot, nil
+}
+
+// List returns the snapshots, newest first. A history that does not exist
+// yet is empty.
+func (h *History) List() ([]Snapshot, error) {
+	ids, err := h.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		snapshot, err := h.Load(ids[i])
+		if err != nil {
+			// A damaged snapshot should not hide the others
+			continue
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	return snapshots, nil
+}
+
+// Load reads a snapshot by ID
+func (h *History) Load(id string) (*Snapshot, error) {
+	data, err := os.ReadFile(h.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode settings snapshot %s: %w", id, err)
+	}
+	return &snapshot, nil
+}
+
+// Remove deletes a snapshot
+func (h *History) Remove(id string) error {
+	if err := os.Remove(h.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove settings snapshot: %w", err)
+	}
+	return nil
+}
+
+// ids returns the snapshot IDs, oldest first
+func (h *History) ids() ([]string, error) {
+	entries, err := os.ReadDir(h.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings history: %w", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, ".json") {
+			ids = append(ids, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// prune removes the oldest snapshots beyond the limit
+func (h *History) prune() {
+	if h.limit <= 0 {
+		return
+	}
+	ids, err := h.ids()
+	if err != nil {
+		return
+	}
+	for len(ids) > h.limit {
+		os.Remove(h.path(ids[0]))
+		ids = ids[1:]
+	}
+}
+
+func (h *History) exists(id string) bool {
+	_, err := os.Stat(h.path(id))
+	return err == nil
+}
+
+func (h *History) path(id string) string {
+	return filepath.Join(h.dir, id+".json")
+}
+
+type descriptionKey struct{}
+
+// WithDescription describes the settings writes made with the returned
+// context in the history
+func WithDescription(ctx context.Context, description string) context.Context {
+	return context.WithValue(ctx, descriptionKey{}, description)
+}
+
+// description returns the description of a write, or a default one
+func description(ctx context.Context, fallback string) string {
+	if d, ok := ctx.Value(descriptionKey{}).(string); ok && d != "" {
+		return d
+	}
+	return fallback
+}
+
+// recordingClient snapshots the settings before every write
+type recordingClient struct {
+	api.SettingsAPI
+	history *History
+}
+
+// Record wraps a settings client so every SetSettings and SetAllSettings
+// call is recorded in the history before it is sent. A write that cannot
+// be recorded is not sent, and a write that fails leaves no snapshot.
+func Record(client api.SettingsAPI, history *History) api.SettingsAPI {
+	return &recordingClient{SettingsAPI: client, history: history}
+}
+
+// SetSettings records the previous values of the settings, then sets them
+func (c *recordingClient) SetSettings(ctx context.Context, requests []api.SetSettingsRequest) (*models.MessageResponse, error) {
+	after := make(map[string]interface{}, len(requests))
+	for _, r := range requests {
+		after[r.Key] = r.Value
+	}
+	fallback := fmt.Sprintf("Set %d setting(s)", len(requests))
+
+	return c.record(ctx, fallback, after, func() (*models.MessageResponse, error) {
+		return c.SettingsAPI.SetSettings(ctx, requests)
+	})
+}
+
+// SetAllSettings records the settings the new tree changes, then replaces
+// the settings
+func (c *recordingClient) SetAllSettings(ctx context.Context, values map[string]interface{}) (*models.MessageResponse, error) {
+	return c.record(ctx, "Replace all settings", Flatten(values), func() (*models.MessageResponse, error) {
+		return c.SettingsAPI.SetAllSettings(ctx, values)
+	})
+}
+
+// record snapshots the live values of the paths in after, runs the write
+// and drops the snapshot if the write fails
+func (c *recordingClient) record(ctx context.Context, fallback string, after map[string]interface{}, write func() (*models.MessageResponse, error)) (*models.MessageResponse, error) {
+	raw, err := c.GetAllSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot settings: %w", err)
+	}
+	live, _ := raw.(map[string]interface{})
+
+	before := map[string]interface{}{}
+	for path := range after {
+		if value, ok := Lookup(live, path); ok {
+			before[path] = value
+		}
+	}
+
+	snapshot, err := c.history.Record(description(ctx, fallback), before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := write()
+	if err != nil && snapshot != nil {
+		c.history.Remove(snapshot.ID)
+	}
+	return resp, err
+}
+
+// Rollback returns the requests that restore the settings a snapshot
+// changed, including previous nulls. Settings that did not exist before
+// the write are skipped.
+func Rollback(snapshot Snapshot) []api.SetSettingsRequest {
+	var requests []api.SetSettingsRequest
+	for _, path := range snapshot.Paths() {
+		if snapshot.HadValue(path) {
+			requests = append(requests, api.SetSettingsRequest{Key: path, Value: snapshot.Before[path]})
+		}
+	}
+	return requests
+}
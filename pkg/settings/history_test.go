@@ -0,0 +1,121 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// fakeSettings is an api.SettingsAPI holding settings in memory
+type fakeSettings struct {
+	api.SettingsAPI
+	values map[string]interface{}
+	err    error
+}
+
+func (f *fakeSettings) GetAllSettings(ctx context.Context) (interface{}, error) {
+	return f.values, nil
+}
+
+func (f *fakeSettings) SetSettings(ctx context.Context, requests []api.SetSettingsRequest) (*models.MessageResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, r := range requests {
+		f.values[r.Key] = r.Value
+	}
+	return &models.MessageResponse{Message: "ok"}, nil
+}
+
+func TestHistoryRecordAndPrune(t *testing.T) {
+	history := NewHistory(t.TempDir(), 2)
+	now := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	history.now = func() time.Time { return now }
+
+	for i, timeout := range []float64{45, 60, 90} {
+		before := map[string]interface{}{"timeout": float64(30 + 15*i), "url": "x"}
+		after := map[string]interface{}{"timeout": timeout, "url": "x"}
+		snapshot, err := history.Record("edit", before, after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshot.Before) != 1 {
+			t.Errorf("expected only the changed setting, got %v", snapshot.Before)
+		}
+	}
+	if snapshot, _ := history.Record("noop", map[string]interface{}{"a": true}, map[string]interface{}{"a": true}); snapshot != nil {
+		t.Errorf("expected no snapshot for a write that changes nothing, got %+v", snapshot)
+	}
+
+	// Same millisecond: IDs still sort in order, and the oldest is pruned
+	snapshots, err := history.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].After["timeout"] != float64(90) || snapshots[1].After["timeout"] != float64(60) {
+		t.Fatalf("expected the two newest snapshots, newest first, got %+v", snapshots)
+	}
+}
+
+func TestRecordingClient(t *testing.T) {
+	history := NewHistory(t.TempDir(), 0)
+	fake := &fakeSettings{values: map[string]interface{}{"profile": "default"}}
+	client := Record(fake, history)
+
+	ctx := WithDescription(context.Background(), "switch profile")
+	if _, err := client.SetSettings(ctx, []api.SetSettingsRequest{{Key: "profile", Value: "best"}}); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, _ := history.List()
+	if len(snapshots) != 1 || snapshots[0].Description != "switch profile" || snapshots[0].Before["profile"] != "default" {
+		t.Fatalf("expected the previous value to be recorded, got %+v", snapshots)
+	}
+
+	// Rolling back restores the previous value, and is itself recorded
+	if _, err := client.SetSettings(context.Background(), Rollback(snapshots[0])); err != nil {
+		t.Fatal(err)
+	}
+	if fake.values["profile"] != "default" {
+		t.Errorf("expected the rollback to restore default, got %v", fake.values["profile"])
+	}
+
+	// A failed write leaves no snapshot
+	fake.err = errors.New("boom")
+	if _, err := client.SetSettings(context.Background(), []api.SetSettingsRequest{{Key: "profile", Value: "custom"}}); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if snapshots, _ := history.List(); len(snapshots) != 2 {
+		t.Errorf("expected two snapshots, got %d", len(snapshots))
+	}
+}
+
+func TestRollbackRestoresNull(t *testing.T) {
+	history := NewHistory(t.TempDir(), 0)
+	fake := &fakeSettings{values: map[string]interface{}{"proxy_url": nil}}
+	client := Record(fake, history)
+
+	requests := []api.SetSettingsRequest{{Key: "proxy_url", Value: "http://proxy:8080"}, {Key: "new_key", Value: true}}
+	if _, err := client.SetSettings(context.Background(), requests); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, _ := history.List()
+	if len(snapshots) != 1 || !snapshots[0].HadValue("proxy_url") || snapshots[0].HadValue("new_key") {
+		t.Fatalf("expected the previous null to be recorded, got %+v", snapshots)
+	}
+
+	// The null comes back; the setting that did not exist is left alone
+	rollback := Rollback(snapshots[0])
+	if len(rollback) != 1 || rollback[0].Key != "proxy_url" || rollback[0].Value != nil {
+		t.Fatalf("expected proxy_url to be set back to null, got %+v", rollback)
+	}
+	if _, err := client.SetSettings(context.Background(), rollback); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := fake.values["proxy_url"]; !ok || value != nil {
+		t.Errorf("expected proxy_url to be null again, got %v", value)
+	}
+}
@@ -0,0 +1,247 @@
+// Package settings types and validates Riven settings against the JSON
+// Schema the server publishes for them
+package settings
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kind is the JSON type of a setting
+type Kind string
+
+const (
+	KindString  Kind = "string"
+	KindInteger Kind = "integer"
+	KindNumber  Kind = "number"
+	KindBoolean Kind = "boolean"
+	KindArray   Kind = "array"
+	KindObject  Kind = "object"
+)
+
+// Field describes a single setting
+type Field struct {
+	Path        string
+	Title       string
+	Description string
+	Kind        Kind
+	Enum        []string // allowed values, if restricted
+	ItemKind    Kind     // element type of arrays
+	ItemEnum    []string // allowed elements of arrays, if restricted
+	Minimum     *float64
+	Maximum     *float64
+	// ExclusiveMinimum and ExclusiveMaximum are bounds the value must not
+	// reach, such as pydantic's gt=0
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	Secret           bool
+	ReadOnly         bool
+}
+
+// Schema holds the fields declared by a settings JSON Schema
+type Schema struct {
+	fields map[string]Field
+}
+
+// secretNames are key suffixes treated as secrets even when the schema does
+// not mark them
+var secretNames = []string{"api_key", "apikey", "token", "password", "secret"}
+
+// ParseSchema reads a pydantic-style JSON Schema. Nested models are followed
+// through $ref into $defs, and Optional fields (anyOf with null) take the
+// type of their non-null branch.
+func ParseSchema(schema map[string]interface{}) *Schema {
+	s := &Schema{fields: map[string]Field{}}
+	if schema == nil {
+		return s
+	}
+	defs, _ := schema["$defs"].(map[string]interface{})
+	if defs == nil {
+		defs, _ = schema["definitions"].(map[string]interface{})
+	}
+	s.walk("", schema, defs, 0)
+	return s
+}
+
+// walk records the fields of an object schema
+func (s *Schema) walk(path string, node map[string]interface{}, defs map[string]interface{}, depth int) {
+	if depth > 32 {
+		// Recursive models have no settings of their own below this
+		return
+	}
+	properties, _ := node["properties"].(map[string]interface{})
+	for key, raw := range properties {
+		prop, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+
+		resolved := resolve(prop, defs)
+		field := newField(childPath, key, resolved)
+		s.fields[childPath] = field
+		if field.Kind == KindObject {
+			s.walk(childPath, resolved, defs, depth+1)
+		}
+	}
+}
+
+// resolve follows $ref, allOf and anyOf to the schema that describes a
+// property, keeping the property's own keywords
+func resolve(prop map[string]interface{}, defs map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	var target map[string]interface{}
+
+	if ref, ok := prop["$ref"].(string); ok {
+		target = lookupRef(ref, defs)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		branches, _ := prop[keyword].([]interface{})
+		for _, b := range branches {
+			branch, ok := b.(map[string]interface{})
+			if !ok || branch["type"] == "null" {
+				continue
+			}
+			target = resolve(branch, defs)
+			break
+		}
+	}
+
+	for k, v := range target {
+		merged[k] = v
+	}
+	for k, v := range prop {
+		if k != "$ref" && k != "allOf" && k != "anyOf" && k != "oneOf" {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// lookupRef returns the definition a local $ref points to
+func lookupRef(ref string, defs map[string]interface{}) map[string]interface{} {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	def, _ := defs[name].(map[string]interface{})
+	return def
+}
+
+// newField builds a field from its resolved schema
+func newField(path, key string, node map[string]interface{}) Field {
+	field := Field{
+		Path:             path,
+		Title:            stringValue(node["title"]),
+		Description:      stringValue(node["description"]),
+		Kind:             Kind(stringValue(node["type"])),
+		Enum:             stringList(node["enum"]),
+		Minimum:          numberValue(node["minimum"]),
+		Maximum:          numberValue(node["maximum"]),
+		ExclusiveMinimum: numberValue(node["exclusiveMinimum"]),
+		ExclusiveMaximum: numberValue(node["exclusiveMaximum"]),
+		Secret:           node["format"] == "password" || node["writeOnly"] == true || isSecretName(key),
+		ReadOnly:         node["readOnly"] == true,
+	}
+	if field.Kind == "" {
+		if _, ok := node["properties"]; ok {
+			field.Kind = KindObject
+		} else if len(field.Enum) > 0 {
+			field.Kind = KindString
+		}
+	}
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		field.ItemKind = Kind(stringValue(items["type"]))
+		field.ItemEnum = stringList(items["enum"])
+	}
+	return field
+}
+
+// Field returns the field at a dotted path
+func (s *Schema) Field(path string) (Field, bool) {
+	field, ok := s.fields[path]
+	return field, ok
+}
+
+// FieldFor returns the field at a path, falling back to one inferred from
+// the current value for settings the schema does not declare
+func (s *Schema) FieldFor(path string, value interface{}) Field {
+	if field, ok := s.Field(path); ok && field.Kind != "" {
+		return field
+	}
+	key := path[strings.LastIndex(path, ".")+1:]
+	field := Field{Path: path, Kind: KindOf(value), Secret: isSecretName(key)}
+	if field.Kind == KindArray {
+		field.ItemKind = KindString
+	}
+	return field
+}
+
+// Len returns the number of fields in the schema
+func (s *Schema) Len() int {
+	return len(s.fields)
+}
+
+// KindOf returns the JSON type of a decoded value
+func KindOf(value interface{}) Kind {
+	switch v := value.(type) {
+	case bool:
+		return KindBoolean
+	case float64:
+		if v == float64(int64(v)) {
+			return KindInteger
+		}
+		return KindNumber
+	case int, int64:
+		return KindInteger
+	case []interface{}:
+		return KindArray
+	case map[string]interface{}:
+		return KindObject
+	}
+	return KindString
+}
+
+// isSecretName reports whether a key names a credential
+func isSecretName(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range secretNames {
+		if strings.HasSuffix(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func numberValue(v interface{}) *float64 {
+	if f, ok := v.(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+func stringList(v interface{}) []string {
+	list, _ := v.([]interface{})
+	var out []string
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Keys returns the keys of a settings object in order
+func Keys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+package settings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"api_key": {"type": "string", "format": "password"},
+		"version": {"type": "string", "readOnly": true},
+		"interval": {"type": "integer", "minimum": 30, "maximum": 86400},
+		"ratio": {"type": "number", "exclusiveMinimum": 0, "maximum": 1},
+		"torrentio": {"$ref": "#/$defs/TorrentioModel"},
+		"proxy": {"anyOf": [{"type": "null"}, {"type": "string"}], "default": null},
+		"kinds": {"type": "array", "items": {"type": "string", "enum": ["movie", "show"]}}
+	},
+	"$defs": {
+		"TorrentioModel": {
+			"type": "object",
+			"properties": {
+				"enabled": {"type": "boolean"},
+				"profile": {"type": "string", "enum": ["default", "best"]}
+			}
+		}
+	}
+}`
+
+func parseTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(testSchema), &raw); err != nil {
+		t.Fatal(err)
+	}
+	return ParseSchema(raw)
+}
+
+func TestParseSchema(t *testing.T) {
+	s := parseTestSchema(t)
+
+	tests := []struct {
+		path string
+		kind Kind
+	}{
+		{"api_key", KindString},
+		{"torrentio", KindObject},
+		{"torrentio.enabled", KindBoolean},
+		{"torrentio.profile", KindString},
+		{"proxy", KindString},
+		{"kinds", KindArray},
+	}
+	for _, tt := range tests {
+		field, ok := s.Field(tt.path)
+		if !ok || field.Kind != tt.kind {
+			t.Errorf("%s: expected kind %q, got %q (found %v)", tt.path, tt.kind, field.Kind, ok)
+		}
+	}
+
+	if f, _ := s.Field("api_key"); !f.Secret {
+		t.Error("expected api_key to be secret")
+	}
+	if f, _ := s.Field("torrentio.profile"); len(f.Choices()) != 2 {
+		t.Errorf("expected the profile choices, got %v", f.Choices())
+	}
+	if f, _ := s.Field("kinds"); f.ItemKind != KindString || len(f.Choices()) != 2 {
+		t.Errorf("expected the list choices, got %+v", f)
+	}
+
+	// Undeclared settings are typed from their value
+	if f := s.FieldFor("other.token", "abc"); f.Kind != KindString || !f.Secret {
+		t.Errorf("expected an inferred secret string, got %+v", f)
+	}
+}
+
+func TestFieldParse(t *testing.T) {
+	s := parseTestSchema(t)
+	field := func(path string) Field {
+		f, _ := s.Field(path)
+		return f
+	}
+
+	tests := []struct {
+		path    string
+		input   string
+		want    interface{}
+		wantErr string
+	}{
+		{"interval", "300", float64(300), ""},
+		{"interval", "5", nil, "must be at least 30"},
+		{"interval", "90000", nil, "must be at most 86400"},
+		{"interval", "1.5", nil, "expected a whole number"},
+		{"ratio", "0.5", 0.5, ""},
+		{"ratio", "1", float64(1), ""},
+		{"ratio", "0", nil, "must be greater than 0"},
+		{"torrentio.enabled", "yes", true, ""},
+		{"torrentio.enabled", "maybe", nil, "expected true or false"},
+		{"torrentio.profile", "worst", nil, "must be one of default, best"},
+		{"kinds", "movie, show", []interface{}{"movie", "show"}, ""},
+		{"kinds", "movie, episode", nil, `"episode" is not one of movie, show`},
+		{"version", "1.0", nil, "version is read-only"},
+	}
+	for _, tt := range tests {
+		got, err := field(tt.path).Parse(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s=%q: expected error %q, got %v", tt.path, tt.input, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || !Equal(got, tt.want) {
+			t.Errorf("%s=%q: expected %v, got %v (%v)", tt.path, tt.input, tt.want, got, err)
+		}
+	}
+
+	if got := field("ratio").Range(); got != "> 0, ≤ 1" {
+		t.Errorf("expected the exclusive lower bound in the range, got %q", got)
+	}
+	if got := field("interval").Range(); got != "30–86400" {
+		t.Errorf("expected the inclusive range, got %q", got)
+	}
+
+	if got := field("api_key").Format("secret"); got != Mask {
+		t.Errorf("expected the secret to be masked, got %q", got)
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	live := map[string]interface{}{
+		"api_key":   "secret-key",
+		"debug":     true,
+		"torrentio": map[string]interface{}{"timeout": float64(30), "url": "http://torrentio"},
+		"kinds":     []interface{}{"movie"},
+	}
+	redacted := Redact(live, parseTestSchema(t))
+	if redacted["api_key"] != Mask || live["api_key"] != "secret-key" {
+		t.Fatalf("expected a redacted copy, got %v", redacted)
+	}
+
+	for _, format := range []FileFormat{FormatYAML, FormatJSON} {
+		data, err := Marshal(redacted, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := Unmarshal(data, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !Equal(decoded, redacted) {
+			t.Errorf("%s: expected %v, got %v", format, redacted, decoded)
+		}
+		// A redacted export is no change
+		if changes := Diff(live, decoded); len(changes) != 0 {
+			t.Errorf("%s: expected no changes, got %+v", format, changes)
+		}
+	}
+}
+
+func TestDiffAndMerge(t *testing.T) {
+	live := map[string]interface{}{
+		"api_key":   "secret-key",
+		"torrentio": map[string]interface{}{"timeout": float64(30), "url": "http://torrentio"},
+	}
+	file := map[string]interface{}{
+		"api_key":   Mask,
+		"torrentio": map[string]interface{}{"timeout": float64(60)},
+		"unknown":   "x",
+	}
+
+	changes := Diff(live, file)
+	want := []Change{
+		{Path: "torrentio.timeout", Kind: Changed, Old: float64(30), New: float64(60)},
+		{Path: "torrentio.url", Kind: Missing, Old: "http://torrentio"},
+		{Path: "unknown", Kind: Unknown, New: "x"},
+	}
+	if !Equal(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+
+	merged := Merge(live, file)
+	if v, _ := Lookup(merged, "torrentio.timeout"); v != float64(60) {
+		t.Errorf("expected the file value, got %v", v)
+	}
+	if v, _ := Lookup(merged, "torrentio.url"); v != "http://torrentio" {
+		t.Errorf("expected the live value to be kept, got %v", v)
+	}
+	if merged["api_key"] != "secret-key" {
+		t.Errorf("expected the redacted secret to keep its live value, got %v", merged["api_key"])
+	}
+}
@@ -0,0 +1,246 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mask is shown in place of secret values
+const Mask = "••••••••"
+
+// Parse converts text typed by the user into a value of the field's type,
+// and validates it
+func (f Field) Parse(input string) (interface{}, error) {
+	input = strings.TrimSpace(input)
+
+	var value interface{}
+	switch f.Kind {
+	case KindBoolean:
+		b, err := parseBool(input)
+		if err != nil {
+			return nil, err
+		}
+		value = b
+	case KindInteger, KindNumber:
+		n, err := parseNumber(input, f.Kind)
+		if err != nil {
+			return nil, err
+		}
+		value = n
+	case KindArray:
+		items := []interface{}{}
+		for _, part := range strings.Split(input, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if f.ItemKind == KindInteger || f.ItemKind == KindNumber {
+				n, err := parseNumber(part, f.ItemKind)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, n)
+			} else {
+				items = append(items, part)
+			}
+		}
+		value = items
+	case KindObject:
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(input), &object); err != nil {
+			return nil, fmt.Errorf("expected a JSON object")
+		}
+		value = object
+	default:
+		value = input
+	}
+
+	if err := f.Validate(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Validate checks a value against the field's type and constraints
+func (f Field) Validate(value interface{}) error {
+	if f.ReadOnly {
+		return fmt.Errorf("%s is read-only", f.Path)
+	}
+
+	switch f.Kind {
+	case KindBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected true or false")
+		}
+	case KindInteger, KindNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected a number")
+		}
+		if f.Kind == KindInteger && n != float64(int64(n)) {
+			return fmt.Errorf("expected a whole number")
+		}
+		if f.Minimum != nil && n < *f.Minimum {
+			return fmt.Errorf("must be at least %s", FormatNumber(*f.Minimum))
+		}
+		if f.Maximum != nil && n > *f.Maximum {
+			return fmt.Errorf("must be at most %s", FormatNumber(*f.Maximum))
+		}
+		if f.ExclusiveMinimum != nil && n <= *f.ExclusiveMinimum {
+			return fmt.Errorf("must be greater than %s", FormatNumber(*f.ExclusiveMinimum))
+		}
+		if f.ExclusiveMaximum != nil && n >= *f.ExclusiveMaximum {
+			return fmt.Errorf("must be less than %s", FormatNumber(*f.ExclusiveMaximum))
+		}
+	case KindString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected text")
+		}
+		if len(f.Enum) > 0 && !containsString(f.Enum, s) {
+			return fmt.Errorf("must be one of %s", strings.Join(f.Enum, ", "))
+		}
+	case KindArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a list")
+		}
+		for _, item := range items {
+			if s, ok := item.(string); ok && len(f.ItemEnum) > 0 && !containsString(f.ItemEnum, s) {
+				return fmt.Errorf("%q is not one of %s", s, strings.Join(f.ItemEnum, ", "))
+			}
+		}
+	case KindObject:
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("expected an object")
+		}
+	}
+	return nil
+}
+
+// Choices returns the values offered by a picker for the field: the enum of
+// a string, the allowed elements of a list, or nil for free input
+func (f Field) Choices() []string {
+	if f.Kind == KindArray {
+		return f.ItemEnum
+	}
+	return f.Enum
+}
+
+// Range describes the allowed range of a number, or "" if unbounded
+func (f Field) Range() string {
+	if f.Minimum != nil && f.Maximum != nil && f.ExclusiveMinimum == nil && f.ExclusiveMaximum == nil {
+		return fmt.Sprintf("%s–%s", FormatNumber(*f.Minimum), FormatNumber(*f.Maximum))
+	}
+	var bounds []string
+	for _, bound := range []struct {
+		op    string
+		value *float64
+	}{
+		{"≥", f.Minimum}, {">", f.ExclusiveMinimum}, {"≤", f.Maximum}, {"<", f.ExclusiveMaximum},
+	} {
+		if bound.value != nil {
+			bounds = append(bounds, bound.op+" "+FormatNumber(*bound.value))
+		}
+	}
+	return strings.Join(bounds, ", ")
+}
+
+// Format renders a value for display, masking secrets
+func (f Field) Format(value interface{}) string {
+	if f.Secret {
+		if s, ok := value.(string); ok && s == "" {
+			return "(not set)"
+		}
+		return Mask
+	}
+	return FormatValue(value)
+}
+
+// FormatValue renders a value for display or editing. Lists are comma
+// separated, the format Parse reads back.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return FormatNumber(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = FormatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// FormatNumber renders a number without a trailing .0 for whole values
+func FormatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// Lookup returns the value at a dotted path
+func Lookup(settings map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = settings
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Equal reports whether two decoded JSON values are the same
+func Equal(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func parseBool(input string) (bool, error) {
+	switch strings.ToLower(input) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected true or false")
+}
+
+func parseNumber(input string, kind Kind) (float64, error) {
+	n, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		if kind == KindInteger {
+			return 0, fmt.Errorf("expected a whole number")
+		}
+		return 0, fmt.Errorf("expected a number")
+	}
+	if kind == KindInteger && n != float64(int64(n)) {
+		return 0, fmt.Errorf("expected a whole number")
+	}
+	return n, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,447 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// addIDType is the kind of ID entered on the Add screen
+type addIDType int
+
+const (
+	addTMDB addIDType = iota
+	addTVDB
+	addIMDb
+)
+
+var addIDTypeNames = []string{"TMDB", "TVDB", "IMDb"}
+
+// addOutcome is what happened to one of the IDs entered
+type addOutcome struct {
+	id     string
+	status addStatus
+	detail string
+}
+
+type addStatus int
+
+const (
+	addAccepted addStatus = iota
+	addSkipped            // IMDb lookups only: already in the library
+	addMissing            // IMDb lookups only: not in the library
+	addNotAdded           // not accepted; the server's message may say why
+	addFailed
+)
+
+// AddModel requests new media by TMDB or TVDB IDs, and looks up IMDb IDs
+// in the library
+type AddModel struct {
+	client api.ItemsAPI
+	ctx    context.Context
+	width  int
+	height int
+
+	// Form
+	idType   addIDType
+	movie    bool
+	idsInput textinput.Model
+	focus    int // 0: ID type, 1: media type, 2: IDs
+	editing  bool
+
+	// Result of the last request
+	submitting     bool
+	addFetch       fetchSlot
+	message        string
+	serverMessages []string // the server's message for each chunk
+	outcomes       []addOutcome
+	error          string
+}
+
+// addResultMsg reports the outcome of adding or looking up IDs
+type addResultMsg struct {
+	gen            int
+	message        string
+	serverMessages []string
+	outcomes       []addOutcome
+	err            error
+}
+
+// NewAddModel creates a new Add screen
+func NewAddModel(client api.ItemsAPI, ctx context.Context) *AddModel {
+	idsInput := textinput.New()
+	idsInput.Placeholder = "603, 27205 ... (paste as many as you like)"
+	idsInput.CharLimit = 0
+	idsInput.Width = 60
+
+	return &AddModel{
+		client:   client,
+		ctx:      ctx,
+		movie:    true,
+		idsInput: idsInput,
+		focus:    2,
+		editing:  true,
+	}
+}
+
+// SetSize sets the size of the Add screen
+func (m *AddModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height - 3 // Account for navigation bar
+
+	m.idsInput.Width = min(width-20, 100)
+}
+
+// Init implements tea.Model
+func (m *AddModel) Init() tea.Cmd {
+	if m.editing && m.focus == 2 {
+		return m.idsInput.Focus()
+	}
+	return nil
+}
+
+// capturesInput implements inputCapturer
+func (m *AddModel) capturesInput() bool {
+	return m.editing
+}
+
+// Update implements tea.Model
+func (m *AddModel) Update(msg tea.Msg) (*AddModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case addResultMsg:
+		if !m.addFetch.finish(msg.gen) {
+			return m, nil
+		}
+		m.submitting = false
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to add media: %s", describeError(msg.err))
+		}
+		m.message = msg.message
+		m.serverMessages = msg.serverMessages
+		m.outcomes = msg.outcomes
+		return m, nil
+
+	case tea.KeyMsg:
+		if !m.editing {
+			switch msg.String() {
+			case "enter", "i":
+				m.editing = true
+				return m, m.setFocus(m.focus)
+			}
+			return m, nil
+		}
+		return m, m.handleFormKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleFormKey handles keys while the form has focus
+func (m *AddModel) handleFormKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.idsInput.Blur()
+		return nil
+	case "tab", "down":
+		return m.setFocus((m.focus + 1) % 3)
+	case "shift+tab", "up":
+		return m.setFocus((m.focus + 2) % 3)
+	case "enter":
+		return m.submit()
+	}
+
+	switch m.focus {
+	case 0:
+		switch msg.String() {
+		case "left", "h":
+			m.idType = (m.idType + 2) % 3
+		case "right", "l", " ":
+			m.idType = (m.idType + 1) % 3
+		}
+		// TVDB only knows shows
+		if m.idType == addTVDB {
+			m.movie = false
+		}
+		return nil
+	case 1:
+		switch msg.String() {
+		case "left", "right", "h", "l", " ":
+			m.movie = !m.movie
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.idsInput, cmd = m.idsInput.Update(msg)
+	return cmd
+}
+
+// setFocus moves the focus to a form field
+func (m *AddModel) setFocus(field int) tea.Cmd {
+	m.focus = field
+	if field == 2 {
+		return m.idsInput.Focus()
+	}
+	m.idsInput.Blur()
+	return nil
+}
+
+// parseIDs splits the entered IDs on commas and whitespace, dropping
+// duplicates, and returns the ones that are not valid for the ID type
+func parseIDs(input string, idType addIDType) (ids, invalid []string) {
+	seen := map[string]bool{}
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	for _, id := range fields {
+		if idType == addIMDb {
+			id = strings.ToLower(id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		valid := numericIDPattern.MatchString(id)
+		if idType == addIMDb {
+			valid = imdbIDPattern.MatchString(id)
+		}
+		if valid {
+			ids = append(ids, id)
+		} else {
+			invalid = append(invalid, id)
+		}
+	}
+	return ids, invalid
+}
+
+// submit validates the form and sends the IDs
+func (m *AddModel) submit() tea.Cmd {
+	if m.submitting {
+		return nil
+	}
+
+	ids, invalid := parseIDs(m.idsInput.Value(), m.idType)
+	switch {
+	case len(invalid) > 0:
+		m.error = fmt.Sprintf("Invalid %s IDs: %s", addIDTypeNames[m.idType], strings.Join(invalid, ", "))
+		return nil
+	case len(ids) == 0:
+		m.error = fmt.Sprintf("Enter one or more %s IDs", addIDTypeNames[m.idType])
+		return nil
+	}
+
+	m.error = ""
+	m.message = ""
+	m.serverMessages = nil
+	m.outcomes = nil
+	m.submitting = true
+
+	ctx, gen := m.addFetch.begin(m.ctx)
+	if m.idType == addIMDb {
+		return lookupIMDbIDs(ctx, m.client, gen, ids)
+	}
+	return addItems(ctx, m.client, gen, m.idType, mediaTypeOf(m.movie), ids)
+}
+
+// addItems requests the IDs in chunks of bulkChunkSize. Known IDs are
+// skipped by the server, so the requests are safe to retry.
+func addItems(ctx context.Context, client api.ItemsAPI, gen int, idType addIDType, mediaType *models.MediaType, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := api.WithRetry(ctx)
+		var outcomes []addOutcome
+		var serverMessages []string
+		added := 0
+
+		for start := 0; start < len(ids); start += bulkChunkSize {
+			chunk := ids[start:min(start+bulkChunkSize, len(ids))]
+			joined := strings.Join(chunk, ",")
+
+			var resp *models.MessageResponse
+			var err error
+			if idType == addTVDB {
+				resp, err = client.AddItems(ctx, nil, &joined, mediaType)
+			} else {
+				resp, err = client.AddItems(ctx, &joined, nil, mediaType)
+			}
+			if err != nil {
+				for _, id := range chunk {
+					outcomes = append(outcomes, addOutcome{id: id, status: addFailed, detail: firstLine(describeError(err))})
+				}
+				continue
+			}
+
+			serverMessage := orDefault(resp.Message, "(no message)")
+			if len(ids) > bulkChunkSize {
+				serverMessage = fmt.Sprintf("IDs %d-%d: %s", start+1, start+len(chunk), serverMessage)
+			}
+			serverMessages = append(serverMessages, serverMessage)
+
+			accepted := resp.TMDBIds
+			if idType == addTVDB {
+				accepted = resp.TVDBIds
+			}
+			done := map[string]bool{}
+			for _, id := range accepted {
+				done[id] = true
+			}
+			for _, id := range chunk {
+				if done[id] {
+					added++
+					outcomes = append(outcomes, addOutcome{id: id, status: addAccepted, detail: "added to the queue"})
+				} else {
+					outcomes = append(outcomes, addOutcome{id: id, status: addNotAdded, detail: "not added (see server message)"})
+				}
+			}
+		}
+
+		message := fmt.Sprintf("Added %d of %d %s IDs", added, len(ids), addIDTypeNames[idType])
+		return addResultMsg{gen: gen, message: message, serverMessages: serverMessages, outcomes: outcomes}
+	}
+}
+
+// lookupIMDbIDs checks which IMDb IDs are already in the library. Riven
+// only adds media by TMDB or TVDB ID.
+func lookupIMDbIDs(ctx context.Context, client api.ItemsAPI, gen int, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetItemsByIMDBIds(ctx, strings.Join(ids, ","))
+		if err != nil {
+			return addResultMsg{gen: gen, err: err}
+		}
+
+		found := map[string]models.MediaItem{}
+		for _, item := range items {
+			found[strings.ToLower(item.IMDBId)] = item
+		}
+
+		var outcomes []addOutcome
+		for _, id := range ids {
+			item, ok := found[id]
+			if !ok {
+				outcomes = append(outcomes, addOutcome{id: id, status: addMissing, detail: "not in the library; add it by TMDB or TVDB ID"})
+				continue
+			}
+			outcomes = append(outcomes, addOutcome{id: id, status: addSkipped, detail: fmt.Sprintf("in the library as %s (%s, item %s)",
+				orDefault(item.Title, "?"), orDefault(string(item.State), "?"), orDefault(item.ID, "?"))})
+		}
+
+		message := fmt.Sprintf("%d of %d IMDb IDs are in the library", len(ids)-countOutcomes(outcomes, addMissing), len(ids))
+		return addResultMsg{gen: gen, message: message, outcomes: outcomes}
+	}
+}
+
+// countOutcomes returns the number of outcomes with the given status
+func countOutcomes(outcomes []addOutcome, status addStatus) int {
+	n := 0
+	for _, o := range outcomes {
+		if o.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// cancelRequests cancels the request in flight and drops its result
+func (m *AddModel) cancelRequests() {
+	m.addFetch.stop()
+	m.submitting = false
+}
+
+// View implements tea.Model
+func (m *AddModel) View() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39")).Render("➕ Add Media")
+
+	label := func(field int, text string) string {
+		if m.editing && m.focus == field {
+			return lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render(text)
+		}
+		return text
+	}
+
+	var types []string
+	for i, name := range addIDTypeNames {
+		if addIDType(i) == m.idType {
+			name = "(•) " + name
+		} else {
+			name = "( ) " + name
+		}
+		types = append(types, name)
+	}
+	mediaType := "( ) movie  (•) tv"
+	if m.movie {
+		mediaType = "(•) movie  ( ) tv"
+	}
+
+	form := strings.Join([]string{
+		label(0, "ID type:") + "    " + strings.Join(types, "  "),
+		label(1, "Media type:") + " " + mediaType,
+		"",
+		label(2, addIDTypeNames[m.idType]+" IDs:"),
+		m.idsInput.View(),
+	}, "\n")
+
+	sections := []string{title, lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(min(m.width-4, 110)).
+		Render(form)}
+
+	if m.idType == addIMDb {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(
+			"IMDb IDs are looked up in the library; Riven adds media by TMDB or TVDB ID."))
+	}
+	if m.submitting {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("⏳ Sending..."))
+	}
+	if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
+	if m.message != "" {
+		sections = append(sections, m.renderOutcomes())
+	}
+
+	controls := "Controls: [tab] next field [←/→] change choice [enter] send [esc] leave form"
+	if !m.editing {
+		controls = "Controls: [enter/i] edit form"
+	}
+	sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(controls))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderOutcomes renders the result, the server's messages and the outcome
+// of each ID
+func (m *AddModel) renderOutcomes() string {
+	styles := map[addStatus]lipgloss.Style{
+		addAccepted: lipgloss.NewStyle().Foreground(lipgloss.Color("46")),
+		addSkipped:  lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		addMissing:  lipgloss.NewStyle().Foreground(lipgloss.Color("243")),
+		addNotAdded: lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		addFailed:   lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+	}
+	marks := map[addStatus]string{addAccepted: "✓", addSkipped: "-", addMissing: "?", addNotAdded: "-", addFailed: "✗"}
+
+	maxLines := max(m.height-20, 5)
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(m.message)}
+	for _, message := range m.serverMessages {
+		lines = append(lines, "Server: "+message)
+	}
+	for i, o := range m.outcomes {
+		if i == maxLines {
+			lines = append(lines, fmt.Sprintf("  … and %d more", len(m.outcomes)-maxLines))
+			break
+		}
+		lines = append(lines, styles[o.status].Render(fmt.Sprintf("  %s %s: %s", marks[o.status], o.id, o.detail)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseIDs(t *testing.T) {
+	ids, invalid := parseIDs("603, 27205\n603;abc 1x", addTMDB)
+	if strings.Join(ids, ",") != "603,27205" || strings.Join(invalid, ",") != "abc,1x" {
+		t.Errorf("got ids %v, invalid %v", ids, invalid)
+	}
+
+	ids, invalid = parseIDs("TT0133093 tt12 603", addIMDb)
+	if strings.Join(ids, ",") != "tt0133093" || strings.Join(invalid, ",") != "tt12,603" {
+		t.Errorf("got ids %v, invalid %v", ids, invalid)
+	}
+}
+
+func TestE2EAddScreen(t *testing.T) {
+	m := NewAddModel(newDemoClient(t), context.Background())
+	m.SetSize(140, 40)
+	m.Init()
+
+	// Invalid IDs are refused before anything is sent
+	m.idsInput.SetValue("603 tt0133093")
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !strings.Contains(m.error, "tt0133093") {
+		t.Fatalf("expected the IMDb ID to be refused as a TMDB ID, got %q", m.error)
+	}
+
+	// The Matrix is in the library already
+	m.idsInput.SetValue("603, 999001\n999002")
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.submitting {
+		t.Fatalf("expected the IDs to be sent, got %q", m.error)
+	}
+	m, _ = m.Update(cmd())
+
+	if m.error != "" || m.message != "Added 2 of 3 TMDB IDs" {
+		t.Fatalf("unexpected result %q, error %q", m.message, m.error)
+	}
+	if len(m.serverMessages) != 1 || m.serverMessages[0] != "Added 2 item(s) to the queue" {
+		t.Errorf("expected the server's message, got %q", m.serverMessages)
+	}
+	if view := m.View(); !strings.Contains(view, "Server: Added 2 item(s) to the queue") || !strings.Contains(view, "603: not added (see server message)") {
+		t.Errorf("expected the server's message and the ID it did not add:\n%s", view)
+	}
+	want := []addStatus{addNotAdded, addAccepted, addAccepted}
+	for i, o := range m.outcomes {
+		if o.status != want[i] {
+			t.Errorf("%s: expected status %d, got %d", o.id, want[i], o.status)
+		}
+	}
+
+	// IMDb IDs are looked up in the library
+	m.focus = 0
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.idType != addIMDb {
+		t.Fatalf("expected the IMDb ID type, got %d", m.idType)
+	}
+	m.idsInput.SetValue("tt0133093 tt9999999")
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+
+	if len(m.outcomes) != 2 || m.outcomes[0].status != addSkipped || m.outcomes[1].status != addMissing {
+		t.Fatalf("unexpected outcomes %+v", m.outcomes)
+	}
+	if !strings.Contains(m.View(), "in the library as The Matrix") {
+		t.Errorf("expected the library item in the view:\n%s", m.View())
+	}
+}
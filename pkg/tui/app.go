@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -11,6 +12,7 @@ import (
 
 	"riven-tui/pkg/api"
 	"riven-tui/pkg/config"
+	"riven-tui/pkg/settings"
 )
 
 // Screen represents different screens in the application
@@ -20,27 +22,58 @@ const (
 	ScreenDashboard Screen = iota
 	ScreenItems
 	ScreenItemDetail
+	ScreenAdd
+	ScreenScrape
+	ScreenScrapeWizard
 	ScreenSettings
 	ScreenLogs
+	ScreenEvents
+	ScreenCalendar
+	ScreenMount
 	ScreenHelp
 )
 
 // App represents the main application state
 type App struct {
-	client        *api.Client
+	client        api.RivenAPI
 	config        *config.Config
 	currentScreen Screen
 	width         int
 	height        int
-	ctx           context.Context
+
+	// ctx is the parent of every request made by the screens; cancel
+	// aborts them all on quit
+	ctx    context.Context
+	cancel context.CancelFunc
 
 	// Screen models
 	dashboard  *DashboardModel
 	items      *ItemsModel
 	itemDetail *ItemDetailModel
-	settings   *SettingsModel
-	logs       *LogsModel
-	help       *HelpModel
+	// itemParents are the shows and seasons a season or an episode was
+	// opened from; leaving the detail screen returns to them
+	itemParents  []*ItemDetailModel
+	add          *AddModel
+	scrape       *ScrapeModel
+	scrapeWizard *ScrapeWizardModel
+	settings     *SettingsModel
+	logs         *LogsModel
+	events       *EventsModel
+	calendar     *CalendarModel
+	mount        *MountModel
+	help         *HelpModel
+
+	// wizardReturn is the screen the scrape wizard returns to
+	wizardReturn Screen
+	// detailReturn is the screen the item detail returns to: Items, the
+	// calendar or the mount
+	detailReturn Screen
+
+	// Shared server event stream
+	bus *EventBus
+
+	// Auto-refresh timers of the screens
+	scheduler *RefreshScheduler
 
 	// Navigation
 	keys KeyMap
@@ -55,13 +88,13 @@ type App struct {
 	helpOverlay  *HelpComponent
 
 	// UI state
-	showHelp    bool
-	showConfirm bool
-	lastError   string
+	showHelp       bool
+	showConfirm    bool
+	pendingConfirm tea.Cmd
+	lastError      string
 }
 
 // Common message types
-type refreshMsg struct{}
 type errorMsg struct {
 	err error
 }
@@ -74,6 +107,35 @@ type toastMsg struct {
 	statusType StatusType
 	duration   time.Duration
 }
+type toastExpiredMsg struct{}
+
+// confirmMsg asks the user to confirm an action; onConfirm runs if they do
+type confirmMsg struct {
+	title     string
+	message   string
+	onConfirm tea.Cmd
+}
+
+// toastCmd shows a toast notification
+func toastCmd(message string, statusType StatusType) tea.Cmd {
+	return func() tea.Msg {
+		return toastMsg{message: message, statusType: statusType, duration: 4 * time.Second}
+	}
+}
+
+// confirmCmd shows a confirmation dialog that runs onConfirm when accepted
+func confirmCmd(title, message string, onConfirm tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		return confirmMsg{title: title, message: message, onConfirm: onConfirm}
+	}
+}
+
+// inputCapturer is implemented by screens that sometimes need every key,
+// for example while a text input or a modal panel has focus. Global
+// navigation keys are not applied while capturesInput returns true.
+type inputCapturer interface {
+	capturesInput() bool
+}
 
 // KeyMap defines the key bindings
 type KeyMap struct {
@@ -92,6 +154,14 @@ type KeyMap struct {
 	Items     key.Binding
 	Settings  key.Binding
 	Logs      key.Binding
+	Events    key.Binding
+	Calendar  key.Binding
+	Mount     key.Binding
+
+	// Tools
+	Add          key.Binding
+	Scrape       key.Binding
+	ManualScrape key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -149,30 +219,77 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("l"),
 			key.WithHelp("l", "logs"),
 		),
+		Events: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "events"),
+		),
+		Calendar: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "calendar"),
+		),
+		Mount: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "vfs mount"),
+		),
+		Add: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "add media"),
+		),
+		Scrape: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "scrape"),
+		),
+		ManualScrape: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "manual scrape"),
+		),
 	}
 }
 
 // NewApp creates a new application instance
 func NewApp(cfg *config.Config) *App {
-	client := api.NewClient(cfg)
-	ctx := context.Background()
+	return NewAppWithClient(cfg, api.NewClient(cfg))
+}
+
+// NewAppWithClient creates a new application instance backed by the given
+// API implementation
+func NewAppWithClient(cfg *config.Config, client api.RivenAPI) *App {
+	ctx, cancel := context.WithCancel(context.Background())
 
 	app := &App{
 		client:        client,
 		config:        cfg,
 		currentScreen: ScreenDashboard,
+		detailReturn:  ScreenItems,
 		ctx:           ctx,
+		cancel:        cancel,
 		keys:          DefaultKeyMap(),
 		theme:         GetTheme(cfg.UI.Theme),
+		scheduler:     NewRefreshScheduler(cfg.UI.RefreshInterval),
 	}
 
 	// Initialize screen models
+	app.bus = NewEventBus(client, ctx)
 	app.dashboard = NewDashboardModel(client, ctx)
 	app.items = NewItemsModel(client, ctx)
-	app.settings = NewSettingsModel(client, ctx)
+	app.add = NewAddModel(client, ctx)
+	app.scrape = NewScrapeModel(client, ctx)
+	app.scrape.SetTheme(app.theme)
+	history := settings.NewHistory(cfg.Settings.HistoryDir, cfg.Settings.HistoryLimit)
+	app.settings = NewSettingsModel(client, ctx, history)
 	app.logs = NewLogsModel(client, ctx)
+	app.events = NewEventsModel(app.bus)
+	app.calendar = NewCalendarModel(client, ctx)
+	app.calendar.SetTheme(app.theme)
+	app.mount = NewMountModel(client, ctx)
+	app.mount.SetTheme(app.theme)
 	app.help = NewHelpModel(app.keys)
 
+	// Screens that refresh themselves from server events
+	app.bus.Subscribe("dashboard", app.dashboard)
+	app.bus.Subscribe("items", app.items)
+	app.bus.Subscribe("logs", app.logs)
+
 	return app
 }
 
@@ -180,16 +297,110 @@ func NewApp(cfg *config.Config) *App {
 func (a *App) Init() tea.Cmd {
 	return tea.Batch(
 		a.dashboard.Init(),
+		a.scheduler.Start(ScreenDashboard),
+		a.bus.Start(),
 		tea.EnterAltScreen,
 	)
 }
 
 // Update implements tea.Model
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
+	// Server events go to the subscribed screens, whichever is current
+	if cmd, ok := a.bus.Update(msg); ok {
+		return a, cmd
+	}
 
 	switch msg := msg.(type) {
+	case refreshMsg:
+		next, ok := a.scheduler.Accept(msg)
+		if !ok {
+			return a, nil
+		}
+		return a, tea.Batch(next, a.updateScreen(msg.screen, msg))
+
+	case confirmMsg:
+		confirmation := NewConfirmationComponent(msg.title, msg.message, a.theme)
+		confirmation.SetSize(a.width, a.height-3)
+		a.confirmation = &confirmation
+		a.pendingConfirm = msg.onConfirm
+		a.showConfirm = true
+		return a, nil
+
+	case toastMsg:
+		toast := NewToastComponent(msg.message, msg.statusType, a.theme, msg.duration, ToastBottomRight)
+		a.toast = &toast
+		return a, tea.Tick(msg.duration, func(time.Time) tea.Msg {
+			return toastExpiredMsg{}
+		})
+
+	case toastExpiredMsg:
+		if a.toast != nil && a.toast.IsExpired() {
+			a.toast = nil
+		}
+		return a, nil
+
+	case itemActionMsg:
+		// Item actions outlive navigation; the detail screen checks the item
+		if a.itemDetail == nil {
+			return a, nil
+		}
+		return a, a.updateScreen(ScreenItemDetail, msg)
+
+	case closeItemDetailMsg:
+		if a.currentScreen != ScreenItemDetail {
+			return a, nil
+		}
+		if len(a.itemParents) > 0 {
+			return a, a.closeChildItem()
+		}
+		return a, tea.Batch(a.switchScreen(a.detailReturn), a.initScreen(a.detailReturn))
+
+	case openChildItemMsg:
+		if a.currentScreen != ScreenItemDetail || a.itemDetail == nil {
+			return a, nil
+		}
+		a.itemDetail.cancelRequests()
+		a.itemParents = append(a.itemParents, a.itemDetail)
+		a.itemDetail = NewItemDetailModel(a.client, a.ctx, msg.itemID)
+		a.itemDetail.SetTheme(a.theme)
+		a.itemDetail.activeTab = msg.tab
+		a.itemDetail.SetSize(a.width, a.height)
+		a.bus.Subscribe("item_detail", a.itemDetail)
+		return a, a.itemDetail.Init()
+
+	case openScrapeWizardMsg:
+		a.scrapeWizard = NewScrapeWizardModel(a.client, a.ctx, msg.target)
+		a.scrapeWizard.SetSize(a.width, a.height)
+		if a.currentScreen != ScreenScrapeWizard {
+			a.wizardReturn = a.currentScreen
+		}
+		return a, tea.Batch(a.switchScreen(ScreenScrapeWizard), a.scrapeWizard.Init())
+
+	case closeScrapeWizardMsg:
+		if a.currentScreen != ScreenScrapeWizard {
+			return a, nil
+		}
+		a.scrapeWizard = nil
+		cmd := a.switchScreen(a.wizardReturn)
+		if a.wizardReturn == ScreenItemDetail && a.itemDetail != nil {
+			// Completing a session adds streams to the item
+			a.bus.Subscribe("item_detail", a.itemDetail)
+			return a, tea.Batch(cmd, a.itemDetail.refetch())
+		}
+		return a, tea.Batch(cmd, a.initScreen(a.wizardReturn))
+
+	case startBulkMsg, bulkResultMsg:
+		// Bulk actions outlive navigation; they always report to Items
+		return a, a.updateScreen(ScreenItems, msg)
+
+	case startUploadMsg, logsUploadedMsg:
+		// Uploads outlive navigation; the link goes to Logs
+		return a, a.updateScreen(ScreenLogs, msg)
+
+	case settingsAppliedMsg, settingsSavedMsg, settingsHistoryMsg:
+		// Applying settings outlives navigation; the outcome goes to Settings
+		return a, a.updateScreen(ScreenSettings, msg)
+
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
@@ -197,71 +408,274 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update all screen models with new size
 		a.dashboard.SetSize(msg.Width, msg.Height)
 		a.items.SetSize(msg.Width, msg.Height)
+		a.add.SetSize(msg.Width, msg.Height)
+		a.scrape.SetSize(msg.Width, msg.Height)
 		a.settings.SetSize(msg.Width, msg.Height)
 		a.logs.SetSize(msg.Width, msg.Height)
+		a.events.SetSize(msg.Width, msg.Height)
+		a.calendar.SetSize(msg.Width, msg.Height)
+		a.mount.SetSize(msg.Width, msg.Height)
 		a.help.SetSize(msg.Width, msg.Height)
+		if a.itemDetail != nil {
+			a.itemDetail.SetSize(msg.Width, msg.Height)
+		}
+		if a.scrapeWizard != nil {
+			a.scrapeWizard.SetSize(msg.Width, msg.Height)
+		}
+		if a.confirmation != nil {
+			a.confirmation.SetSize(msg.Width, msg.Height-3)
+		}
 
 	case tea.KeyMsg:
+		if a.showConfirm && msg.String() != "ctrl+c" {
+			confirmed, dismissed := a.confirmation.Update(msg)
+			if !dismissed {
+				return a, nil
+			}
+			cmd := a.pendingConfirm
+			a.showConfirm = false
+			a.pendingConfirm = nil
+			if !confirmed {
+				return a, nil
+			}
+			return a, cmd
+		}
+
+		// Screens with a focused input get every key but ctrl+c
+		if c, ok := a.screenModel(a.currentScreen).(inputCapturer); ok && c.capturesInput() && msg.String() != "ctrl+c" {
+			break
+		}
+
 		// Handle escape key for navigation
 		if msg.String() == "esc" && a.currentScreen == ScreenItemDetail {
-			a.currentScreen = ScreenItems
-			return a, nil
+			if len(a.itemParents) > 0 {
+				return a, a.closeChildItem()
+			}
+			return a, tea.Batch(a.switchScreen(a.detailReturn), a.initScreen(a.detailReturn))
 		}
 
 		// Global key bindings
 		switch {
 		case key.Matches(msg, a.keys.Quit):
+			if a.scrapeWizard != nil {
+				// An open session would otherwise stay up until it expires
+				a.scrapeWizard.abortOnQuit()
+			}
+			a.bus.Stop()
+			a.cancel()
 			return a, tea.Quit
 		case key.Matches(msg, a.keys.Dashboard):
-			a.currentScreen = ScreenDashboard
-			return a, a.dashboard.Init()
+			return a, tea.Batch(a.switchScreen(ScreenDashboard), a.dashboard.Init())
 		case key.Matches(msg, a.keys.Items):
-			a.currentScreen = ScreenItems
-			return a, a.items.Init()
+			return a, tea.Batch(a.switchScreen(ScreenItems), a.items.Init())
 		case key.Matches(msg, a.keys.Settings):
-			a.currentScreen = ScreenSettings
-			return a, a.settings.Init()
+			return a, tea.Batch(a.switchScreen(ScreenSettings), a.settings.Init())
 		case key.Matches(msg, a.keys.Logs):
-			a.currentScreen = ScreenLogs
-			return a, a.logs.Init()
+			return a, tea.Batch(a.switchScreen(ScreenLogs), a.logs.Init())
+		case key.Matches(msg, a.keys.Events):
+			return a, tea.Batch(a.switchScreen(ScreenEvents), a.events.Init())
+		case key.Matches(msg, a.keys.Calendar):
+			return a, tea.Batch(a.switchScreen(ScreenCalendar), a.calendar.Init())
+		case key.Matches(msg, a.keys.Mount):
+			return a, tea.Batch(a.switchScreen(ScreenMount), a.mount.Init())
 		case key.Matches(msg, a.keys.Help):
-			a.currentScreen = ScreenHelp
-			return a, nil
+			return a, a.switchScreen(ScreenHelp)
+		case key.Matches(msg, a.keys.Add):
+			return a, tea.Batch(a.switchScreen(ScreenAdd), a.add.Init())
+		case key.Matches(msg, a.keys.Scrape):
+			if a.currentScreen == ScreenItemDetail && a.itemDetail != nil {
+				target := a.itemDetail.scrapeTarget()
+				target.magnet = ""
+				return a, tea.Batch(a.switchScreen(ScreenScrape), a.scrape.scrapeItem(target))
+			}
+			return a, tea.Batch(a.switchScreen(ScreenScrape), a.scrape.Init())
+		case key.Matches(msg, a.keys.ManualScrape):
+			target := scrapeTarget{}
+			if a.currentScreen == ScreenItemDetail && a.itemDetail != nil {
+				target = a.itemDetail.scrapeTarget()
+			}
+			return a, openScrapeWizardCmd(target)
 		}
 
 	case showItemDetailMsg:
-		// Navigate to item detail view
+		// Navigate to item detail view; esc returns to the calendar or the
+		// mount if it was opened from there, otherwise to Items
+		if a.currentScreen != ScreenItemDetail {
+			a.detailReturn = ScreenItems
+			if a.currentScreen == ScreenCalendar || a.currentScreen == ScreenMount {
+				a.detailReturn = a.currentScreen
+			}
+		}
+		a.itemParents = nil
 		a.itemDetail = NewItemDetailModel(a.client, a.ctx, msg.itemID)
+		a.itemDetail.SetTheme(a.theme)
 		a.itemDetail.SetSize(a.width, a.height)
-		a.currentScreen = ScreenItemDetail
-		return a, a.itemDetail.Init()
+		cmd := a.switchScreen(ScreenItemDetail)
+		a.bus.Subscribe("item_detail", a.itemDetail)
+		return a, tea.Batch(cmd, a.itemDetail.Init())
 	}
 
-	// Update current screen
-	switch a.currentScreen {
+	return a, a.updateScreen(a.currentScreen, msg)
+}
+
+// closeChildItem returns from a season or an episode to the item it was
+// opened from, which is re-fetched as it may have changed meanwhile
+func (a *App) closeChildItem() tea.Cmd {
+	a.itemDetail.cancelRequests()
+	a.itemDetail = a.itemParents[len(a.itemParents)-1]
+	a.itemParents = a.itemParents[:len(a.itemParents)-1]
+	a.itemDetail.SetSize(a.width, a.height)
+	a.bus.Subscribe("item_detail", a.itemDetail)
+	return a.itemDetail.refetch()
+}
+
+// screenModel returns the model of the given screen, or nil
+func (a *App) screenModel(screen Screen) interface{} {
+	switch screen {
+	case ScreenDashboard:
+		return a.dashboard
+	case ScreenItems:
+		return a.items
+	case ScreenItemDetail:
+		if a.itemDetail != nil {
+			return a.itemDetail
+		}
+	case ScreenAdd:
+		return a.add
+	case ScreenScrape:
+		return a.scrape
+	case ScreenScrapeWizard:
+		if a.scrapeWizard != nil {
+			return a.scrapeWizard
+		}
+	case ScreenSettings:
+		return a.settings
+	case ScreenLogs:
+		return a.logs
+	case ScreenEvents:
+		return a.events
+	case ScreenCalendar:
+		return a.calendar
+	case ScreenMount:
+		return a.mount
+	case ScreenHelp:
+		return a.help
+	}
+	return nil
+}
+
+// updateScreen forwards a message to the given screen
+func (a *App) updateScreen(screen Screen, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch screen {
 	case ScreenDashboard:
 		a.dashboard, cmd = a.dashboard.Update(msg)
-		cmds = append(cmds, cmd)
 	case ScreenItems:
 		a.items, cmd = a.items.Update(msg)
-		cmds = append(cmds, cmd)
 	case ScreenItemDetail:
 		if a.itemDetail != nil {
 			a.itemDetail, cmd = a.itemDetail.Update(msg)
-			cmds = append(cmds, cmd)
+		}
+	case ScreenAdd:
+		a.add, cmd = a.add.Update(msg)
+	case ScreenScrape:
+		a.scrape, cmd = a.scrape.Update(msg)
+	case ScreenScrapeWizard:
+		if a.scrapeWizard != nil {
+			a.scrapeWizard, cmd = a.scrapeWizard.Update(msg)
 		}
 	case ScreenSettings:
 		a.settings, cmd = a.settings.Update(msg)
-		cmds = append(cmds, cmd)
 	case ScreenLogs:
 		a.logs, cmd = a.logs.Update(msg)
-		cmds = append(cmds, cmd)
+	case ScreenEvents:
+		a.events, cmd = a.events.Update(msg)
+	case ScreenCalendar:
+		a.calendar, cmd = a.calendar.Update(msg)
+	case ScreenMount:
+		a.mount, cmd = a.mount.Update(msg)
 	case ScreenHelp:
 		a.help, cmd = a.help.Update(msg)
-		cmds = append(cmds, cmd)
 	}
 
-	return a, tea.Batch(cmds...)
+	return cmd
+}
+
+// switchScreen makes the given screen current. Work that only runs while a
+// screen is visible is stopped, and the new screen's refresh timer started.
+func (a *App) switchScreen(screen Screen) tea.Cmd {
+	if a.currentScreen == ScreenEvents && screen != ScreenEvents {
+		a.events.StopStreaming()
+	}
+	if a.currentScreen == ScreenItemDetail && screen != ScreenItemDetail {
+		// The detail screen is kept while the scrape wizard runs on top of
+		// it, but not kept up to date
+		a.bus.Unsubscribe("item_detail")
+	}
+	if a.currentScreen != screen {
+		a.cancelRequests(a.currentScreen)
+	}
+	a.scheduler.Stop(a.currentScreen)
+	a.currentScreen = screen
+
+	switch screen {
+	case ScreenDashboard, ScreenItems, ScreenItemDetail, ScreenSettings:
+		return a.scheduler.Start(screen)
+	}
+	return nil
+}
+
+// initScreen returns the command that loads a screen when it is shown
+func (a *App) initScreen(screen Screen) tea.Cmd {
+	switch screen {
+	case ScreenDashboard:
+		return a.dashboard.Init()
+	case ScreenItems:
+		return a.items.Init()
+	case ScreenAdd:
+		return a.add.Init()
+	case ScreenScrape:
+		return a.scrape.Init()
+	case ScreenSettings:
+		return a.settings.Init()
+	case ScreenLogs:
+		return a.logs.Init()
+	case ScreenEvents:
+		return a.events.Init()
+	case ScreenCalendar:
+		return a.calendar.Init()
+	case ScreenMount:
+		return a.mount.Init()
+	}
+	return nil
+}
+
+// cancelRequests aborts the requests a screen has in flight. Their results
+// are dropped, so the screen refetches when it is shown again.
+func (a *App) cancelRequests(screen Screen) {
+	switch screen {
+	case ScreenDashboard:
+		a.dashboard.cancelRequests()
+	case ScreenItems:
+		a.items.cancelRequests()
+	case ScreenItemDetail:
+		if a.itemDetail != nil {
+			a.itemDetail.cancelRequests()
+		}
+	case ScreenAdd:
+		a.add.cancelRequests()
+	case ScreenScrape:
+		a.scrape.cancelRequests()
+	case ScreenSettings:
+		a.settings.cancelRequests()
+	case ScreenLogs:
+		a.logs.cancelRequests()
+	case ScreenCalendar:
+		a.calendar.cancelRequests()
+	case ScreenMount:
+		a.mount.cancelRequests()
+	}
 }
 
 // View implements tea.Model
@@ -283,16 +697,37 @@ func (a *App) View() string {
 		} else {
 			content = "Item detail not available"
 		}
+	case ScreenAdd:
+		content = a.add.View()
+	case ScreenScrape:
+		content = a.scrape.View()
+	case ScreenScrapeWizard:
+		if a.scrapeWizard != nil {
+			content = a.scrapeWizard.View()
+		}
 	case ScreenSettings:
 		content = a.settings.View()
 	case ScreenLogs:
 		content = a.logs.View()
+	case ScreenEvents:
+		content = a.events.View()
+	case ScreenCalendar:
+		content = a.calendar.View()
+	case ScreenMount:
+		content = a.mount.View()
 	case ScreenHelp:
 		content = a.help.View()
 	default:
 		content = "Unknown screen"
 	}
 
+	if a.showConfirm && a.confirmation != nil {
+		content = a.confirmation.View()
+	}
+	if a.toast != nil && !a.toast.IsExpired() {
+		content = a.overlayToast(content)
+	}
+
 	// Add navigation bar
 	navBar := a.renderNavBar()
 
@@ -304,6 +739,28 @@ func (a *App) View() string {
 	)
 }
 
+// overlayToast draws the toast over the bottom lines of the content
+func (a *App) overlayToast(content string) string {
+	toast := lipgloss.PlaceHorizontal(a.width, lipgloss.Right, a.toast.View())
+	if a.toast.position == ToastBottomLeft || a.toast.position == ToastTopLeft {
+		toast = lipgloss.PlaceHorizontal(a.width, lipgloss.Left, a.toast.View())
+	}
+
+	lines := strings.Split(content, "\n")
+	toastLines := strings.Split(toast, "\n")
+	if len(toastLines) > len(lines) {
+		return content
+	}
+
+	switch a.toast.position {
+	case ToastTopRight, ToastTopLeft:
+		copy(lines, toastLines)
+	default:
+		copy(lines[len(lines)-len(toastLines):], toastLines)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // renderNavBar renders the navigation bar
 func (a *App) renderNavBar() string {
 	var tabs []string
@@ -316,8 +773,14 @@ func (a *App) renderNavBar() string {
 		{ScreenDashboard, "Dashboard", "d"},
 		{ScreenItems, "Media", "m"},
 		{ScreenItemDetail, "Detail", ""},
+		{ScreenAdd, "Add", "+"},
+		{ScreenScrape, "Scrape", "S"},
+		{ScreenScrapeWizard, "Manual scrape", "M"},
 		{ScreenSettings, "Settings", "s"},
 		{ScreenLogs, "Logs", "l"},
+		{ScreenEvents, "Events", "e"},
+		{ScreenCalendar, "Calendar", "C"},
+		{ScreenMount, "Mount", "V"},
 		{ScreenHelp, "Help", "?"},
 	}
 
@@ -326,6 +789,10 @@ func (a *App) renderNavBar() string {
 		if s.screen == ScreenItemDetail && a.currentScreen != ScreenItemDetail {
 			continue
 		}
+		// Skip the scrape wizard if not running
+		if s.screen == ScreenScrapeWizard && a.currentScreen != ScreenScrapeWizard {
+			continue
+		}
 
 		var style lipgloss.Style
 		if s.screen == a.currentScreen {
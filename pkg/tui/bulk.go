@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+)
+
+// bulkChunkSize caps the number of IDs sent in one request, keeping the
+// query string of large selections within server limits
+const bulkChunkSize = 50
+
+// bulkAction is an action that can be run on many items at once
+type bulkAction struct {
+	key         string // key in the actions panel
+	name        string // e.g. "Retry"
+	running     string // e.g. "Retrying"
+	done        string // e.g. "retried"
+	destructive bool
+	run         func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error)
+}
+
+// bulkActions lists the actions offered by the Items actions panel
+var bulkActions = []bulkAction{
+	{key: "r", name: "Retry", running: "Retrying", done: "retried", run: func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error) {
+		resp, err := client.RetryItems(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return resp.IDs, nil
+	}},
+	{key: "R", name: "Reset", running: "Resetting", done: "reset", run: func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error) {
+		resp, err := client.ResetItems(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return resp.IDs, nil
+	}},
+	{key: "d", name: "Remove", running: "Removing", done: "removed", destructive: true, run: func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error) {
+		resp, err := client.RemoveItems(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return resp.IDs, nil
+	}},
+	{key: "p", name: "Pause", running: "Pausing", done: "paused", run: func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error) {
+		resp, err := client.PauseItems(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return resp.IDs, nil
+	}},
+	{key: "u", name: "Unpause", running: "Unpausing", done: "unpaused", run: func(ctx context.Context, client api.ItemsAPI, ids string) ([]string, error) {
+		resp, err := client.UnpauseItems(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		return resp.IDs, nil
+	}},
+}
+
+// findBulkAction returns the action bound to a key in the actions panel
+func findBulkAction(key string) (bulkAction, bool) {
+	for _, action := range bulkActions {
+		if action.key == key {
+			return action, true
+		}
+	}
+	return bulkAction{}, false
+}
+
+// bulkTarget is an item an action is run on
+type bulkTarget struct {
+	id    string
+	title string
+}
+
+// bulkItemResult is the outcome of an action for one item
+type bulkItemResult struct {
+	bulkTarget
+	err     error
+	skipped bool // the server accepted the request but did not act on the item
+}
+
+// bulkResultMsg reports the outcome of a bulk action
+type bulkResultMsg struct {
+	action  bulkAction
+	results []bulkItemResult
+}
+
+// counts returns the number of succeeded, skipped and failed items
+func (msg bulkResultMsg) counts() (succeeded, skipped, failed int) {
+	for _, r := range msg.results {
+		switch {
+		case r.err != nil:
+			failed++
+		case r.skipped:
+			skipped++
+		default:
+			succeeded++
+		}
+	}
+	return succeeded, skipped, failed
+}
+
+// summary returns a one-line summary of the outcome
+func (msg bulkResultMsg) summary() string {
+	succeeded, skipped, failed := msg.counts()
+	parts := []string{fmt.Sprintf("%s: %d of %d items %s", msg.action.name, succeeded, len(msg.results), msg.action.done)}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", skipped))
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", failed))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// statusType returns how the outcome should be presented
+func (msg bulkResultMsg) statusType() StatusType {
+	succeeded, skipped, failed := msg.counts()
+	switch {
+	case failed > 0 && succeeded == 0:
+		return StatusError
+	case failed > 0 || skipped > 0:
+		return StatusWarning
+	}
+	return StatusSuccess
+}
+
+// runBulkAction runs an action on the targets, in chunks of bulkChunkSize.
+// Requests are marked retryable: every action is safe to repeat.
+func runBulkAction(ctx context.Context, client api.ItemsAPI, action bulkAction, targets []bulkTarget) tea.Cmd {
+	return func() tea.Msg {
+		ctx := api.WithRetry(ctx)
+		results := make([]bulkItemResult, 0, len(targets))
+
+		for start := 0; start < len(targets); start += bulkChunkSize {
+			chunk := targets[start:min(start+bulkChunkSize, len(targets))]
+			ids := make([]string, len(chunk))
+			for i, t := range chunk {
+				ids[i] = t.id
+			}
+
+			processed, err := action.run(ctx, client, strings.Join(ids, ","))
+			done := make(map[string]bool, len(processed))
+			for _, id := range processed {
+				done[id] = true
+			}
+
+			for _, t := range chunk {
+				result := bulkItemResult{bulkTarget: t}
+				switch {
+				case api.IsNotFound(err):
+					// Nothing in the chunk could be acted on
+					result.skipped = true
+				case err != nil:
+					result.err = err
+				case !done[t.id]:
+					result.skipped = true
+				}
+				results = append(results, result)
+			}
+		}
+
+		return bulkResultMsg{action: action, results: results}
+	}
+}
+
+// renderBulkResults renders the per-item outcome of a bulk action, failures
+// first, capped at maxLines items
+func renderBulkResults(msg bulkResultMsg, width, maxLines int) string {
+	results := append([]bulkItemResult(nil), msg.results...)
+	rank := func(r bulkItemResult) int {
+		switch {
+		case r.err != nil:
+			return 0
+		case r.skipped:
+			return 1
+		}
+		return 2
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return rank(results[i]) < rank(results[j])
+	})
+
+	okStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	skipStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(msg.summary())}
+	for i, r := range results {
+		if i == maxLines {
+			lines = append(lines, fmt.Sprintf("  … and %d more", len(results)-maxLines))
+			break
+		}
+		label := fmt.Sprintf("%s (%s)", truncateString(r.title, 40), r.id)
+		switch {
+		case r.err != nil:
+			lines = append(lines, errStyle.Render(fmt.Sprintf("  ✗ %s: %s", label, firstLine(describeError(r.err)))))
+		case r.skipped:
+			lines = append(lines, skipStyle.Render(fmt.Sprintf("  - %s: not %s", label, msg.action.done)))
+		default:
+			lines = append(lines, okStyle.Render(fmt.Sprintf("  ✓ %s", label)))
+		}
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("[esc] dismiss"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Width(min(width-4, 100)).
+		Render(strings.Join(lines, "\n"))
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,447 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// calendarSpan is the period shown by the calendar
+type calendarSpan int
+
+const (
+	calendarWeek calendarSpan = iota
+	calendarMonth
+)
+
+// calendarRow is a line of the agenda: a day heading or an entry of that day
+type calendarRow struct {
+	day   time.Time
+	entry *models.CalendarEntry
+}
+
+// CalendarModel represents the calendar screen: an agenda of the movies and
+// episodes airing in a week or a month, with their library state
+type CalendarModel struct {
+	client  api.SystemAPI
+	ctx     context.Context
+	width   int
+	height  int
+	loading bool
+	loaded  bool
+	error   string
+	theme   Theme
+
+	entries []models.CalendarEntry
+
+	// The period shown, starting on a Monday or the first of a month
+	span  calendarSpan
+	start time.Time
+
+	// missingOnly hides entries that are not missing from the library
+	missingOnly bool
+
+	rows   []calendarRow
+	cursor int // index of the selected entry row, -1 if none
+	offset int
+
+	now func() time.Time
+
+	// Request in flight; cancelled when the screen is left
+	calendarFetch fetchSlot
+}
+
+// NewCalendarModel creates a new calendar model
+func NewCalendarModel(client api.SystemAPI, ctx context.Context) *CalendarModel {
+	m := &CalendarModel{
+		client:  client,
+		ctx:     ctx,
+		loading: true,
+		theme:   DefaultTheme(),
+		cursor:  -1,
+		now:     time.Now,
+	}
+	m.start = m.periodStart(calendarDay(m.now()))
+	return m
+}
+
+// SetSize sets the size of the calendar screen
+func (m *CalendarModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height - 3 // Account for navigation bar
+	m.clamp()
+}
+
+// SetTheme sets the theme of the state badges
+func (m *CalendarModel) SetTheme(theme Theme) {
+	m.theme = theme
+}
+
+// Init implements tea.Model
+func (m *CalendarModel) Init() tea.Cmd {
+	return m.fetchCalendar()
+}
+
+// Update implements tea.Model
+func (m *CalendarModel) Update(msg tea.Msg) (*CalendarModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r":
+			m.loading = !m.loaded
+			return m, m.fetchCalendar()
+		case "up", "k":
+			m.moveCursor(-1)
+		case "down", "j":
+			m.moveCursor(1)
+		case "pgup":
+			m.moveCursor(-m.listHeight())
+		case "pgdown":
+			m.moveCursor(m.listHeight())
+		case "left", "p":
+			m.shift(-1)
+		case "right", "n":
+			m.shift(1)
+		case "t":
+			m.start = m.periodStart(calendarDay(m.now()))
+			m.layout()
+		case "v":
+			if m.span == calendarWeek {
+				m.span = calendarMonth
+			} else {
+				m.span = calendarWeek
+			}
+			m.start = m.periodStart(m.start)
+			m.layout()
+		case "f":
+			m.missingOnly = !m.missingOnly
+			m.layout()
+		case "enter", "o":
+			if entry := m.selected(); entry != nil && entry.ItemID != "" {
+				return m, showItemDetailCmd(entry.ItemID)
+			}
+		}
+		return m, nil
+
+	case calendarMsg:
+		if !m.calendarFetch.finish(msg.gen) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to fetch the calendar: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.error = ""
+		m.loaded = true
+		m.entries = msg.calendar.Entries()
+		m.layout()
+	}
+
+	return m, nil
+}
+
+// calendarDay returns the date of t as midnight UTC. Riven's air dates are
+// dates rather than instants, so days are compared as dates.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart returns the first day of the week or month holding day
+func (m *CalendarModel) periodStart(day time.Time) time.Time {
+	if m.span == calendarMonth {
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// periodEnd returns the first day after the period shown
+func (m *CalendarModel) periodEnd() time.Time {
+	if m.span == calendarMonth {
+		return m.start.AddDate(0, 1, 0)
+	}
+	return m.start.AddDate(0, 0, 7)
+}
+
+// shift moves the period by n weeks or months
+func (m *CalendarModel) shift(n int) {
+	if m.span == calendarMonth {
+		m.start = m.start.AddDate(0, n, 0)
+	} else {
+		m.start = m.start.AddDate(0, 0, 7*n)
+	}
+	m.layout()
+}
+
+// missing reports whether an entry has aired without reaching the library.
+// Symlinked entries are playable, so they are not missing.
+func (m *CalendarModel) missing(entry *models.CalendarEntry) bool {
+	if entry.LastState == models.StateCompleted || entry.LastState == models.StateSymlinked {
+		return false
+	}
+	return !calendarDay(*entry.AiredAt).After(calendarDay(m.now()))
+}
+
+// layout builds the agenda rows of the period, keeping the selected entry
+// when it is still shown
+func (m *CalendarModel) layout() {
+	var selectedID string
+	if entry := m.selected(); entry != nil {
+		selectedID = entry.ItemID
+	}
+
+	m.rows = nil
+	end := m.periodEnd()
+	var day time.Time
+	for i := range m.entries {
+		entry := &m.entries[i]
+		aired := calendarDay(*entry.AiredAt)
+		if aired.Before(m.start) || !aired.Before(end) {
+			continue
+		}
+		if m.missingOnly && !m.missing(entry) {
+			continue
+		}
+		if !aired.Equal(day) {
+			day = aired
+			m.rows = append(m.rows, calendarRow{day: day})
+		}
+		m.rows = append(m.rows, calendarRow{day: day, entry: entry})
+	}
+
+	// Otherwise start at the first entry airing from today on
+	today := calendarDay(m.now())
+	m.cursor = -1
+	for i, row := range m.rows {
+		if row.entry == nil {
+			continue
+		}
+		if row.entry.ItemID == selectedID {
+			m.cursor = i
+			break
+		}
+		if m.cursor < 0 || (m.rows[m.cursor].day.Before(today) && !row.day.Before(today)) {
+			m.cursor = i
+		}
+	}
+	m.offset = 0
+	m.clamp()
+}
+
+// selected returns the entry under the cursor, or nil
+func (m *CalendarModel) selected() *models.CalendarEntry {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor].entry
+}
+
+// moveCursor moves the cursor by delta entries, skipping day headings
+func (m *CalendarModel) moveCursor(delta int) {
+	if m.cursor < 0 {
+		return
+	}
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+	for i := m.cursor + step; i >= 0 && i < len(m.rows) && delta > 0; i += step {
+		if m.rows[i].entry != nil {
+			m.cursor = i
+			delta--
+		}
+	}
+	m.clamp()
+}
+
+// listHeight returns the number of agenda lines that fit on screen
+func (m *CalendarModel) listHeight() int {
+	return max(m.height-6, 1) // title, period, border and controls
+}
+
+// clamp scrolls the agenda to keep the cursor in view, with its day
+// heading where possible
+func (m *CalendarModel) clamp() {
+	height := m.listHeight()
+	if m.cursor < 0 {
+		m.offset = 0
+		return
+	}
+	top := m.cursor
+	if top > 0 && m.rows[top-1].entry == nil {
+		top--
+	}
+	if top < m.offset {
+		m.offset = top
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.rows)-height))
+}
+
+// View implements tea.Model
+func (m *CalendarModel) View() string {
+	if m.loading && !m.loaded {
+		return m.renderLoading()
+	}
+
+	if m.error != "" && !m.loaded {
+		return m.renderError()
+	}
+
+	return m.renderCalendar()
+}
+
+// renderLoading renders the loading state
+func (m *CalendarModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render("Loading calendar...")
+}
+
+// renderError renders the error state
+func (m *CalendarModel) renderError() string {
+	style := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Foreground(lipgloss.Color("196"))
+
+	return style.Render(fmt.Sprintf("Error: %s\n\nPress 'r' to refresh", m.error))
+}
+
+// renderCalendar renders the agenda of the period
+func (m *CalendarModel) renderCalendar() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	var sections []string
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Render("📅 Calendar")
+	sections = append(sections, title, m.renderPeriod())
+
+	theme := m.theme
+	today := calendarDay(m.now())
+	var lines []string
+	for i := m.offset; i < len(m.rows) && i < m.offset+m.listHeight(); i++ {
+		row := m.rows[i]
+		if row.entry == nil {
+			heading := row.day.Format("Mon 02 Jan")
+			if row.day.Equal(today) {
+				heading += " · today"
+			}
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render(heading))
+			continue
+		}
+		lines = append(lines, m.renderEntry(row.entry, i == m.cursor, theme))
+	}
+	if len(m.rows) == 0 {
+		empty := "Nothing airs in this period"
+		if m.missingOnly {
+			empty = "Nothing is missing in this period"
+		}
+		lines = append(lines, dim.Render(empty))
+	}
+	list := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Width(max(m.width-4, 20)).
+		Height(m.listHeight()).
+		Render(strings.Join(lines, "\n"))
+	sections = append(sections, list)
+
+	controls := "Controls: ↑/↓ select | ←/→ previous/next | t today | v week/month | f missing only | enter open | r refresh"
+	sections = append(sections, dim.Italic(true).Render(controls))
+	if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderPeriod renders the period shown and a count of its entries
+func (m *CalendarModel) renderPeriod() string {
+	var period string
+	if m.span == calendarMonth {
+		period = m.start.Format("January 2006")
+	} else {
+		last := m.start.AddDate(0, 0, 6)
+		period = fmt.Sprintf("Week of %s – %s", m.start.Format("Mon 02 Jan"), last.Format("Mon 02 Jan 2006"))
+	}
+
+	entries, missing := 0, 0
+	for _, row := range m.rows {
+		if row.entry == nil {
+			continue
+		}
+		entries++
+		if m.missing(row.entry) {
+			missing++
+		}
+	}
+	status := fmt.Sprintf("%s  %d items", period, entries)
+	if missing > 0 {
+		status += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(fmt.Sprintf("  ⚠ %d missing", missing))
+	}
+	if m.missingOnly {
+		status += "  [missing only]"
+	}
+	return status
+}
+
+// renderEntry renders an entry with its type, state badge and, for entries
+// that have aired without reaching the library, a warning
+func (m *CalendarModel) renderEntry(entry *models.CalendarEntry, selected bool, theme Theme) string {
+	cursor := "  "
+	if selected {
+		cursor = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render("›") + " "
+	}
+
+	state := string(entry.LastState)
+	if state == "" {
+		state = string(models.StateUnknown)
+	}
+	parts := []string{
+		cursor + fmt.Sprintf("%-50s", truncateString(entry.Title(), 50)),
+		fmt.Sprintf("%-8s", entry.ItemType),
+		theme.StateStyle(state).Render(state),
+	}
+	if m.missing(entry) {
+		parts = append(parts, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("⚠ missing"))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// calendarMsg carries the calendar
+type calendarMsg struct {
+	gen      int
+	calendar *models.CalendarResponse
+	err      error
+}
+
+// fetchCalendar fetches the calendar of the library
+func (m *CalendarModel) fetchCalendar() tea.Cmd {
+	ctx, gen := m.calendarFetch.begin(m.ctx)
+	return tea.Cmd(func() tea.Msg {
+		calendar, err := m.client.GetCalendar(ctx)
+		return calendarMsg{gen: gen, calendar: calendar, err: err}
+	})
+}
+
+// cancelRequests cancels the request in flight and drops its result
+func (m *CalendarModel) cancelRequests() {
+	m.calendarFetch.stop()
+}
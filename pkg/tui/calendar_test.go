@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+// testCalendar returns a calendar around Wednesday 14 October 2026
+func testCalendar() *models.CalendarResponse {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	return &models.CalendarResponse{Data: map[string]models.CalendarEntry{
+		"1": {ItemID: "1", ShowTitle: "Severance", ItemType: "episode", Season: 2, Episode: 8, AiredAt: day(12), LastState: models.StateCompleted},
+		"2": {ItemID: "2", ShowTitle: "Severance", ItemType: "episode", Season: 2, Episode: 9, AiredAt: day(14), LastState: models.StateFailed},
+		"3": {ItemID: "3", ShowTitle: "Severance", ItemType: "episode", Season: 2, Episode: 10, AiredAt: day(21), LastState: models.StateUnreleased},
+		"4": {ItemID: "4", ShowTitle: "Dune: Part Three", ItemType: "movie", AiredAt: day(16), LastState: models.StateUnreleased},
+	}}
+}
+
+func newTestCalendar(t *testing.T, fake *fakeAPI) *CalendarModel {
+	t.Helper()
+	m := NewCalendarModel(fake, context.Background())
+	m.now = func() time.Time { return time.Date(2026, 10, 14, 20, 0, 0, 0, time.UTC) }
+	m.start = m.periodStart(calendarDay(m.now()))
+	m.SetSize(140, 40)
+	m, _ = m.Update(m.fetchCalendar()())
+	return m
+}
+
+func TestCalendarWeekAgenda(t *testing.T) {
+	m := newTestCalendar(t, &fakeAPI{calendar: testCalendar()})
+
+	view := m.View()
+	if !strings.Contains(view, "Week of Mon 12 Oct – Sun 18 Oct 2026") {
+		t.Errorf("expected the current week, got:\n%s", view)
+	}
+	if !strings.Contains(view, "Wed 14 Oct · today") || strings.Contains(view, "S02E10") {
+		t.Errorf("expected this week's entries only, got:\n%s", view)
+	}
+	if !strings.Contains(view, "⚠ 1 missing") {
+		t.Errorf("expected the failed episode to be flagged, got:\n%s", view)
+	}
+	// The cursor starts at today
+	if entry := m.selected(); entry == nil || entry.ItemID != "2" {
+		t.Fatalf("expected today's episode to be selected, got %+v", entry)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if len(m.rows) != 2 || m.selected().ItemID != "2" {
+		t.Errorf("expected only the missing episode, got %+v", m.rows)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := m.View(); !strings.Contains(view, "Severance S02E10") || !strings.Contains(view, "Unreleased") {
+		t.Errorf("expected next week's episode, got:\n%s", view)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !m.start.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected to return to this week, got %v", m.start)
+	}
+}
+
+func TestCalendarMonthAgenda(t *testing.T) {
+	m := newTestCalendar(t, &fakeAPI{calendar: testCalendar()})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !strings.Contains(m.View(), "October 2026") || len(m.rows) != 8 {
+		t.Fatalf("expected every entry of October under its day, got %+v", m.rows)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if entry := m.selected(); entry == nil || entry.ItemID != "3" {
+		t.Errorf("expected the cursor to skip day headings, got %+v", entry)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if !strings.Contains(m.View(), "Nothing airs in this period") {
+		t.Errorf("expected September to be empty, got:\n%s", m.View())
+	}
+}
+
+func TestAppCalendarOpensItemAndReturns(t *testing.T) {
+	fake := &fakeAPI{calendar: testCalendar(), item: &models.MediaItem{ID: "2", Title: "Episode 9", Type: "episode"}}
+	app := NewAppWithClient(config.DefaultConfig(), fake)
+	app.calendar.now = func() time.Time { return time.Date(2026, 10, 14, 20, 0, 0, 0, time.UTC) }
+	app.calendar.start = app.calendar.periodStart(calendarDay(app.calendar.now()))
+	app.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if app.currentScreen != ScreenCalendar {
+		t.Fatal("expected C to open the calendar")
+	}
+	app.Update(cmd())
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(cmd())
+	if app.currentScreen != ScreenItemDetail || app.itemDetail.itemID != "2" {
+		t.Fatal("expected enter to open the selected item")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.currentScreen != ScreenCalendar {
+		t.Errorf("expected esc to return to the calendar, got screen %d", app.currentScreen)
+	}
+}
+
+func TestAppPassesThemeToCalendar(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.Theme = "light"
+	app := NewAppWithClient(cfg, &fakeAPI{})
+	if app.calendar.theme != LightTheme() {
+		t.Error("expected the calendar to use the configured theme")
+	}
+}
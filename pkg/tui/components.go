@@ -9,6 +9,8 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
 )
 
 // LoadingComponent represents a loading spinner with message
@@ -393,3 +395,32 @@ func (h HelpComponent) View() string {
 
 	return style.Render(content)
 }
+
+// describeError renders an error for display, expanding API validation
+// failures into one line per offending field
+func describeError(err error) string {
+	apiErr, ok := api.AsAPIError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	switch {
+	case api.IsValidation(err) && len(apiErr.Validation) > 0:
+		lines := []string{fmt.Sprintf("Invalid request (status %d):", apiErr.StatusCode)}
+		for _, msg := range apiErr.FieldMessages() {
+			lines = append(lines, "  • "+msg)
+		}
+		return strings.Join(lines, "\n")
+	case api.IsUnauthorized(err):
+		return fmt.Sprintf("Unauthorized (status %d): check the api.token in your configuration", apiErr.StatusCode)
+	case api.IsNotFound(err):
+		if apiErr.Detail != "" {
+			return fmt.Sprintf("Not found: %s", apiErr.Detail)
+		}
+		return fmt.Sprintf("Not found: %s", apiErr.Path)
+	case apiErr.Detail != "":
+		return fmt.Sprintf("%s (status %d)", apiErr.Detail, apiErr.StatusCode)
+	}
+
+	return apiErr.Error()
+}
@@ -14,7 +14,7 @@ import (
 
 // DashboardModel represents the dashboard screen
 type DashboardModel struct {
-	client  *api.Client
+	client  api.SystemAPI
 	ctx     context.Context
 	width   int
 	height  int
@@ -28,10 +28,20 @@ type DashboardModel struct {
 
 	// Auto-refresh
 	lastUpdate time.Time
+
+	// Live updates: while the event stream is up, item events re-fetch the
+	// stats and polling only covers services
+	live       bool
+	statsStale bool
+
+	// Requests in flight; cancelled when the screen is left
+	statsFetch    fetchSlot
+	servicesFetch fetchSlot
+	rdUserFetch   fetchSlot
 }
 
 // NewDashboardModel creates a new dashboard model
-func NewDashboardModel(client *api.Client, ctx context.Context) *DashboardModel {
+func NewDashboardModel(client api.SystemAPI, ctx context.Context) *DashboardModel {
 	return &DashboardModel{
 		client:  client,
 		ctx:     ctx,
@@ -51,7 +61,6 @@ func (m *DashboardModel) Init() tea.Cmd {
 		m.fetchStats(),
 		m.fetchServices(),
 		m.fetchRDUser(),
-		m.autoRefresh(),
 	)
 }
 
@@ -59,6 +68,13 @@ func (m *DashboardModel) Init() tea.Cmd {
 func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case statsMsg:
+		if !m.statsFetch.finish(msg.gen) {
+			return m, nil
+		}
+		if m.statsStale {
+			m.statsStale = false
+			return m, m.fetchStats()
+		}
 		m.loading = false
 		if msg.err != nil {
 			m.error = fmt.Sprintf("Failed to fetch stats: %v", msg.err)
@@ -68,6 +84,9 @@ func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 		}
 
 	case servicesMsg:
+		if !m.servicesFetch.finish(msg.gen) {
+			return m, nil
+		}
 		if msg.err != nil {
 			m.error = fmt.Sprintf("Failed to fetch services: %v", msg.err)
 		} else {
@@ -75,6 +94,9 @@ func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 		}
 
 	case rdUserMsg:
+		if !m.rdUserFetch.finish(msg.gen) {
+			return m, nil
+		}
 		if msg.err != nil {
 			// RD user might not be configured, don't show error
 		} else {
@@ -83,11 +105,14 @@ func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 
 	case refreshMsg:
 		m.lastUpdate = time.Now()
+		if m.live {
+			// Events keep the counters current; polling is only a fallback
+			return m, m.fetchServices()
+		}
 		return m, tea.Batch(
 			m.fetchStats(),
 			m.fetchServices(),
 			m.fetchRDUser(),
-			m.autoRefresh(),
 		)
 	}
 
@@ -273,47 +298,72 @@ func (m *DashboardModel) renderLastUpdate() string {
 
 // Message types for async operations
 type statsMsg struct {
+	gen   int
 	stats *models.StatsResponse
 	err   error
 }
 
 type servicesMsg struct {
+	gen      int
 	services models.ServicesResponse
 	err      error
 }
 
 type rdUserMsg struct {
+	gen    int
 	rdUser *models.RDUser
 	err    error
 }
 
+// handleEvent implements eventSubscriber
+func (m *DashboardModel) handleEvent(event models.Event) tea.Cmd {
+	if !isItemEvent(event) {
+		return nil
+	}
+	// Coalesce bursts of events into one fetch at a time
+	if m.statsFetch.busy() {
+		m.statsStale = true
+		return nil
+	}
+	return m.fetchStats()
+}
+
+// streamStateChanged implements eventSubscriber
+func (m *DashboardModel) streamStateChanged(state api.StreamState) {
+	m.live = state == api.StreamConnected
+}
+
 // fetchStats fetches system statistics
 func (m *DashboardModel) fetchStats() tea.Cmd {
+	ctx, gen := m.statsFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		stats, err := m.client.GetStats(m.ctx)
-		return statsMsg{stats: stats, err: err}
+		stats, err := m.client.GetStats(ctx)
+		return statsMsg{gen: gen, stats: stats, err: err}
 	})
 }
 
 // fetchServices fetches service status
 func (m *DashboardModel) fetchServices() tea.Cmd {
+	ctx, gen := m.servicesFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		services, err := m.client.GetServices(m.ctx)
-		return servicesMsg{services: services, err: err}
+		services, err := m.client.GetServices(ctx)
+		return servicesMsg{gen: gen, services: services, err: err}
 	})
 }
 
 // fetchRDUser fetches Real-Debrid user info
 func (m *DashboardModel) fetchRDUser() tea.Cmd {
+	ctx, gen := m.rdUserFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		rdUser, err := m.client.GetRDUser(m.ctx)
-		return rdUserMsg{rdUser: rdUser, err: err}
+		rdUser, err := m.client.GetRDUser(ctx)
+		return rdUserMsg{gen: gen, rdUser: rdUser, err: err}
 	})
 }
 
-// autoRefresh sets up auto-refresh
-func (m *DashboardModel) autoRefresh() tea.Cmd {
-	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
-		return refreshMsg{}
-	})
+// cancelRequests cancels every request in flight and drops their results
+func (m *DashboardModel) cancelRequests() {
+	m.statsFetch.stop()
+	m.servicesFetch.stop()
+	m.rdUserFetch.stop()
+	m.statsStale = false
 }
@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"riven-tui/pkg/models"
+)
+
+func TestDashboardModelRendersStats(t *testing.T) {
+	fake := &fakeAPI{
+		stats: &models.StatsResponse{
+			TotalItems: 42,
+			States:     map[models.States]int{models.StateCompleted: 40, models.StateFailed: 2},
+		},
+		services: models.ServicesResponse{"scraping": true},
+	}
+	m := NewDashboardModel(fake, context.Background())
+	m.SetSize(120, 50)
+
+	m, _ = m.Update(m.fetchStats()())
+	m, _ = m.Update(m.fetchServices()())
+	m, _ = m.Update(m.fetchRDUser()())
+
+	view := m.View()
+	for _, want := range []string{"Total Items: 42", "Completed: 40", "Failed: 2", "scraping"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in dashboard view", want)
+		}
+	}
+	if m.rdUser != nil {
+		t.Error("expected RD user to stay empty when the endpoint fails")
+	}
+}
@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/fakeriven"
+)
+
+// newDemoClient returns a real API client talking to a fake Riven server
+func newDemoClient(t *testing.T) *api.Client {
+	client, _ := newDemoServer(t)
+	return client
+}
+
+// newDemoServer is newDemoClient that also returns the fake server
+func newDemoServer(t *testing.T) (*api.Client, *fakeriven.Server) {
+	t.Helper()
+
+	fake := fakeriven.New(fakeriven.WithToken("e2e"))
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultConfig()
+	cfg.API.Endpoint = srv.URL
+	cfg.API.Token = "e2e"
+	return api.NewClient(cfg), fake
+}
+
+func TestE2EItemsScreen(t *testing.T) {
+	m := NewItemsModel(newDemoClient(t), context.Background())
+	m.SetSize(140, 40)
+
+	m, _ = m.Update(m.fetchItems()())
+
+	if m.error != "" {
+		t.Fatalf("unexpected error: %s", m.error)
+	}
+	if len(m.table.Rows()) == 0 {
+		t.Fatal("expected rows from the fake library")
+	}
+	if !strings.Contains(m.View(), "Severance") {
+		t.Error("expected a seeded show in the items view")
+	}
+}
+
+func TestE2EDashboardScreen(t *testing.T) {
+	m := NewDashboardModel(newDemoClient(t), context.Background())
+	m.SetSize(140, 60)
+
+	m, _ = m.Update(m.fetchStats()())
+	m, _ = m.Update(m.fetchServices()())
+	m, _ = m.Update(m.fetchRDUser()())
+
+	view := m.View()
+	for _, want := range []string{"Total Items", "demo"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in dashboard view", want)
+		}
+	}
+}
+
+func TestE2EItemDetailScreen(t *testing.T) {
+	m := NewItemDetailModel(newDemoClient(t), context.Background(), "1")
+	m.SetSize(140, 40)
+
+	m, _ = m.Update(m.fetchItemDetail()())
+	m, _ = m.Update(m.fetchItemStreams()())
+
+	if m.error != "" {
+		t.Fatalf("unexpected error: %s", m.error)
+	}
+	if m.item == nil || m.item.Title == "" {
+		t.Error("expected item details to be loaded")
+	}
+}
+
+func TestE2EBlacklistStream(t *testing.T) {
+	m := NewItemDetailModel(newDemoClient(t), context.Background(), "1")
+	m.SetSize(140, 40)
+	m, _ = m.Update(m.fetchItemDetail()())
+	m, _ = m.Update(m.fetchItemStreams()())
+
+	if m.streams == nil || len(m.streams.Streams) == 0 {
+		t.Fatal("expected streams for the seeded item")
+	}
+	before := len(m.streams.BlacklistedStreams)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	for _, msg := range batchMsgs(cmd) {
+		if result, ok := msg.(itemActionMsg); ok {
+			if result.err != nil {
+				t.Fatalf("blacklist failed: %v", result.err)
+			}
+			m, _ = m.Update(result)
+		}
+	}
+
+	m, _ = m.Update(m.fetchItemStreams()())
+	if got := len(m.streams.BlacklistedStreams); got != before+1 {
+		t.Errorf("expected %d blacklisted streams, got %d", before+1, got)
+	}
+}
+
+func TestE2EItemDetailSeasonTree(t *testing.T) {
+	client := newDemoClient(t)
+	search, show := "Severance", "show"
+	items, err := client.GetItems(context.Background(), &api.ItemsParams{Search: &search, Type: &show})
+	if err != nil || len(items.Items) == 0 {
+		t.Fatalf("expected the seeded show, got %v", err)
+	}
+
+	m := NewItemDetailModel(client, context.Background(), items.Items[0].ID)
+	m.SetSize(140, 40)
+	m, _ = m.Update(m.fetchItemDetail()())
+
+	if len(m.tree) != 2 || len(m.treeRows) != 2 {
+		t.Fatalf("expected 2 collapsed seasons, got %d seasons in %d rows", len(m.tree), len(m.treeRows))
+	}
+	view := m.View()
+	for _, want := range []string{"Season 1", "9/9 Completed", "5/10 Completed"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the tree:\n%s", want, view)
+		}
+	}
+
+	// Season 2 expands into its episodes
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.treeRows) != 12 {
+		t.Fatalf("expected the episodes of season 2, got %d rows", len(m.treeRows))
+	}
+
+	// Retrying an episode runs on the episode and refreshes the show
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	episode := m.treeRows[m.treeCursor].node.ID
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	var result itemActionMsg
+	for _, msg := range batchMsgs(cmd) {
+		if r, ok := msg.(itemActionMsg); ok {
+			result = r
+		}
+	}
+	if result.itemID != episode || result.err != nil {
+		t.Fatalf("expected a retry of episode %s, got %+v", episode, result)
+	}
+	m, _ = m.Update(result)
+	if m.actionRunning != nil {
+		t.Error("expected the episode action to finish")
+	}
+
+	// Enter on an episode opens its streams
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if open, ok := cmd().(openChildItemMsg); !ok || open.itemID != episode || open.tab != 1 {
+		t.Errorf("expected the episode to open on its Streams tab, got %+v", open)
+	}
+}
+
+func TestE2ECalendarScreen(t *testing.T) {
+	m := NewCalendarModel(newDemoClient(t), context.Background())
+	// The demo library's air dates are UTC days
+	m.now = func() time.Time { return time.Now().UTC() }
+	m.start = m.periodStart(calendarDay(m.now()))
+	m.SetSize(140, 40)
+
+	m, _ = m.Update(m.fetchCalendar()())
+	if m.error != "" {
+		t.Fatalf("unexpected error: %s", m.error)
+	}
+
+	// Every demo show has an episode airing today, some not downloaded
+	view := m.View()
+	if !strings.Contains(view, "Severance S02E09") || !strings.Contains(view, "missing") {
+		t.Errorf("expected this week's episodes with the missing ones flagged, got:\n%s", view)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := m.View(); !strings.Contains(view, "Severance S02E10") || !strings.Contains(view, "Unreleased") {
+		t.Errorf("expected next week's episodes, got:\n%s", view)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if open, ok := cmd().(showItemDetailMsg); !ok || open.itemID == "" {
+		t.Errorf("expected enter to open the item, got %+v", open)
+	}
+}
+
+func TestE2EMountScreen(t *testing.T) {
+	m := NewMountModel(newDemoClient(t), context.Background())
+	m.SetSize(160, 40)
+
+	m, _ = m.Update(m.fetchMount()())
+	m, _ = m.Update(m.fetchLibrary()())
+	if m.error != "" || m.libraryError != "" {
+		t.Fatalf("unexpected errors: %s %s", m.error, m.libraryError)
+	}
+
+	// The demo library links every file and leaves a few Completed items
+	// without one
+	if len(m.owners) != m.files || len(m.missing) == 0 {
+		t.Errorf("expected every file linked and missing items flagged, got %d of %d linked and %d missing",
+			len(m.owners), m.files, len(m.missing))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("matrix")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.View(), "The Matrix (1999).mkv") {
+		t.Errorf("expected the movie in the results, got:\n%s", m.View())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if open, ok := cmd().(showItemDetailMsg); !ok || open.itemID == "" {
+		t.Errorf("expected enter to open the movie, got %+v", open)
+	}
+}
@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// eventSubscriber is a screen that reacts to server events
+type eventSubscriber interface {
+	// handleEvent is called for every event received on the stream
+	handleEvent(event models.Event) tea.Cmd
+	// streamStateChanged is called when the stream connects or drops, so
+	// screens can fall back to polling while it is down
+	streamStateChanged(state api.StreamState)
+}
+
+// EventBus shares one server event stream between all screens. The App owns
+// it, feeds its messages back through Update and fans events out to the
+// subscribed screens.
+type EventBus struct {
+	client api.StreamingAPI
+	ctx    context.Context
+
+	stream *api.EventStream
+	states chan api.StreamState
+	cancel context.CancelFunc
+	state  api.StreamState
+	notice string
+	err    error
+
+	subscribers map[string]eventSubscriber
+	order       []string
+}
+
+// busEventMsg delivers an event received on the shared stream
+type busEventMsg struct {
+	stream *api.EventStream
+	event  models.Event
+}
+
+// busErrorMsg reports a stream error. Decode errors leave the stream
+// running; any other error means it has ended.
+type busErrorMsg struct {
+	stream *api.EventStream
+	err    error
+}
+
+// busStateMsg reports a connection state change
+type busStateMsg struct {
+	stream *api.EventStream
+	state  api.StreamState
+}
+
+// busClosedMsg reports that the stream has ended
+type busClosedMsg struct {
+	stream *api.EventStream
+}
+
+// NewEventBus creates an event bus. It does not connect until Start.
+func NewEventBus(client api.StreamingAPI, ctx context.Context) *EventBus {
+	return &EventBus{
+		client:      client,
+		ctx:         ctx,
+		state:       api.StreamClosed,
+		subscribers: make(map[string]eventSubscriber),
+	}
+}
+
+// Subscribe registers a screen under the given name, replacing any previous
+// subscriber with that name
+func (b *EventBus) Subscribe(name string, sub eventSubscriber) {
+	if _, ok := b.subscribers[name]; !ok {
+		b.order = append(b.order, name)
+	}
+	b.subscribers[name] = sub
+	sub.streamStateChanged(b.state)
+}
+
+// Unsubscribe removes the named subscriber
+func (b *EventBus) Unsubscribe(name string) {
+	if _, ok := b.subscribers[name]; !ok {
+		return
+	}
+	delete(b.subscribers, name)
+	for i, n := range b.order {
+		if n == name {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Subscribed reports whether a subscriber with the given name is registered
+func (b *EventBus) Subscribed(name string) bool {
+	_, ok := b.subscribers[name]
+	return ok
+}
+
+// State returns the connection state of the stream
+func (b *EventBus) State() api.StreamState {
+	return b.state
+}
+
+// Live reports whether events are currently flowing
+func (b *EventBus) Live() bool {
+	return b.state == api.StreamConnected
+}
+
+// Notice returns the last non-fatal stream problem, such as a bad payload
+func (b *EventBus) Notice() string {
+	return b.notice
+}
+
+// Err returns the error that ended the stream, if any
+func (b *EventBus) Err() error {
+	return b.err
+}
+
+// Start opens the stream, unless it is already running
+func (b *EventBus) Start() tea.Cmd {
+	if b.stream != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(b.ctx)
+	states := make(chan api.StreamState, 8)
+
+	b.cancel = cancel
+	b.states = states
+	b.err = nil
+	b.notice = ""
+	b.stream = b.client.SubscribeEvents(ctx, &api.EventStreamOptions{
+		OnStateChange: func(state api.StreamState, err error) {
+			// Never block the stream on a slow UI; the next state wins anyway
+			select {
+			case states <- state:
+			default:
+			}
+		},
+	})
+	b.setState(api.StreamConnecting)
+
+	return b.listen()
+}
+
+// Stop closes the stream
+func (b *EventBus) Stop() {
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+	b.stream = nil
+	b.setState(api.StreamClosed)
+}
+
+// Restart closes and reopens the stream
+func (b *EventBus) Restart() tea.Cmd {
+	b.Stop()
+	return b.Start()
+}
+
+// Update handles the bus messages. It returns false for any other message.
+func (b *EventBus) Update(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case busEventMsg:
+		if msg.stream != b.stream {
+			return nil, true
+		}
+		cmds := []tea.Cmd{b.listen()}
+		for _, name := range b.order {
+			cmds = append(cmds, b.subscribers[name].handleEvent(msg.event))
+		}
+		return tea.Batch(cmds...), true
+
+	case busStateMsg:
+		if msg.stream != b.stream {
+			return nil, true
+		}
+		if msg.state == api.StreamConnected {
+			b.notice = ""
+		}
+		b.setState(msg.state)
+		return b.listen(), true
+
+	case busErrorMsg:
+		if msg.stream != b.stream {
+			return nil, true
+		}
+		var decodeErr *api.EventDecodeError
+		if errors.As(msg.err, &decodeErr) {
+			b.notice = decodeErr.Error()
+		} else {
+			b.err = msg.err
+		}
+		return b.listen(), true
+
+	case busClosedMsg:
+		if msg.stream != b.stream {
+			return nil, true
+		}
+		b.stream = nil
+		b.cancel = nil
+		b.setState(api.StreamClosed)
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// setState records the state and tells the subscribers when it changes
+func (b *EventBus) setState(state api.StreamState) {
+	if state == b.state {
+		return
+	}
+	b.state = state
+	for _, name := range b.order {
+		b.subscribers[name].streamStateChanged(state)
+	}
+}
+
+// listen waits for the next message from the current stream. Each message
+// re-arms the listener, so exactly one listener runs per stream.
+func (b *EventBus) listen() tea.Cmd {
+	stream, states := b.stream, b.states
+	if stream == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		select {
+		case event, ok := <-stream.Events():
+			if !ok {
+				break
+			}
+			return busEventMsg{stream: stream, event: event}
+		case err, ok := <-stream.Errors():
+			if !ok {
+				break
+			}
+			return busErrorMsg{stream: stream, err: err}
+		case state := <-states:
+			return busStateMsg{stream: stream, state: state}
+		}
+
+		<-stream.Done()
+		return busClosedMsg{stream: stream}
+	}
+}
+
+// eventItemID returns the item an event refers to, if any
+func eventItemID(event models.Event) string {
+	switch id := event.Data["item_id"].(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	}
+	return ""
+}
+
+// isItemEvent reports whether an event changes library items
+func isItemEvent(event models.Event) bool {
+	switch event.Type {
+	case "item_update", "item_removed", "item_added":
+		return true
+	}
+	return false
+}
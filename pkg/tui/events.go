@@ -1,7 +1,6 @@
 package tui
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -14,14 +13,14 @@ import (
 	"riven-tui/pkg/models"
 )
 
-// EventsModel represents the real-time events screen
+// EventsModel represents the real-time events screen. It shows the events
+// of the shared EventBus while the screen is open.
 type EventsModel struct {
-	client   *api.Client
-	ctx      context.Context
-	width    int
-	height   int
-	loading  bool
-	error    string
+	bus     *EventBus
+	width   int
+	height  int
+	loading bool
+	error   string
 
 	// Events data
 	events      []models.Event
@@ -29,24 +28,15 @@ type EventsModel struct {
 	maxEvents   int
 
 	// Event streaming
-	eventChan   chan models.Event
-	streaming   bool
-	
+	streaming bool
+	state     api.StreamState
+
 	// Auto-refresh
 	lastUpdate time.Time
 }
 
-// eventMsg represents messages for the events screen
-type eventMsg struct {
-	event models.Event
-}
-
-type eventsErrorMsg struct {
-	err error
-}
-
 // NewEventsModel creates a new events model
-func NewEventsModel(client *api.Client, ctx context.Context) *EventsModel {
+func NewEventsModel(bus *EventBus) *EventsModel {
 	// Create events table
 	columns := []table.Column{
 		{Title: "Time", Width: 20},
@@ -74,12 +64,11 @@ func NewEventsModel(client *api.Client, ctx context.Context) *EventsModel {
 	t.SetStyles(s)
 
 	return &EventsModel{
-		client:      client,
-		ctx:         ctx,
+		bus:         bus,
 		loading:     false,
 		eventsTable: t,
 		maxEvents:   100, // Keep last 100 events
-		eventChan:   make(chan models.Event, 50),
+		state:       bus.State(),
 	}
 }
 
@@ -94,10 +83,7 @@ func (m *EventsModel) SetSize(width, height int) {
 
 // Init implements tea.Model
 func (m *EventsModel) Init() tea.Cmd {
-	return tea.Batch(
-		m.startEventStreaming(),
-		m.listenForEvents(),
-	)
+	return m.startEventStreaming()
 }
 
 // Update implements tea.Model
@@ -106,27 +92,12 @@ func (m *EventsModel) Update(msg tea.Msg) (*EventsModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-	case eventMsg:
-		m.addEvent(msg.event)
-		m.updateEventsTable()
-
-	case eventsErrorMsg:
-		m.error = fmt.Sprintf("Event streaming error: %v", msg.err)
-		m.streaming = false
-		// Try to restart streaming after a delay
-		return m, tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
-			return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}
-		})
-
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "r":
-			// Restart event streaming
+			// Reconnect the event stream
 			m.error = ""
-			return m, tea.Batch(
-				m.startEventStreaming(),
-				m.listenForEvents(),
-			)
+			return m, tea.Batch(m.startEventStreaming(), m.bus.Restart())
 
 		case "c":
 			// Clear events
@@ -134,16 +105,13 @@ func (m *EventsModel) Update(msg tea.Msg) (*EventsModel, tea.Cmd) {
 			m.updateEventsTable()
 			return m, nil
 
-		case "s":
-			// Toggle streaming
+		case "p":
+			// Pause or resume streaming
 			if m.streaming {
-				m.streaming = false
-			} else {
-				return m, tea.Batch(
-					m.startEventStreaming(),
-					m.listenForEvents(),
-				)
+				m.StopStreaming()
+				return m, nil
 			}
+			return m, m.startEventStreaming()
 		}
 
 		// Update events table
@@ -179,11 +147,18 @@ func (m *EventsModel) renderEvents() string {
 	var sections []string
 
 	// Title with streaming status
-	streamingStatus := "🔴 Disconnected"
+	streamingStatus := "⏸ Paused"
 	if m.streaming {
-		streamingStatus = "🟢 Live"
+		switch m.state {
+		case api.StreamConnected:
+			streamingStatus = "🟢 Live"
+		case api.StreamConnecting:
+			streamingStatus = "🟡 Connecting"
+		default:
+			streamingStatus = "🔴 Reconnecting"
+		}
 	}
-	
+
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("39")).
@@ -193,8 +168,8 @@ func (m *EventsModel) renderEvents() string {
 
 	// Events count and controls
 	eventCount := fmt.Sprintf("Events: %d/%d", len(m.events), m.maxEvents)
-	controls := "Controls: [r] restart [c] clear [s] toggle streaming [↑/↓] navigate"
-	
+	controls := "Controls: [r] restart [c] clear [p] pause/resume [↑/↓] navigate"
+
 	info := lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(eventCount),
@@ -202,6 +177,10 @@ func (m *EventsModel) renderEvents() string {
 	)
 	sections = append(sections, info)
 
+	if notice := m.bus.Notice(); notice != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("⚠ "+notice))
+	}
+
 	// Events table
 	sections = append(sections, m.eventsTable.View())
 
@@ -266,42 +245,34 @@ func (m *EventsModel) updateEventsTable() {
 	m.eventsTable.SetRows(rows)
 }
 
-// startEventStreaming starts the event streaming goroutine
-func (m *EventsModel) startEventStreaming() tea.Cmd {
-	return tea.Cmd(func() tea.Msg {
-		m.streaming = true
-		
-		// Start streaming in a goroutine
-		go func() {
-			err := m.client.StreamEventsSSE(m.ctx, m.eventChan)
-			if err != nil {
-				// Send error message
-				select {
-				case <-m.ctx.Done():
-					return
-				default:
-					// This would need to be handled differently in a real implementation
-					// For now, we'll just mark streaming as false
-					m.streaming = false
-				}
-			}
-		}()
-		
-		return nil
-	})
+// handleEvent implements eventSubscriber
+func (m *EventsModel) handleEvent(event models.Event) tea.Cmd {
+	m.addEvent(event)
+	m.updateEventsTable()
+	return nil
 }
 
-// listenForEvents listens for events from the event channel
-func (m *EventsModel) listenForEvents() tea.Cmd {
-	return tea.Cmd(func() tea.Msg {
-		select {
-		case event := <-m.eventChan:
-			return eventMsg{event: event}
-		case <-m.ctx.Done():
-			return nil
-		case <-time.After(100 * time.Millisecond):
-			// Timeout to prevent blocking
-			return m.listenForEvents()
+// streamStateChanged implements eventSubscriber
+func (m *EventsModel) streamStateChanged(state api.StreamState) {
+	m.state = state
+	if state == api.StreamClosed {
+		if err := m.bus.Err(); err != nil {
+			m.error = fmt.Sprintf("Event streaming error: %s", describeError(err))
 		}
-	})
+	}
+}
+
+// startEventStreaming subscribes the screen to the event bus, connecting the
+// stream if it is not running
+func (m *EventsModel) startEventStreaming() tea.Cmd {
+	m.streaming = true
+	m.bus.Subscribe("events", m)
+	return m.bus.Start()
+}
+
+// StopStreaming unsubscribes the screen from the event bus. The stream
+// itself keeps running for the other screens.
+func (m *EventsModel) StopStreaming() {
+	m.streaming = false
+	m.bus.Unsubscribe("events")
 }
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+)
+
+// runUntil executes cmd and feeds its messages back into the app until one
+// satisfies done
+func runUntil(t *testing.T, app *App, cmd tea.Cmd, done func(tea.Msg) bool) {
+	t.Helper()
+
+	deadline := time.After(5 * time.Second)
+	for cmd != nil {
+		msgs := make(chan tea.Msg, 1)
+		go func(cmd tea.Cmd) { msgs <- cmd() }(cmd)
+
+		var msg tea.Msg
+		select {
+		case msg = <-msgs:
+		case <-deadline:
+			t.Fatal("timed out waiting for message")
+		}
+
+		if done(msg) {
+			return
+		}
+		_, cmd = app.Update(msg)
+	}
+	t.Fatal("command chain ended before the expected message")
+}
+
+func TestEventBusFeedsEventsScreen(t *testing.T) {
+	client, fake := newDemoServer(t)
+	app := NewAppWithClient(config.DefaultConfig(), client)
+	app.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+
+	cmd := app.bus.Start()
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if app.currentScreen != ScreenEvents {
+		t.Fatalf("expected events screen, got %v", app.currentScreen)
+	}
+
+	runUntil(t, app, cmd, func(msg tea.Msg) bool {
+		if state, ok := msg.(busStateMsg); ok && state.state == api.StreamConnected {
+			app.Update(msg)
+			fake.Publish("item_update", "The Matrix moved to Completed", map[string]interface{}{"item_id": "1"})
+			return false
+		}
+		_, ok := msg.(busEventMsg)
+		if ok {
+			app.Update(msg)
+		}
+		return ok
+	})
+
+	if len(app.events.events) != 1 || app.events.events[0].Message != "The Matrix moved to Completed" {
+		t.Fatalf("expected the published event, got %+v", app.events.events)
+	}
+	if !app.dashboard.live || !app.items.live {
+		t.Error("expected subscribed screens to know the stream is live")
+	}
+
+	// Leaving the screen unsubscribes it; the shared stream keeps running
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.events.streaming || app.bus.Subscribed("events") {
+		t.Error("expected the events screen to unsubscribe when left")
+	}
+	if !app.bus.Live() {
+		t.Error("expected the shared stream to stay up")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	for i := 0; fake.Subscribers() > 0; i++ {
+		if i > 100 {
+			t.Fatal("expected the server connection to be closed on quit")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// fakeAPI is an in-memory api.RivenAPI for screen tests. Only the methods
+// exercised by the tests are implemented; calling any other method panics
+// through the nil embedded interface.
+type fakeAPI struct {
+	api.RivenAPI
+
+	items      *models.ItemsResponse
+	itemsErr   error
+	itemsCalls []*api.ItemsParams
+	itemsCtxs  []context.Context
+
+	item      *models.MediaItem
+	itemCalls []string
+	streams   *models.ItemStreamsResponse
+
+	actionCalls []string // "<action> <ids>"
+	actionIDs   []string
+	actionErr   error
+
+	scrape      *models.ScrapeItemResponse
+	scrapeCalls []*api.ScrapeItemParams
+	scrapeCtxs  []context.Context
+
+	stats    *models.StatsResponse
+	services models.ServicesResponse
+	calendar *models.CalendarResponse
+	mount    *models.MountResponse
+
+	logs      *models.LogsResponse
+	logsCalls int
+}
+
+func (f *fakeAPI) GetItems(ctx context.Context, params *api.ItemsParams) (*models.ItemsResponse, error) {
+	f.itemsCalls = append(f.itemsCalls, params)
+	f.itemsCtxs = append(f.itemsCtxs, ctx)
+	return f.items, f.itemsErr
+}
+
+func (f *fakeAPI) GetItem(ctx context.Context, id string, mediaType *models.MediaType, withStreams *bool) (*models.MediaItem, error) {
+	f.itemCalls = append(f.itemCalls, id)
+	if f.item == nil {
+		return nil, &api.APIError{StatusCode: 404, Detail: "Item not found"}
+	}
+	return f.item, nil
+}
+
+func (f *fakeAPI) RetryItems(ctx context.Context, ids string) (*models.RetryResponse, error) {
+	f.actionCalls = append(f.actionCalls, "retry "+ids)
+	if f.actionErr != nil {
+		return nil, f.actionErr
+	}
+	return &models.RetryResponse{IDs: f.actionIDs}, nil
+}
+
+func (f *fakeAPI) RemoveItems(ctx context.Context, ids string) (*models.RemoveResponse, error) {
+	f.actionCalls = append(f.actionCalls, "remove "+ids)
+	if f.actionErr != nil {
+		return nil, f.actionErr
+	}
+	return &models.RemoveResponse{IDs: f.actionIDs}, nil
+}
+
+func (f *fakeAPI) FfprobeMediaFiles(ctx context.Context, id int) (*models.FfprobeResponse, error) {
+	f.actionCalls = append(f.actionCalls, fmt.Sprintf("ffprobe %d", id))
+	if f.actionErr != nil {
+		return nil, f.actionErr
+	}
+	return &models.FfprobeResponse{Data: map[string]interface{}{"format": "matroska,webm"}}, nil
+}
+
+func (f *fakeAPI) GetItemStreams(ctx context.Context, itemID int) (*models.ItemStreamsResponse, error) {
+	if f.streams == nil {
+		return &models.ItemStreamsResponse{}, nil
+	}
+	return f.streams, nil
+}
+
+func (f *fakeAPI) BlacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error) {
+	f.actionCalls = append(f.actionCalls, fmt.Sprintf("blacklist %d/%d", itemID, streamID))
+	return &models.MessageResponse{Message: "Blacklisted stream"}, f.actionErr
+}
+
+func (f *fakeAPI) UnblacklistStream(ctx context.Context, itemID, streamID int) (*models.MessageResponse, error) {
+	f.actionCalls = append(f.actionCalls, fmt.Sprintf("unblacklist %d/%d", itemID, streamID))
+	return &models.MessageResponse{Message: "Unblacklisted stream"}, f.actionErr
+}
+
+func (f *fakeAPI) ScrapeItem(ctx context.Context, params *api.ScrapeItemParams) (*models.ScrapeItemResponse, error) {
+	f.scrapeCalls = append(f.scrapeCalls, params)
+	f.scrapeCtxs = append(f.scrapeCtxs, ctx)
+	return f.scrape, nil
+}
+
+func (f *fakeAPI) GetStates(ctx context.Context) (*models.StateResponse, error) {
+	return &models.StateResponse{Success: true, States: []string{"Completed", "Failed"}}, nil
+}
+
+func (f *fakeAPI) GetStats(ctx context.Context) (*models.StatsResponse, error) {
+	return f.stats, nil
+}
+
+func (f *fakeAPI) GetServices(ctx context.Context) (models.ServicesResponse, error) {
+	return f.services, nil
+}
+
+func (f *fakeAPI) GetRDUser(ctx context.Context) (*models.RDUser, error) {
+	return nil, &api.APIError{StatusCode: 404, Detail: "Real-Debrid not configured"}
+}
+
+func (f *fakeAPI) GetCalendar(ctx context.Context) (*models.CalendarResponse, error) {
+	return f.calendar, nil
+}
+
+func (f *fakeAPI) GetMount(ctx context.Context) (*models.MountResponse, error) {
+	return f.mount, nil
+}
+
+func (f *fakeAPI) GetLogs(ctx context.Context) (*models.LogsResponse, error) {
+	f.logsCalls++
+	return f.logs, nil
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"context"
+)
+
+// fetchSlot tracks the latest request of one kind, such as the current page
+// of items. Starting a request cancels the one it supersedes, and every
+// request is tagged with a generation so that results arriving late or out
+// of order can be told apart from the current one and dropped.
+type fetchSlot struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	gen    int
+}
+
+// begin cancels the request in flight, if any, and starts a new one
+func (s *fetchSlot) begin(parent context.Context) (context.Context, int) {
+	s.stop()
+	s.ctx, s.cancel = context.WithCancel(parent)
+	return s.ctx, s.gen
+}
+
+// join returns the running context, starting one if needed. It lets a group
+// of requests share a lifetime, like the row fetches of one page.
+func (s *fetchSlot) join(parent context.Context) (context.Context, int) {
+	if s.cancel == nil {
+		return s.begin(parent)
+	}
+	return s.ctx, s.gen
+}
+
+// finish ends the request and reports whether gen is the current one. A
+// false result means the request was superseded and must be ignored.
+func (s *fetchSlot) finish(gen int) bool {
+	if gen != s.gen || s.cancel == nil {
+		return false
+	}
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	return true
+}
+
+// current reports whether gen is the running request, without ending it
+func (s *fetchSlot) current(gen int) bool {
+	return gen == s.gen && s.cancel != nil
+}
+
+// busy reports whether a request is in flight
+func (s *fetchSlot) busy() bool {
+	return s.cancel != nil
+}
+
+// stop cancels the request in flight; its result will be dropped
+func (s *fetchSlot) stop() {
+	if s.cancel != nil {
+		s.cancel()
+		s.ctx, s.cancel = nil, nil
+	}
+	s.gen++
+}
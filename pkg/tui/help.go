@@ -65,6 +65,12 @@ func (m *HelpModel) View() string {
 				"  m                   Media Items\n" +
 				"  s                   Settings\n" +
 				"  l                   Logs\n" +
+				"  e                   Events\n" +
+				"  C                   Calendar\n" +
+				"  V                   VFS mount\n" +
+				"  +                   Add media\n" +
+				"  S                   Scrape\n" +
+				"  M                   Manual scrape\n" +
 				"  ?                   Help\n" +
 				"  r                   Refresh\n" +
 				"  q                   Quit",
@@ -84,16 +90,48 @@ func (m *HelpModel) View() string {
 		Margin(1, 0).
 		Render(
 			"Dashboard:\n" +
-				"  • Auto-refreshes every 30 seconds\n" +
+				"  • Auto-refreshes every ui.refresh_interval\n" +
 				"  • Press 'r' to manually refresh\n\n" +
 				"Media Items:\n" +
 				"  • 'n' - Next page\n" +
 				"  • 'p' - Previous page\n" +
-				"  • 'r' - Refresh items\n\n" +
+				"  • 'r' - Refresh items\n" +
+				"  • Space/'A'/Ctrl+A - Select item/page/all matching\n" +
+				"  • 'a' - Actions on the selection\n\n" +
+				"Item Details:\n" +
+				"  • Shows list seasons → episodes; Enter/→/← - Expand/collapse\n" +
+				"  • Enter on an episode - Streams, 'o' - Open, 't'/'R' - Retry/reset\n\n" +
+				"Add Media:\n" +
+				"  • Paste TMDB/TVDB IDs to request them, IMDb IDs to look them up\n" +
+				"  • Esc leaves the form so the global keys work\n\n" +
+				"Scrape:\n" +
+				"  • 'S' on an item scrapes it; elsewhere enter an external ID\n" +
+				"  • 'f'/'L' - Resolution/language, 'c'/'t'/'a' - Cached only/no trash/no adult\n" +
+				"  • Enter - Manual scrape with the highlighted stream\n\n" +
+				"Manual Scrape:\n" +
+				"  • Magnet → files → episode matches → complete\n" +
+				"  • 'M' on an item scrapes it, with the highlighted stream on the Streams tab\n" +
+				"  • Esc aborts the session\n\n" +
+				"Settings:\n" +
+				"  • →/← - Expand/collapse, Enter - Edit/toggle a field\n" +
+				"  • 'u'/'U' - Undo edit/all, 'a' - Apply, 'w' - Save to file\n" +
+				"  • 'x'/'X' - Export without/with secrets, 'i' - Import a file\n" +
+				"  • 'H' - History of changes, 'R' there rolls one back\n\n" +
 				"Logs:\n" +
-				"  • ↑/↓ - Scroll through logs\n" +
-				"  • 'j'/'k' - Scroll line by line\n" +
-				"  • 'r' - Refresh logs",
+				"  • ↑/↓, PgUp/PgDn, 'g'/'G' - Scroll, top/bottom\n" +
+				"  • 'f'/'F' - Minimum level, 'o' - Module filter\n" +
+				"  • '/' - Regex search, 'n'/'N' - Next/previous match\n" +
+				"  • 'w' - Wrap, 't' - Follow new lines, 'r' - Refresh logs\n" +
+				"  • 'x' - Export to a file, 'u' - Upload and copy the link\n\n" +
+				"Calendar:\n" +
+				"  • ←/→ - Previous/next week or month, 't' - Today, 'v' - Week/month\n" +
+				"  • 'f' - Missing only, Enter - Open the item\n\n" +
+				"Mount:\n" +
+				"  • →/← - Expand/collapse, Enter - Expand or open the file's item\n" +
+				"  • '/' - Fuzzy search, 'f' - Completed items without a file\n\n" +
+				"Events:\n" +
+				"  • Streams live while the screen is open\n" +
+				"  • 'p' - Pause/resume, 'c' - Clear, 'r' - Reconnect",
 		)
 	
 	sections = append(sections, infoTitle, infoContent)
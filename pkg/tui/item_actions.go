@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// itemAction is an action in the Actions tab of the item detail screen
+type itemAction struct {
+	name    string
+	running string
+	confirm bool // ask before running
+	removes bool // the item is gone afterwards
+	run     func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error)
+}
+
+// itemActionResult is what an action reports back
+type itemActionResult struct {
+	message string
+	output  string // optional details shown in the tab, such as probe data
+}
+
+// itemActionMsg reports the outcome of an item action
+type itemActionMsg struct {
+	itemID string
+	action itemAction
+	result itemActionResult
+	err    error
+}
+
+// closeItemDetailMsg returns from the item detail screen to the Items list
+type closeItemDetailMsg struct{}
+
+// fromBulkAction runs a bulk action on the single item
+func fromBulkAction(key string, confirm, removes bool) itemAction {
+	bulk, _ := findBulkAction(key)
+	return itemAction{
+		name:    bulk.name,
+		running: bulk.running,
+		confirm: confirm,
+		removes: removes,
+		run: func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error) {
+			processed, err := bulk.run(ctx, client, id)
+			if err != nil {
+				return itemActionResult{}, err
+			}
+			for _, p := range processed {
+				if p == id {
+					return itemActionResult{message: fmt.Sprintf("Item %s", bulk.done)}, nil
+				}
+			}
+			return itemActionResult{}, fmt.Errorf("item was not %s", bulk.done)
+		},
+	}
+}
+
+var (
+	retryItemAction   = fromBulkAction("r", false, false)
+	resetItemAction   = fromBulkAction("R", true, false)
+	pauseItemAction   = fromBulkAction("p", false, false)
+	unpauseItemAction = fromBulkAction("u", false, false)
+	removeItemAction  = fromBulkAction("d", true, true)
+
+	reindexItemAction = itemAction{
+		name:    "Reindex",
+		running: "Reindexing",
+		run: func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error) {
+			itemID, err := parseItemID(id)
+			if err != nil {
+				return itemActionResult{}, err
+			}
+			resp, err := client.ReindexItem(ctx, &api.ReindexParams{ItemID: &itemID})
+			if err != nil {
+				return itemActionResult{}, err
+			}
+			return itemActionResult{message: resp.Message}, nil
+		},
+	}
+
+	ffprobeItemAction = itemAction{
+		name:    "Probe media files (ffprobe)",
+		running: "Probing media files",
+		run: func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error) {
+			itemID, err := parseItemID(id)
+			if err != nil {
+				return itemActionResult{}, err
+			}
+			resp, err := client.FfprobeMediaFiles(ctx, itemID)
+			if err != nil {
+				return itemActionResult{}, err
+			}
+			output, err := json.MarshalIndent(resp.Data, "", "  ")
+			if err != nil {
+				return itemActionResult{}, fmt.Errorf("failed to format probe data: %w", err)
+			}
+			return itemActionResult{message: "Media files probed", output: string(output)}, nil
+		},
+	}
+)
+
+// resetStreamsAction resets every stream of the item, blacklisted ones
+// included
+var resetStreamsAction = itemAction{
+	name:    "Reset streams",
+	running: "Resetting streams",
+	confirm: true,
+	run: func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error) {
+		itemID, err := parseItemID(id)
+		if err != nil {
+			return itemActionResult{}, err
+		}
+		resp, err := client.ResetItemStreams(ctx, itemID)
+		if err != nil {
+			return itemActionResult{}, err
+		}
+		return itemActionResult{message: resp.Message}, nil
+	},
+}
+
+// streamBlacklistAction blacklists the stream, or takes it off the blacklist
+// if it is already on it
+func streamBlacklistAction(stream models.Stream) itemAction {
+	action := itemAction{name: "Blacklist stream", running: "Blacklisting stream"}
+	if stream.Blacklisted {
+		action = itemAction{name: "Unblacklist stream", running: "Unblacklisting stream"}
+	}
+
+	action.run = func(ctx context.Context, client api.ItemsAPI, id string) (itemActionResult, error) {
+		itemID, err := parseItemID(id)
+		if err != nil {
+			return itemActionResult{}, err
+		}
+		var resp *models.MessageResponse
+		if stream.Blacklisted {
+			resp, err = client.UnblacklistStream(ctx, itemID, stream.ID)
+		} else {
+			resp, err = client.BlacklistStream(ctx, itemID, stream.ID)
+		}
+		if err != nil {
+			return itemActionResult{}, err
+		}
+		return itemActionResult{message: resp.Message}, nil
+	}
+	return action
+}
+
+// itemActions returns the actions offered for an item in the given state
+func itemActions(state string) []itemAction {
+	pause := pauseItemAction
+	if state == string(models.StatePaused) {
+		pause = unpauseItemAction
+	}
+	return []itemAction{
+		retryItemAction,
+		resetItemAction,
+		pause,
+		removeItemAction,
+		reindexItemAction,
+		ffprobeItemAction,
+	}
+}
+
+// parseItemID converts an item ID to the integer the item endpoints expect
+func parseItemID(id string) (int, error) {
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid item ID %q", id)
+	}
+	return itemID, nil
+}
+
+// runItemAction runs an action on an item. Every action is safe to repeat,
+// so requests are marked retryable.
+func runItemAction(ctx context.Context, client api.ItemsAPI, itemID string, action itemAction) tea.Cmd {
+	return func() tea.Msg {
+		result, err := action.run(api.WithRetry(ctx), client, itemID)
+		return itemActionMsg{itemID: itemID, action: action, result: result, err: err}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -17,48 +18,81 @@ import (
 
 // ItemDetailModel represents the item detail screen
 type ItemDetailModel struct {
-	client  *api.Client
+	client  api.ItemsAPI
 	ctx     context.Context
 	width   int
 	height  int
 	loading bool
 	error   string
+	theme   Theme
 
 	// Data
-	itemID   string
-	itemData map[string]interface{}
-	streams  interface{}
+	itemID  string
+	item    *models.MediaItem
+	streams *models.ItemStreamsResponse
+
+	// streamRows holds the stream behind each row of the streams table
+	streamRows []models.Stream
 
 	// UI state
 	activeTab int // 0: Details, 1: Streams, 2: Actions
 
+	// Season tree of a show, or the episodes of a season, on the Details
+	// tab. Expanded seasons are kept by ID across refreshes.
+	tree         []models.MediaItem
+	treeRows     []treeRow
+	treeExpanded map[string]bool
+	treeCursor   int
+	treeOffset   int
+
 	// Streams table
 	streamsTable table.Model
 
+	// Actions tab
+	actionCursor  int
+	actionRunning *itemAction
+	actionSpinner LoadingComponent
+	actionOutput  string
+
 	// Auto-refresh
 	lastUpdate time.Time
+
+	// Live updates: while the event stream is up, events for this item
+	// re-fetch it and polling is skipped
+	live        bool
+	detailStale bool
+
+	// Requests in flight; cancelled when the screen is left
+	detailFetch  fetchSlot
+	streamsFetch fetchSlot
 }
 
 // ItemDetailMsg represents messages for the item detail screen
 type itemDetailMsg struct {
-	itemData map[string]interface{}
-	err      error
+	gen  int
+	item *models.MediaItem
+	err  error
 }
 
 type itemStreamsMsg struct {
-	streams interface{}
+	gen     int
+	streams *models.ItemStreamsResponse
 	err     error
 }
 
 // NewItemDetailModel creates a new item detail model
-func NewItemDetailModel(client *api.Client, ctx context.Context, itemID string) *ItemDetailModel {
+func NewItemDetailModel(client api.ItemsAPI, ctx context.Context, itemID string) *ItemDetailModel {
 	// Create streams table
 	columns := []table.Column{
-		{Title: "Title", Width: 50},
-		{Title: "Quality", Width: 10},
-		{Title: "Size", Width: 10},
-		{Title: "Cached", Width: 8},
+		{Title: "Title", Width: 40},
+		{Title: "Res", Width: 7},
+		{Title: "Codec", Width: 6},
+		{Title: "HDR", Width: 10},
+		{Title: "Audio", Width: 12},
+		{Title: "Size", Width: 9},
+		{Title: "Cached", Width: 6},
 		{Title: "Rank", Width: 6},
+		{Title: "Status", Width: 11},
 	}
 
 	t := table.New(
@@ -84,7 +118,9 @@ func NewItemDetailModel(client *api.Client, ctx context.Context, itemID string)
 		ctx:          ctx,
 		itemID:       itemID,
 		loading:      true,
+		theme:        DefaultTheme(),
 		streamsTable: t,
+		treeExpanded: make(map[string]bool),
 	}
 }
 
@@ -97,12 +133,16 @@ func (m *ItemDetailModel) SetSize(width, height int) {
 	m.streamsTable.SetHeight(m.height - 15) // Leave space for tabs and info
 }
 
+// SetTheme sets the theme of the action spinner and the season tree
+func (m *ItemDetailModel) SetTheme(theme Theme) {
+	m.theme = theme
+}
+
 // Init implements tea.Model
 func (m *ItemDetailModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.fetchItemDetail(),
 		m.fetchItemStreams(),
-		m.autoRefresh(),
 	)
 }
 
@@ -113,15 +153,26 @@ func (m *ItemDetailModel) Update(msg tea.Msg) (*ItemDetailModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case itemDetailMsg:
+		if !m.detailFetch.finish(msg.gen) {
+			return m, nil
+		}
+		if m.detailStale {
+			m.detailStale = false
+			return m, m.refetch()
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.error = fmt.Sprintf("Failed to fetch item details: %v", msg.err)
+			m.error = fmt.Sprintf("Failed to fetch item details: %s", describeError(msg.err))
 		} else {
-			m.itemData = msg.itemData
+			m.item = msg.item
 			m.error = ""
+			m.refreshTree()
 		}
 
 	case itemStreamsMsg:
+		if !m.streamsFetch.finish(msg.gen) {
+			return m, nil
+		}
 		if msg.err != nil {
 			// Streams might not be available, don't show error
 		} else {
@@ -129,12 +180,46 @@ func (m *ItemDetailModel) Update(msg tea.Msg) (*ItemDetailModel, tea.Cmd) {
 			m.updateStreamsTable()
 		}
 
+	case startItemActionMsg:
+		if !m.ownsItem(msg.itemID) {
+			return m, nil
+		}
+		return m, m.startActionOn(msg.itemID, msg.action)
+
+	case itemActionMsg:
+		if !m.ownsItem(msg.itemID) {
+			return m, nil
+		}
+		m.actionRunning = nil
+		if msg.err != nil {
+			return m, tea.Batch(
+				toastCmd(fmt.Sprintf("%s failed: %s", msg.action.name, firstLine(describeError(msg.err))), StatusError),
+				m.refetch(),
+			)
+		}
+		m.actionOutput = msg.result.output
+		toast := toastCmd(msg.result.message, StatusSuccess)
+		if msg.action.removes {
+			return m, tea.Batch(toast, func() tea.Msg { return closeItemDetailMsg{} })
+		}
+		return m, tea.Batch(toast, m.refetch())
+
+	case spinner.TickMsg:
+		if m.actionRunning == nil {
+			return m, nil
+		}
+		m.actionSpinner, cmd = m.actionSpinner.Update(msg)
+		return m, cmd
+
 	case refreshMsg:
 		m.lastUpdate = time.Now()
+		if m.live {
+			// Events keep the item current; polling is only a fallback
+			return m, nil
+		}
 		return m, tea.Batch(
 			m.fetchItemDetail(),
 			m.fetchItemStreams(),
-			m.autoRefresh(),
 		)
 
 	case tea.KeyMsg:
@@ -167,8 +252,19 @@ func (m *ItemDetailModel) Update(msg tea.Msg) (*ItemDetailModel, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.activeTab == 0 {
+			return m, m.updateTreeKeys(msg)
+		}
+
+		if m.activeTab == 2 {
+			return m, m.updateActionsTab(msg)
+		}
+
 		// Update streams table if on streams tab
 		if m.activeTab == 1 {
+			if cmd := m.updateStreamsTab(msg); cmd != nil {
+				return m, cmd
+			}
 			m.streamsTable, cmd = m.streamsTable.Update(msg)
 			cmds = append(cmds, cmd)
 		}
@@ -217,10 +313,8 @@ func (m *ItemDetailModel) renderItemDetail() string {
 
 	// Title
 	title := "📺 Item Details"
-	if m.itemData != nil {
-		if itemTitle := getStringFromMap(m.itemData, "title", ""); itemTitle != "" {
-			title = fmt.Sprintf("📺 %s", itemTitle)
-		}
+	if m.item != nil && m.item.Title != "" {
+		title = fmt.Sprintf("📺 %s", m.item.Title)
 	}
 
 	titleStyle := lipgloss.NewStyle().
@@ -286,44 +380,27 @@ func (m *ItemDetailModel) renderTabs() string {
 
 // renderDetailsTab renders the details tab content
 func (m *ItemDetailModel) renderDetailsTab() string {
-	if m.itemData == nil {
+	if m.item == nil {
 		return "No item data available."
 	}
 
-	var details []string
-
-	// Basic info
-	if id := getStringFromMap(m.itemData, "id", ""); id != "" {
-		details = append(details, fmt.Sprintf("ID: %s", id))
-	}
-	if itemType := getStringFromMap(m.itemData, "type", ""); itemType != "" {
-		details = append(details, fmt.Sprintf("Type: %s", itemType))
-	}
-	if state := getStringFromMap(m.itemData, "state", ""); state != "" {
-		details = append(details, fmt.Sprintf("State: %s", state))
-	}
-	if year := getStringFromMap(m.itemData, "year", ""); year != "" {
-		details = append(details, fmt.Sprintf("Year: %s", year))
-	}
-
-	// External IDs
-	if tmdbId := getStringFromMap(m.itemData, "tmdb_id", ""); tmdbId != "" {
-		details = append(details, fmt.Sprintf("TMDB ID: %s", tmdbId))
-	}
-	if tvdbId := getStringFromMap(m.itemData, "tvdb_id", ""); tvdbId != "" {
-		details = append(details, fmt.Sprintf("TVDB ID: %s", tvdbId))
-	}
-	if imdbId := getStringFromMap(m.itemData, "imdb_id", ""); imdbId != "" {
-		details = append(details, fmt.Sprintf("IMDB ID: %s", imdbId))
-	}
+	details := m.detailLines()
 
 	// Overview
-	if overview := getStringFromMap(m.itemData, "overview", ""); overview != "" {
+	if overview := m.item.Overview; overview != "" {
 		details = append(details, "")
 		details = append(details, "Overview:")
+		if len(m.tree) > 0 {
+			// Leave the room to the seasons
+			overview = truncateString(strings.SplitN(overview, "\n", 2)[0], max(m.width-12, 20))
+		}
 		details = append(details, overview)
 	}
 
+	if len(m.tree) > 0 {
+		details = append(details, "", m.renderTree())
+	}
+
 	content := strings.Join(details, "\n")
 
 	contentStyle := lipgloss.NewStyle().
@@ -335,35 +412,126 @@ func (m *ItemDetailModel) renderDetailsTab() string {
 	return contentStyle.Render(content)
 }
 
+// detailLines returns the basic details of the item
+func (m *ItemDetailModel) detailLines() []string {
+	var details []string
+
+	// Basic info
+	item := m.item
+	if item.ID != "" {
+		details = append(details, fmt.Sprintf("ID: %s", item.ID))
+	}
+	if item.Type != "" {
+		details = append(details, fmt.Sprintf("Type: %s", item.Type))
+	}
+	if item.State != "" {
+		details = append(details, fmt.Sprintf("State: %s", item.State))
+	}
+	if item.Year != nil {
+		details = append(details, fmt.Sprintf("Year: %d", *item.Year))
+	}
+	if item.AiredAt != nil {
+		details = append(details, fmt.Sprintf("Aired: %s", item.AiredAt.Format("2006-01-02")))
+	}
+
+	// External IDs
+	if item.TMDBId != "" {
+		details = append(details, fmt.Sprintf("TMDB ID: %s", item.TMDBId))
+	}
+	if item.TVDBId != "" {
+		details = append(details, fmt.Sprintf("TVDB ID: %s", item.TVDBId))
+	}
+	if item.IMDBId != "" {
+		details = append(details, fmt.Sprintf("IMDB ID: %s", item.IMDBId))
+	}
+
+	return details
+}
+
+// renderTree renders the visible part of the season tree
+func (m *ItemDetailModel) renderTree() string {
+	theme := m.theme
+	now := time.Now()
+	header := "Seasons:"
+	if m.item.Type == "season" {
+		header = "Episodes:"
+	}
+	lines := []string{header}
+
+	end := min(m.treeOffset+m.treeHeight(), len(m.treeRows))
+	for i := m.treeOffset; i < end; i++ {
+		row := m.treeRows[i]
+		lines = append(lines, renderTreeRow(row, m.treeExpanded[row.node.ID], i == m.treeCursor, now, theme))
+	}
+
+	footer := "[↑/↓] move [enter] expand/streams [o] open [t] retry [R] reset"
+	if len(m.treeRows) > end-m.treeOffset {
+		footer = fmt.Sprintf("%d-%d of %d | %s", m.treeOffset+1, end, len(m.treeRows), footer)
+	}
+	if m.actionRunning != nil {
+		footer = m.actionSpinner.View()
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(footer))
+	return strings.Join(lines, "\n")
+}
+
 // renderStreamsTab renders the streams tab content
 func (m *ItemDetailModel) renderStreamsTab() string {
-	if m.streams == nil {
+	if m.streams == nil || len(m.streamRows) == 0 {
 		return lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("62")).
 			Padding(1, 2).
 			Height(m.height - 10).
-			Render("No streams data available.")
+			Render("No streams data available.\n\n[X] reset streams")
 	}
 
-	return m.streamsTable.View()
+	status := fmt.Sprintf("%d streams, %d blacklisted | [b] blacklist/unblacklist [X] reset all streams",
+		len(m.streams.Streams), len(m.streams.BlacklistedStreams))
+	if m.actionRunning != nil {
+		status = m.actionSpinner.View()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.streamsTable.View(),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(status),
+	)
 }
 
 // renderActionsTab renders the actions tab content
 func (m *ItemDetailModel) renderActionsTab() string {
-	actions := []string{
-		"Available Actions:",
-		"",
-		"• Retry item processing",
-		"• Reset item state",
-		"• Pause/unpause item",
-		"• Remove item",
-		"• Reindex item",
-		"",
-		"Note: Action implementation coming soon.",
+	lines := []string{"Available Actions:", ""}
+
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	for i, action := range m.actions() {
+		label := action.name
+		if action.confirm {
+			label += " …"
+		}
+		if i == m.actionCursor {
+			lines = append(lines, selectedStyle.Render("▸ "+label))
+		} else {
+			lines = append(lines, "  "+label)
+		}
 	}
 
-	content := strings.Join(actions, "\n")
+	lines = append(lines, "")
+	if m.actionRunning != nil {
+		lines = append(lines, m.actionSpinner.View())
+	} else {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("[↑/↓] choose [enter] run"))
+	}
+
+	if m.actionOutput != "" {
+		lines = append(lines, "")
+		output := strings.Split(m.actionOutput, "\n")
+		if room := m.height - 14 - len(lines); room > 0 && len(output) > room {
+			output = append(output[:room-1], "…")
+		}
+		lines = append(lines, output...)
+	}
+
+	content := strings.Join(lines, "\n")
 
 	contentStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -374,37 +542,393 @@ func (m *ItemDetailModel) renderActionsTab() string {
 	return contentStyle.Render(content)
 }
 
+// actions returns the actions offered for the item
+func (m *ItemDetailModel) actions() []itemAction {
+	state := ""
+	if m.item != nil {
+		state = string(m.item.State)
+	}
+	return itemActions(state)
+}
+
+// updateActionsTab handles keys on the Actions tab
+func (m *ItemDetailModel) updateActionsTab(msg tea.KeyMsg) tea.Cmd {
+	if m.actionRunning != nil {
+		return nil
+	}
+
+	actions := m.actions()
+	switch msg.String() {
+	case "up", "k":
+		if m.actionCursor > 0 {
+			m.actionCursor--
+		}
+	case "down", "j":
+		if m.actionCursor < len(actions)-1 {
+			m.actionCursor++
+		}
+	case "enter":
+		action := actions[m.actionCursor]
+		if !action.confirm {
+			return m.startAction(action)
+		}
+		title := m.title()
+		message := fmt.Sprintf("%s %q?", action.name, title)
+		if action.removes {
+			message += "\n\nThis cannot be undone."
+		}
+		itemID := m.itemID
+		return confirmCmd(action.name+" item", message, func() tea.Msg {
+			return startItemActionMsg{itemID: itemID, action: action}
+		})
+	}
+	return nil
+}
+
+// startItemActionMsg starts a confirmed item action
+type startItemActionMsg struct {
+	itemID string
+	action itemAction
+}
+
+// startAction runs an action on the item, showing a spinner until it is done
+func (m *ItemDetailModel) startAction(action itemAction) tea.Cmd {
+	return m.startActionOn(m.itemID, action)
+}
+
+// startActionOn runs an action on the item or one of its seasons or
+// episodes
+func (m *ItemDetailModel) startActionOn(itemID string, action itemAction) tea.Cmd {
+	m.actionRunning = &action
+	m.actionOutput = ""
+	m.actionSpinner = NewLoadingComponent(action.running+"...", m.theme)
+	return tea.Batch(
+		m.actionSpinner.Init(),
+		runItemAction(m.ctx, m.client, itemID, action),
+	)
+}
+
+// ownsItem reports whether an item ID is the item or one of its seasons
+// or episodes
+func (m *ItemDetailModel) ownsItem(itemID string) bool {
+	if itemID == m.itemID {
+		return true
+	}
+	for _, season := range m.tree {
+		if season.ID == itemID {
+			return true
+		}
+		for _, ep := range season.Episodes {
+			if ep.ID == itemID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// refreshTree rebuilds the season tree from the item, keeping the cursor
+// on the same season or episode
+func (m *ItemDetailModel) refreshTree() {
+	selected := ""
+	if m.treeCursor < len(m.treeRows) {
+		selected = m.treeRows[m.treeCursor].node.ID
+	}
+	m.tree = seasonTree(m.item)
+	m.layoutTree()
+	for i, row := range m.treeRows {
+		if row.node.ID == selected {
+			m.treeCursor = i
+			break
+		}
+	}
+	m.clampTree()
+}
+
+// layoutTree recomputes the visible rows after seasons expand or collapse
+func (m *ItemDetailModel) layoutTree() {
+	m.treeRows = flattenTree(m.tree, m.item.Type, m.treeExpanded)
+}
+
+// clampTree keeps the cursor on a row and in view
+func (m *ItemDetailModel) clampTree() {
+	m.treeCursor = max(0, min(m.treeCursor, len(m.treeRows)-1))
+	height := m.treeHeight()
+	if m.treeCursor < m.treeOffset {
+		m.treeOffset = m.treeCursor
+	} else if m.treeCursor >= m.treeOffset+height {
+		m.treeOffset = m.treeCursor - height + 1
+	}
+	m.treeOffset = max(0, min(m.treeOffset, len(m.treeRows)-height))
+}
+
+// treeHeight is the number of tree rows that fit on the Details tab under
+// the item's details
+func (m *ItemDetailModel) treeHeight() int {
+	return max(m.height-10-4-len(m.detailLines())-3, 3)
+}
+
+// updateTreeKeys handles the season tree keys on the Details tab
+func (m *ItemDetailModel) updateTreeKeys(msg tea.KeyMsg) tea.Cmd {
+	if len(m.treeRows) == 0 {
+		return nil
+	}
+	row := m.treeRows[m.treeCursor]
+
+	switch msg.String() {
+	case "up", "k":
+		m.treeCursor--
+	case "down", "j":
+		m.treeCursor++
+	case "pgup":
+		m.treeCursor -= m.treeHeight()
+	case "pgdown":
+		m.treeCursor += m.treeHeight()
+	case "home", "g":
+		m.treeCursor = 0
+	case "end", "G":
+		m.treeCursor = len(m.treeRows) - 1
+	case "right":
+		if row.season && !m.treeExpanded[row.node.ID] {
+			m.treeExpanded[row.node.ID] = true
+			m.layoutTree()
+		}
+	case "left":
+		if row.season {
+			delete(m.treeExpanded, row.node.ID)
+		} else if row.depth > 0 {
+			// Collapse the season the episode is in
+			for i := m.treeCursor; i >= 0; i-- {
+				if m.treeRows[i].season {
+					delete(m.treeExpanded, m.treeRows[i].node.ID)
+					m.treeCursor = i
+					break
+				}
+			}
+		}
+		m.layoutTree()
+	case "enter", " ":
+		if row.season {
+			if m.treeExpanded[row.node.ID] {
+				delete(m.treeExpanded, row.node.ID)
+			} else {
+				m.treeExpanded[row.node.ID] = true
+			}
+			m.layoutTree()
+			break
+		}
+		// Episodes open on their Streams tab
+		return openChildItemCmd(row.node.ID, 1)
+	case "o":
+		return openChildItemCmd(row.node.ID, 0)
+	case "t":
+		if m.actionRunning != nil {
+			return nil
+		}
+		return m.startActionOn(row.node.ID, retryItemAction)
+	case "R":
+		if m.actionRunning != nil {
+			return nil
+		}
+		itemID := row.node.ID
+		action := resetItemAction
+		title := fmt.Sprintf("%s of %q", treeLabel(row.node, row.season), m.title())
+		return confirmCmd("Reset "+strings.ToLower(treeLabel(row.node, row.season)), fmt.Sprintf("Reset %s?\n\nIts streams are dropped and it is scraped again.", title), func() tea.Msg {
+			return startItemActionMsg{itemID: itemID, action: action}
+		})
+	}
+	m.clampTree()
+	return nil
+}
+
+// openChildItemMsg opens a season or an episode of the item in its own
+// detail screen, on the given tab. Leaving it returns to the item.
+type openChildItemMsg struct {
+	itemID string
+	tab    int
+}
+
+// openChildItemCmd opens a season or an episode of the item
+func openChildItemCmd(itemID string, tab int) tea.Cmd {
+	return func() tea.Msg {
+		return openChildItemMsg{itemID: itemID, tab: tab}
+	}
+}
+
 // updateStreamsTable updates the streams table with current data
 func (m *ItemDetailModel) updateStreamsTable() {
-	// TODO: Parse streams data and populate table
-	// This would need to be implemented based on the actual streams data structure
-	var rows []table.Row
-	rows = append(rows, table.Row{"Sample Stream", "1080p", "2.5GB", "Yes", "100"})
+	m.streamRows = nil
+	if m.streams != nil {
+		m.streamRows = append(m.streamRows, m.streams.Streams...)
+		for _, stream := range m.streams.BlacklistedStreams {
+			// Only the list a stream is in says whether it is blacklisted
+			stream.Blacklisted = true
+			m.streamRows = append(m.streamRows, stream)
+		}
+	}
+
+	rows := make([]table.Row, 0, len(m.streamRows))
+	for _, stream := range m.streamRows {
+		parsed := stream.ParsedData
+
+		title := stream.RawTitle
+		if title == "" {
+			title = parsed.RawTitle
+		}
+		cached := "No"
+		if stream.IsCached {
+			cached = "Yes"
+		}
+		status := ""
+		if stream.Blacklisted {
+			status = "Blacklisted"
+		}
+
+		rows = append(rows, table.Row{
+			truncateString(title, 38),
+			orDash(parsed.Resolution),
+			orDash(derefString(parsed.Codec)),
+			orDash(strings.Join(parsed.HDR, ",")),
+			orDash(strings.Join(parsed.Audio, ",")),
+			orDash(derefString(parsed.Size)),
+			cached,
+			strconv.Itoa(stream.Rank),
+			status,
+		})
+	}
+
 	m.streamsTable.SetRows(rows)
+	if cursor := m.streamsTable.Cursor(); cursor >= len(rows) && len(rows) > 0 {
+		m.streamsTable.SetCursor(len(rows) - 1)
+	}
+}
+
+// updateStreamsTab handles the stream management keys on the Streams tab
+func (m *ItemDetailModel) updateStreamsTab(msg tea.KeyMsg) tea.Cmd {
+	if m.actionRunning != nil {
+		return nil
+	}
+
+	switch msg.String() {
+	case "b":
+		cursor := m.streamsTable.Cursor()
+		if cursor < 0 || cursor >= len(m.streamRows) {
+			return nil
+		}
+		return m.startAction(streamBlacklistAction(m.streamRows[cursor]))
+
+	case "X":
+		action := resetStreamsAction
+		itemID := m.itemID
+		title := m.title()
+		return confirmCmd("Reset streams", fmt.Sprintf("Reset all streams of %q?\n\nBlacklisted streams are restored and streams are scraped again.", title), func() tea.Msg {
+			return startItemActionMsg{itemID: itemID, action: action}
+		})
+	}
+	return nil
+}
+
+// title returns the title of the item, or its ID until it is loaded
+func (m *ItemDetailModel) title() string {
+	if m.item == nil || m.item.Title == "" {
+		return m.itemID
+	}
+	return m.item.Title
+}
+
+// orDefault returns s, or fallback if it is empty
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// orDash returns s, or "-" if it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// derefString returns the string s points to, or "" if it is nil
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// handleEvent implements eventSubscriber
+func (m *ItemDetailModel) handleEvent(event models.Event) tea.Cmd {
+	if !isItemEvent(event) || eventItemID(event) != m.itemID {
+		return nil
+	}
+	// Coalesce bursts of events into one fetch at a time
+	if m.detailFetch.busy() {
+		m.detailStale = true
+		return nil
+	}
+	return m.refetch()
+}
+
+// streamStateChanged implements eventSubscriber
+func (m *ItemDetailModel) streamStateChanged(state api.StreamState) {
+	m.live = state == api.StreamConnected
+}
+
+// refetch re-fetches the item and its streams
+func (m *ItemDetailModel) refetch() tea.Cmd {
+	return tea.Batch(
+		m.fetchItemDetail(),
+		m.fetchItemStreams(),
+	)
 }
 
 // fetchItemDetail fetches item details from the API
 func (m *ItemDetailModel) fetchItemDetail() tea.Cmd {
+	ctx, gen := m.detailFetch.begin(m.ctx)
+	itemID := m.itemID
 	return tea.Cmd(func() tea.Msg {
-		itemData, err := m.client.GetItem(m.ctx, m.itemID, nil, models.BoolPtr(true))
-		return itemDetailMsg{itemData: itemData, err: err}
+		item, err := m.client.GetItem(ctx, itemID, nil, models.BoolPtr(true))
+		return itemDetailMsg{gen: gen, item: item, err: err}
 	})
 }
 
 // fetchItemStreams fetches item streams from the API
 func (m *ItemDetailModel) fetchItemStreams() tea.Cmd {
+	ctx, gen := m.streamsFetch.begin(m.ctx)
+	itemID := m.itemID
 	return tea.Cmd(func() tea.Msg {
-		if itemIDInt, err := strconv.Atoi(m.itemID); err == nil {
-			streams, err := m.client.GetItemStreams(m.ctx, itemIDInt)
-			return itemStreamsMsg{streams: streams, err: err}
+		if itemIDInt, err := strconv.Atoi(itemID); err == nil {
+			streams, err := m.client.GetItemStreams(ctx, itemIDInt)
+			return itemStreamsMsg{gen: gen, streams: streams, err: err}
 		}
-		return itemStreamsMsg{streams: nil, err: fmt.Errorf("invalid item ID")}
+		return itemStreamsMsg{gen: gen, streams: nil, err: fmt.Errorf("invalid item ID")}
 	})
 }
 
-// autoRefresh sets up auto-refresh
-func (m *ItemDetailModel) autoRefresh() tea.Cmd {
-	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
-		return refreshMsg{}
-	})
+// cancelRequests cancels every request in flight and drops their results
+func (m *ItemDetailModel) cancelRequests() {
+	m.detailFetch.stop()
+	m.streamsFetch.stop()
+	m.detailStale = false
+}
+
+// scrapeTarget returns the item as the target of a manual scrape. On the
+// Streams tab the highlighted stream's infohash is used as the magnet.
+func (m *ItemDetailModel) scrapeTarget() scrapeTarget {
+	target := scrapeTarget{
+		itemID:   m.itemID,
+		title:    m.title(),
+		itemType: "movie",
+	}
+	if m.item != nil && m.item.Type != "" {
+		target.itemType = m.item.Type
+	}
+	if cursor := m.streamsTable.Cursor(); m.activeTab == 1 && cursor >= 0 && cursor < len(m.streamRows) {
+		target.magnet = m.streamRows[cursor].InfoHash
+	}
+	return target
 }
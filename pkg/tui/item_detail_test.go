@@ -0,0 +1,266 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+// batchMsgs runs a command and returns the messages it produces, expanding
+// nested batches. Commands must not block, so ticks are not allowed.
+func batchMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, batchMsgs(c)...)
+	}
+	return msgs
+}
+
+func newTestItemDetail(t *testing.T, fake *fakeAPI) *ItemDetailModel {
+	t.Helper()
+	fake.item = &models.MediaItem{ID: "1", Title: "The Matrix", Type: "movie", State: models.StateCompleted}
+	m := NewItemDetailModel(fake, context.Background(), "1")
+	m.SetSize(120, 40)
+	m, _ = m.Update(m.fetchItemDetail()())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	return m
+}
+
+func TestItemDetailRunsAction(t *testing.T) {
+	fake := &fakeAPI{actionIDs: []string{"1"}}
+	m := newTestItemDetail(t, fake)
+
+	// Retry is first and needs no confirmation
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.actionRunning == nil || !strings.Contains(m.View(), "Retrying...") {
+		t.Fatalf("expected a spinner while the action runs, got:\n%s", m.View())
+	}
+
+	var result tea.Msg
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(itemActionMsg); ok {
+			result = msg
+		}
+	}
+	if result == nil {
+		t.Fatal("expected the action to run")
+	}
+
+	calls := len(fake.itemCalls)
+	m, cmd = m.Update(result)
+	if m.actionRunning != nil {
+		t.Error("expected the spinner to stop")
+	}
+
+	var toast toastMsg
+	for _, msg := range batchMsgs(cmd) {
+		if t, ok := msg.(toastMsg); ok {
+			toast = t
+		}
+	}
+	if toast.message != "Item retried" || toast.statusType != StatusSuccess {
+		t.Errorf("unexpected toast %+v", toast)
+	}
+	if len(fake.itemCalls) != calls+1 {
+		t.Error("expected the item to be re-fetched")
+	}
+}
+
+func TestItemDetailConfirmsRemove(t *testing.T) {
+	fake := &fakeAPI{actionIDs: []string{"1"}}
+	m := newTestItemDetail(t, fake)
+
+	for i := 0; i < 3; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	confirm, ok := cmd().(confirmMsg)
+	if !ok || !strings.Contains(confirm.message, "cannot be undone") {
+		t.Fatalf("expected a confirmation for remove, got %#v", confirm)
+	}
+	if len(fake.actionCalls) != 0 {
+		t.Fatal("expected nothing to run before confirmation")
+	}
+
+	m, cmd = m.Update(confirm.onConfirm())
+	var result tea.Msg
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(itemActionMsg); ok {
+			result = msg
+		}
+	}
+	_, cmd = m.Update(result)
+
+	closed := false
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(closeItemDetailMsg); ok {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("expected the screen to close after removing the item")
+	}
+}
+
+func TestItemDetailShowsProbeOutput(t *testing.T) {
+	fake := &fakeAPI{}
+	m := newTestItemDetail(t, fake)
+	m.actionCursor = len(m.actions()) - 1
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(itemActionMsg); ok {
+			m, _ = m.Update(msg)
+		}
+	}
+
+	if fake.actionCalls[0] != "ffprobe 1" {
+		t.Errorf("unexpected calls %v", fake.actionCalls)
+	}
+	if !strings.Contains(m.View(), "matroska,webm") {
+		t.Errorf("expected probe data in the Actions tab, got:\n%s", m.View())
+	}
+}
+
+func TestItemDetailReportsActionFailure(t *testing.T) {
+	fake := &fakeAPI{actionErr: &api.APIError{StatusCode: 400, Detail: "Item has no media file to probe"}}
+	m := newTestItemDetail(t, fake)
+	m.actionCursor = len(m.actions()) - 1
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	var toast toastMsg
+	for _, msg := range batchMsgs(cmd) {
+		if result, ok := msg.(itemActionMsg); ok {
+			_, next := m.Update(result)
+			for _, msg := range batchMsgs(next) {
+				if t, ok := msg.(toastMsg); ok {
+					toast = t
+				}
+			}
+		}
+	}
+	if toast.statusType != StatusError || !strings.Contains(toast.message, "no media file") {
+		t.Errorf("expected an error toast, got %+v", toast)
+	}
+}
+
+func TestItemDetailStreamsTab(t *testing.T) {
+	codec := "hevc"
+	size := "14.2 GB"
+	fake := &fakeAPI{streams: &models.ItemStreamsResponse{
+		Streams: []models.Stream{{
+			ID: 7, RawTitle: "The.Matrix.1999.2160p.UHD.BluRay.HDR.x265-GROUP", Rank: 9000, IsCached: true,
+			ParsedData: models.ParsedData{Resolution: "2160p", Codec: &codec, HDR: []string{"HDR10"}, Audio: []string{"TrueHD"}, Size: &size},
+		}},
+		BlacklistedStreams: []models.Stream{{ID: 8, RawTitle: "The.Matrix.1999.CAM", Rank: -100}},
+	}}
+	m := newTestItemDetail(t, fake)
+	m, _ = m.Update(m.fetchItemStreams()())
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+
+	rows := m.streamsTable.Rows()
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 stream rows, got %d", len(rows))
+	}
+	want := []string{"2160p", "hevc", "HDR10", "TrueHD", "14.2 GB", "Yes", "9000", ""}
+	for i, cell := range want {
+		if rows[0][i+1] != cell {
+			t.Errorf("column %d: got %q, want %q", i+1, rows[0][i+1], cell)
+		}
+	}
+	if rows[1][8] != "Blacklisted" {
+		t.Errorf("expected the second stream to be marked blacklisted, got %v", rows[1])
+	}
+
+	// The blacklisted stream is taken off the blacklist
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	batchMsgs(cmd)
+	if len(fake.actionCalls) != 1 || fake.actionCalls[0] != "unblacklist 1/8" {
+		t.Errorf("unexpected calls %v", fake.actionCalls)
+	}
+}
+
+func TestSeasonTree(t *testing.T) {
+	var show models.MediaItem
+	err := json.Unmarshal([]byte(`{
+		"type": "show",
+		"seasons": [
+			{"id": 2, "number": 1, "state": "PartiallyCompleted", "episodes": [
+				{"id": 3, "number": 1, "state": "Completed", "aired_at": "2024-01-05 00:00:00"},
+				{"id": 4, "number": "2", "state": "Failed", "aired_at": null, "air_date": "2024-01-12"}
+			]}
+		]
+	}`), &show)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := seasonTree(&show)
+	if len(tree) != 1 || len(tree[0].Episodes) != 2 {
+		t.Fatalf("unexpected tree %+v", tree)
+	}
+	if completed, total := seasonProgress(&tree[0]); completed != 1 || total != 2 {
+		t.Errorf("expected 1/2 completed, got %d/%d", completed, total)
+	}
+	if ep := &tree[0].Episodes[1]; ep.AiredAt == nil || ep.AiredAt.Format("2006-01-02") != "2024-01-12" || treeLabel(ep, false) != "E02" {
+		t.Errorf("unexpected episode %+v", ep)
+	}
+
+	rows := flattenTree(tree, "show", map[string]bool{"2": true})
+	if len(rows) != 3 || !rows[0].season || rows[2].depth != 1 || rows[2].node.ID != "4" {
+		t.Errorf("unexpected rows %+v", rows)
+	}
+	if seasonTree(&models.MediaItem{Type: "movie"}) != nil {
+		t.Error("expected movies to have no tree")
+	}
+}
+
+func TestAppOpensEpisodeAndReturns(t *testing.T) {
+	fake := &fakeAPI{item: &models.MediaItem{ID: "1", Title: "Severance", Type: "show"}}
+	app := NewAppWithClient(config.DefaultConfig(), fake)
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.Update(showItemDetailMsg{itemID: "1"})
+	show := app.itemDetail
+
+	app.Update(openChildItemMsg{itemID: "3", tab: 1})
+	if app.itemDetail == show || app.itemDetail.itemID != "3" || app.itemDetail.activeTab != 1 {
+		t.Fatal("expected the episode on its Streams tab")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.currentScreen != ScreenItemDetail || app.itemDetail != show {
+		t.Fatal("expected esc to return to the show")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.currentScreen != ScreenItems {
+		t.Error("expected esc to leave the show for Items")
+	}
+}
+
+func TestAppPassesThemeToItemDetail(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.Theme = "light"
+	app := NewAppWithClient(cfg, &fakeAPI{item: &models.MediaItem{ID: "1", Title: "The Matrix", Type: "movie"}})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.Update(showItemDetailMsg{itemID: "1"})
+
+	app.itemDetail.startAction(itemActions("")[0])
+	if app.itemDetail.actionSpinner.theme != LightTheme() {
+		t.Error("expected the action spinner to use the configured theme")
+	}
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/models"
+)
+
+// treeRow is a visible row of the season tree of a show: a season or one
+// of its episodes
+type treeRow struct {
+	node   *models.MediaItem
+	season bool
+	depth  int
+}
+
+// seasonTree returns the top level of the tree of an item: the seasons of a
+// show or the episodes of a season. Other items have no tree.
+func seasonTree(item *models.MediaItem) []models.MediaItem {
+	switch item.Type {
+	case "show":
+		return item.Seasons
+	case "season":
+		return item.Episodes
+	}
+	return nil
+}
+
+// seasonProgress counts the completed episodes of a season
+func seasonProgress(season *models.MediaItem) (completed, total int) {
+	for _, ep := range season.Episodes {
+		if ep.State == models.StateCompleted {
+			completed++
+		}
+	}
+	return completed, len(season.Episodes)
+}
+
+// treeLabel names a season or an episode
+func treeLabel(node *models.MediaItem, season bool) string {
+	if season {
+		if node.Number == 0 {
+			return "Specials"
+		}
+		return fmt.Sprintf("Season %d", node.Number)
+	}
+	label := fmt.Sprintf("E%02d", node.Number)
+	if node.Title != "" {
+		label += " " + node.Title
+	}
+	return label
+}
+
+// flattenTree returns the visible rows of the tree. A season item lists
+// its episodes without a season level.
+func flattenTree(nodes []models.MediaItem, itemType string, expanded map[string]bool) []treeRow {
+	var rows []treeRow
+	if itemType == "season" {
+		for i := range nodes {
+			rows = append(rows, treeRow{node: &nodes[i]})
+		}
+		return rows
+	}
+	for i := range nodes {
+		season := &nodes[i]
+		rows = append(rows, treeRow{node: season, season: true})
+		if !expanded[season.ID] {
+			continue
+		}
+		for j := range season.Episodes {
+			rows = append(rows, treeRow{node: &season.Episodes[j], depth: 1})
+		}
+	}
+	return rows
+}
+
+// renderTreeRow renders a row of the tree with its state badge, air date
+// and, for seasons, the completed episode count
+func renderTreeRow(row treeRow, expanded, selected bool, now time.Time, theme Theme) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	node := row.node
+
+	cursor := "  "
+	if selected {
+		cursor = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render("›") + " "
+	}
+	prefix := cursor + strings.Repeat("    ", row.depth)
+	if row.season {
+		if expanded {
+			prefix += "▾ "
+		} else {
+			prefix += "▸ "
+		}
+	} else {
+		prefix += "  "
+	}
+
+	state := string(node.State)
+	if state == "" {
+		state = string(models.StateUnknown)
+	}
+	parts := []string{
+		prefix + truncateString(treeLabel(node, row.season), 40),
+		theme.StateStyle(state).Render(state),
+	}
+	if row.season {
+		completed, total := seasonProgress(node)
+		progress := fmt.Sprintf("%d/%d Completed", completed, total)
+		if total > 0 && completed == total {
+			parts = append(parts, theme.StateStyle(string(models.StateCompleted)).Render(progress))
+		} else {
+			parts = append(parts, progress)
+		}
+	}
+	if node.AiredAt != nil {
+		if node.AiredAt.After(now) {
+			parts = append(parts, dim.Render("airs "+node.AiredAt.Format("2006-01-02")))
+		} else {
+			parts = append(parts, dim.Render(node.AiredAt.Format("2006-01-02")))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
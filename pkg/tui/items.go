@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -18,7 +19,7 @@ import (
 
 // ItemsModel represents the media items screen
 type ItemsModel struct {
-	client  *api.Client
+	client  api.ItemsAPI
 	ctx     context.Context
 	width   int
 	height  int
@@ -44,24 +45,57 @@ type ItemsModel struct {
 	// Auto-refresh
 	lastUpdate time.Time
 
-	// Selection and actions
-	selectedItems []string
+	// Live updates: while the event stream is up, rows are re-fetched on
+	// item events and polling is skipped
+	live       bool
+	rowFetches map[string]bool // in-flight row fetches; true if stale again
+
+	// Requests in flight. A new page cancels the fetch it supersedes, and
+	// results from superseded fetches are dropped.
+	pageFetch   fetchSlot
+	statesFetch fetchSlot
+	rowsFetch   fetchSlot // shared by the row fetches of the current page
+
+	// Selection and actions. The selection survives paging and filtering,
+	// so it maps item IDs to titles for the result summary.
+	selectedItems map[string]string
 	showActions   bool
+	selectingAll  bool
+	matchFetch    fetchSlot
+	bulkRunning   string
+	bulkResult    *bulkResultMsg
 }
 
 // ItemsMsg represents messages for the items screen
 type itemsMsg struct {
+	gen   int
 	items *models.ItemsResponse
 	err   error
 }
 
 type statesMsg struct {
+	gen    int
 	states *models.StateResponse
 	err    error
 }
 
+// matchingItemsMsg carries every item matching the current search and filter
+type matchingItemsMsg struct {
+	gen   int
+	items map[string]string
+	err   error
+}
+
+// itemRowMsg carries a re-fetched item for a row on the current page
+type itemRowMsg struct {
+	gen  int
+	id   string
+	item *models.MediaItem
+	err  error
+}
+
 // NewItemsModel creates a new items model
-func NewItemsModel(client *api.Client, ctx context.Context) *ItemsModel {
+func NewItemsModel(client api.ItemsAPI, ctx context.Context) *ItemsModel {
 	// Create search input
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Search media items..."
@@ -70,7 +104,7 @@ func NewItemsModel(client *api.Client, ctx context.Context) *ItemsModel {
 
 	// Create table
 	columns := []table.Column{
-		{Title: "ID", Width: 8},
+		{Title: "ID", Width: 10},
 		{Title: "Title", Width: 40},
 		{Title: "Type", Width: 8},
 		{Title: "State", Width: 15},
@@ -105,6 +139,9 @@ func NewItemsModel(client *api.Client, ctx context.Context) *ItemsModel {
 		currentPage: 1,
 		pageSize:    50,
 		sortOrder:   models.SortDateDesc,
+		rowFetches:  make(map[string]bool),
+
+		selectedItems: make(map[string]string),
 	}
 }
 
@@ -125,7 +162,6 @@ func (m *ItemsModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.fetchItems(),
 		m.fetchStates(),
-		m.autoRefresh(),
 	)
 }
 
@@ -136,9 +172,12 @@ func (m *ItemsModel) Update(msg tea.Msg) (*ItemsModel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case itemsMsg:
+		if !m.pageFetch.finish(msg.gen) {
+			return m, nil
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.error = fmt.Sprintf("Failed to fetch items: %v", msg.err)
+			m.error = fmt.Sprintf("Failed to fetch items: %s", describeError(msg.err))
 		} else {
 			m.items = msg.items
 			m.error = ""
@@ -146,18 +185,78 @@ func (m *ItemsModel) Update(msg tea.Msg) (*ItemsModel, tea.Cmd) {
 		}
 
 	case statesMsg:
+		if !m.statesFetch.finish(msg.gen) {
+			return m, nil
+		}
 		if msg.err != nil {
 			// States are optional, don't show error
 		} else {
 			m.states = msg.states
 		}
 
+	case itemRowMsg:
+		if !m.rowsFetch.current(msg.gen) {
+			return m, nil
+		}
+		stale := m.rowFetches[msg.id]
+		delete(m.rowFetches, msg.id)
+		if len(m.rowFetches) == 0 {
+			m.rowsFetch.finish(msg.gen)
+		}
+		switch {
+		case stale:
+			return m, m.fetchItemRow(msg.id)
+		case api.IsNotFound(msg.err):
+			return m, m.fetchItems()
+		case msg.err == nil:
+			if i := m.rowIndex(msg.id); i >= 0 {
+				m.items.Items[i] = *msg.item
+				m.updateTable()
+			}
+		}
+
+	case startBulkMsg:
+		m.bulkResult = nil
+		m.bulkRunning = fmt.Sprintf("%s %d items...", msg.action.running, len(msg.targets))
+		return m, runBulkAction(m.ctx, m.client, msg.action, msg.targets)
+
+	case matchingItemsMsg:
+		if !m.matchFetch.finish(msg.gen) {
+			return m, nil
+		}
+		m.selectingAll = false
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Failed to select matching items: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		for id, title := range msg.items {
+			m.selectedItems[id] = title
+		}
+		m.updateTable()
+		return m, toastCmd(fmt.Sprintf("Selected %d items", len(m.selectedItems)), StatusInfo)
+
+	case bulkResultMsg:
+		m.bulkRunning = ""
+		m.bulkResult = &msg
+		// Keep failed items selected so they can be acted on again
+		for _, r := range msg.results {
+			if r.err == nil {
+				delete(m.selectedItems, r.id)
+			}
+		}
+		return m, tea.Batch(
+			toastCmd(msg.summary(), msg.statusType()),
+			m.fetchItems(),
+		)
+
 	case refreshMsg:
 		m.lastUpdate = time.Now()
+		if m.live {
+			// Events keep the page current; polling is only a fallback
+			return m, nil
+		}
 		return m, tea.Batch(
 			m.fetchItems(),
 			m.fetchStates(),
-			m.autoRefresh(),
 		)
 
 	case tea.KeyMsg:
@@ -178,8 +277,43 @@ func (m *ItemsModel) Update(msg tea.Msg) (*ItemsModel, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Handle the actions panel
+		if m.showActions {
+			if action, ok := findBulkAction(msg.String()); ok {
+				m.showActions = false
+				return m, m.confirmBulkAction(action)
+			}
+			switch msg.String() {
+			case "esc", "a":
+				m.showActions = false
+				return m, nil
+			}
+		}
+
 		// Handle normal navigation
 		switch msg.String() {
+		case " ":
+			m.toggleSelection()
+			m.table.MoveDown(1)
+			return m, nil
+
+		case "A":
+			m.toggleSelectPage()
+			return m, nil
+
+		case "ctrl+a":
+			m.selectingAll = true
+			return m, m.fetchMatchingItems()
+
+		case "x":
+			m.selectedItems = make(map[string]string)
+			m.updateTable()
+			return m, nil
+
+		case "esc":
+			m.bulkResult = nil
+			return m, nil
+
 		case "/", "ctrl+f":
 			m.showSearch = true
 			m.searchInput.Focus()
@@ -259,8 +393,7 @@ func (m *ItemsModel) Update(msg tea.Msg) (*ItemsModel, tea.Cmd) {
 			if m.items != nil && len(m.items.Items) > 0 {
 				selectedRow := m.table.Cursor()
 				if selectedRow < len(m.items.Items) {
-					item := m.items.Items[selectedRow]
-					if itemID := getStringFromMap(item, "id", ""); itemID != "" {
+					if itemID := m.items.Items[selectedRow].ID; itemID != "" {
 						return m, showItemDetailCmd(itemID)
 					}
 				}
@@ -359,14 +492,34 @@ func (m *ItemsModel) renderItems() string {
 		statusInfo := strings.Join(statusParts, " | ")
 		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(statusInfo))
 
+		// Selection info
+		var selectionParts []string
+		if len(m.selectedItems) > 0 {
+			selectionParts = append(selectionParts, fmt.Sprintf("%d selected", len(m.selectedItems)))
+		}
+		if m.selectingAll {
+			selectionParts = append(selectionParts, "Selecting all matching items...")
+		}
+		if m.bulkRunning != "" {
+			selectionParts = append(selectionParts, m.bulkRunning)
+		}
+		if len(selectionParts) > 0 {
+			sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(strings.Join(selectionParts, " | ")))
+		}
+
 		// Controls info
-		controlsInfo := "Controls: [/] search [f] filter [s] sort [c] clear [n/p] page [a] actions [enter] details"
+		controlsInfo := "Controls: [/] search [f] filter [s] sort [c] clear [n/p] page [a] actions [enter] details\n" +
+			"Select: [space] toggle [A] page [ctrl+a] all matching [x] clear"
 		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(controlsInfo))
 	}
 
 	// Actions panel
 	if m.showActions {
-		actions := "Actions: [r]etry [R]eset [d]elete [p]ause [u]npause [ESC] close"
+		target := "current item"
+		if n := len(m.selectedItems); n > 0 {
+			target = fmt.Sprintf("%d selected items", n)
+		}
+		actions := fmt.Sprintf("Actions on %s: [r]etry [R]eset [d]elete [p]ause [u]npause [ESC] close", target)
 		sections = append(sections, lipgloss.NewStyle().
 			Background(lipgloss.Color("240")).
 			Foreground(lipgloss.Color("255")).
@@ -374,6 +527,11 @@ func (m *ItemsModel) renderItems() string {
 			Render(actions))
 	}
 
+	// Outcome of the last bulk action
+	if m.bulkResult != nil {
+		sections = append(sections, renderBulkResults(*m.bulkResult, m.width, 8))
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
@@ -386,26 +544,25 @@ func (m *ItemsModel) updateTable() {
 
 	var rows []table.Row
 	for _, item := range m.items.Items {
-		// Extract fields from the map
-		id := getStringFromMap(item, "id", "")
-		title := getStringFromMap(item, "title", "Unknown")
-		itemType := getStringFromMap(item, "type", "")
-		state := getStringFromMap(item, "state", "")
-		year := getStringFromMap(item, "year", "")
-		updatedAt := getStringFromMap(item, "updated_at", "")
-
-		// Format updated time
-		if updatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, updatedAt); err == nil {
-				updatedAt = t.Format("2006-01-02")
-			}
+		year := ""
+		if item.Year != nil {
+			year = strconv.Itoa(*item.Year)
+		}
+		updatedAt := ""
+		if item.UpdatedAt != nil {
+			updatedAt = item.UpdatedAt.Local().Format("2006-01-02")
+		}
+
+		marker := "  "
+		if _, ok := m.selectedItems[item.ID]; ok {
+			marker = "✓ "
 		}
 
 		rows = append(rows, table.Row{
-			id,
-			truncateString(title, 38),
-			itemType,
-			state,
+			marker + item.ID,
+			truncateString(itemTitle(item), 38),
+			item.Type,
+			string(item.State),
 			year,
 			updatedAt,
 		})
@@ -414,22 +571,156 @@ func (m *ItemsModel) updateTable() {
 	m.table.SetRows(rows)
 }
 
-// Helper functions
-func getStringFromMap(m map[string]interface{}, key, defaultValue string) string {
-	if val, ok := m[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
+// capturesInput implements inputCapturer
+func (m *ItemsModel) capturesInput() bool {
+	return m.showSearch || m.showActions
+}
+
+// currentItem returns the item under the cursor
+func (m *ItemsModel) currentItem() (models.MediaItem, bool) {
+	if m.items == nil {
+		return models.MediaItem{}, false
+	}
+	row := m.table.Cursor()
+	if row < 0 || row >= len(m.items.Items) {
+		return models.MediaItem{}, false
+	}
+	return m.items.Items[row], true
+}
+
+// toggleSelection selects or deselects the item under the cursor
+func (m *ItemsModel) toggleSelection() {
+	item, ok := m.currentItem()
+	if !ok {
+		return
+	}
+	if item.ID == "" {
+		return
+	}
+	if _, selected := m.selectedItems[item.ID]; selected {
+		delete(m.selectedItems, item.ID)
+	} else {
+		m.selectedItems[item.ID] = itemTitle(item)
+	}
+	m.updateTable()
+}
+
+// toggleSelectPage selects every item on the page, or deselects them all if
+// they are already selected
+func (m *ItemsModel) toggleSelectPage() {
+	if m.items == nil {
+		return
+	}
+	all := true
+	for _, item := range m.items.Items {
+		if _, ok := m.selectedItems[item.ID]; !ok {
+			all = false
+			break
+		}
+	}
+	for _, item := range m.items.Items {
+		if all {
+			delete(m.selectedItems, item.ID)
+		} else {
+			m.selectedItems[item.ID] = itemTitle(item)
+		}
+	}
+	m.updateTable()
+}
+
+// bulkTargets returns the selected items, or the item under the cursor when
+// nothing is selected
+func (m *ItemsModel) bulkTargets() []bulkTarget {
+	var targets []bulkTarget
+	for id, title := range m.selectedItems {
+		targets = append(targets, bulkTarget{id: id, title: title})
+	}
+	if len(targets) == 0 {
+		if item, ok := m.currentItem(); ok {
+			targets = append(targets, bulkTarget{
+				id:    item.ID,
+				title: itemTitle(item),
+			})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].id < targets[j].id
+	})
+	return targets
+}
+
+// confirmBulkAction asks for confirmation before running an action on the
+// bulk targets
+func (m *ItemsModel) confirmBulkAction(action bulkAction) tea.Cmd {
+	targets := m.bulkTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("%d selected items", len(targets))
+	if len(targets) == 1 {
+		subject = fmt.Sprintf("%q", targets[0].title)
+	}
+	message := fmt.Sprintf("%s %s?", action.name, subject)
+	if action.destructive {
+		message += "\n\nThis cannot be undone."
+	}
+
+	return confirmCmd(action.name+" items", message, func() tea.Msg {
+		return startBulkMsg{action: action, targets: targets}
+	})
+}
+
+// startBulkMsg starts a confirmed bulk action
+type startBulkMsg struct {
+	action  bulkAction
+	targets []bulkTarget
+}
+
+// handleEvent implements eventSubscriber. Only rows on the current page are
+// refreshed; other pages are fetched fresh when visited.
+func (m *ItemsModel) handleEvent(event models.Event) tea.Cmd {
+	if !isItemEvent(event) || m.rowIndex(eventItemID(event)) < 0 {
+		return nil
+	}
+	if event.Type == "item_removed" {
+		return m.fetchItems()
+	}
+	return m.fetchItemRow(eventItemID(event))
+}
+
+// streamStateChanged implements eventSubscriber
+func (m *ItemsModel) streamStateChanged(state api.StreamState) {
+	m.live = state == api.StreamConnected
+}
+
+// rowIndex returns the index of the item on the current page, or -1
+func (m *ItemsModel) rowIndex(id string) int {
+	if m.items == nil || id == "" {
+		return -1
+	}
+	for i, item := range m.items.Items {
+		if item.ID == id {
+			return i
 		}
-		return fmt.Sprintf("%v", val)
 	}
-	return defaultValue
+	return -1
 }
 
+// Helper functions
+
+// itemTitle returns the title of an item, or "Unknown" if it has none
+func itemTitle(item models.MediaItem) string {
+	return orDefault(item.Title, "Unknown")
+}
+
+// truncateString shortens s to maxLen characters, ending it with "..."
 func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
 }
 
 func min(a, b int) int {
@@ -439,41 +730,105 @@ func min(a, b int) int {
 	return b
 }
 
-// fetchItems fetches items from the API
+// fetchItems fetches items from the API. Any page fetch still in flight is
+// cancelled, so fast paging only ever shows the last page asked for.
 func (m *ItemsModel) fetchItems() tea.Cmd {
+	sortOrder := m.sortOrder
+	params := &api.ItemsParams{
+		Limit: models.IntPtr(m.pageSize),
+		Page:  models.IntPtr(m.currentPage),
+		Sort:  &sortOrder,
+	}
+
+	if m.searchQuery != "" {
+		params.Search = models.StringPtr(m.searchQuery)
+	}
+
+	if m.filterState != "" {
+		params.States = models.StringPtr(m.filterState)
+	}
+
+	// Row fetches belong to the page being replaced
+	m.rowsFetch.stop()
+	m.rowFetches = make(map[string]bool)
+
+	ctx, gen := m.pageFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		params := &api.ItemsParams{
-			Limit: models.IntPtr(m.pageSize),
-			Page:  models.IntPtr(m.currentPage),
-			Sort:  &m.sortOrder,
-		}
+		items, err := m.client.GetItems(ctx, params)
+		return itemsMsg{gen: gen, items: items, err: err}
+	})
+}
 
-		if m.searchQuery != "" {
-			params.Search = models.StringPtr(m.searchQuery)
-		}
+// fetchItemRow re-fetches a single item on the current page. Events that
+// arrive while a fetch is in flight cause one more fetch once it returns.
+func (m *ItemsModel) fetchItemRow(id string) tea.Cmd {
+	if _, inFlight := m.rowFetches[id]; inFlight {
+		m.rowFetches[id] = true
+		return nil
+	}
+	m.rowFetches[id] = false
 
-		if m.filterState != "" {
-			params.States = models.StringPtr(m.filterState)
-		}
+	ctx, gen := m.rowsFetch.join(m.ctx)
+	return tea.Cmd(func() tea.Msg {
+		item, err := m.client.GetItem(ctx, id, nil, nil)
+		return itemRowMsg{gen: gen, id: id, item: item, err: err}
+	})
+}
+
+// fetchMatchingItems fetches the IDs of every item matching the current
+// search and filter, across all pages
+func (m *ItemsModel) fetchMatchingItems() tea.Cmd {
+	sortOrder := m.sortOrder
+	search, filter := m.searchQuery, m.filterState
 
-		items, err := m.client.GetItems(m.ctx, params)
-		return itemsMsg{items: items, err: err}
+	ctx, gen := m.matchFetch.begin(m.ctx)
+	return tea.Cmd(func() tea.Msg {
+		matching := make(map[string]string)
+		for page := 1; ; page++ {
+			params := &api.ItemsParams{
+				Limit: models.IntPtr(100),
+				Page:  models.IntPtr(page),
+				Sort:  &sortOrder,
+			}
+			if search != "" {
+				params.Search = models.StringPtr(search)
+			}
+			if filter != "" {
+				params.States = models.StringPtr(filter)
+			}
+
+			items, err := m.client.GetItems(ctx, params)
+			if err != nil {
+				return matchingItemsMsg{gen: gen, err: err}
+			}
+			for _, item := range items.Items {
+				matching[item.ID] = itemTitle(item)
+			}
+			if page >= items.TotalPages {
+				return matchingItemsMsg{gen: gen, items: matching}
+			}
+		}
 	})
 }
 
 // fetchStates fetches available states from the API
 func (m *ItemsModel) fetchStates() tea.Cmd {
+	ctx, gen := m.statesFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		states, err := m.client.GetStates(m.ctx)
-		return statesMsg{states: states, err: err}
+		states, err := m.client.GetStates(ctx)
+		return statesMsg{gen: gen, states: states, err: err}
 	})
 }
 
-// autoRefresh sets up auto-refresh
-func (m *ItemsModel) autoRefresh() tea.Cmd {
-	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
-		return refreshMsg{}
-	})
+// cancelRequests cancels every request in flight. Their results are
+// dropped, so the screen must be re-initialised before it is shown again.
+func (m *ItemsModel) cancelRequests() {
+	m.pageFetch.stop()
+	m.statesFetch.stop()
+	m.rowsFetch.stop()
+	m.matchFetch.stop()
+	m.selectingAll = false
+	m.rowFetches = make(map[string]bool)
 }
 
 // showItemDetailMsg represents a message to show item details
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+func testItemsResponse() *models.ItemsResponse {
+	return &models.ItemsResponse{
+		Success: true,
+		Items: []models.MediaItem{
+			{ID: "1", Title: "The Matrix", Type: "movie", State: models.StateCompleted, Year: intPtr(1999)},
+			{ID: "2", Title: "Severance", Type: "show", State: models.StateFailed, Year: intPtr(2022)},
+		},
+		Page:       1,
+		Limit:      50,
+		TotalItems: 60,
+		TotalPages: 2,
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestItemsModelLoadsRows(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse()}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+
+	m, _ = m.Update(m.fetchItems()())
+
+	if m.loading {
+		t.Fatal("expected loading to be cleared after itemsMsg")
+	}
+	rows := m.table.Rows()
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0][1] != "The Matrix" || rows[0][4] != "1999" {
+		t.Errorf("unexpected first row: %v", rows[0])
+	}
+}
+
+func TestItemsModelPaging(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse()}
+	m := NewItemsModel(fake, context.Background())
+	m, _ = m.Update(m.fetchItems()())
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if cmd == nil {
+		t.Fatal("expected next page to trigger a fetch")
+	}
+	cmd()
+
+	last := fake.itemsCalls[len(fake.itemsCalls)-1]
+	if last.Page == nil || *last.Page != 2 {
+		t.Errorf("expected page 2 to be requested, got %v", last.Page)
+	}
+}
+
+func TestItemsModelRendersValidationErrors(t *testing.T) {
+	fake := &fakeAPI{itemsErr: &api.APIError{
+		StatusCode: 422,
+		Validation: []models.ValidationError{
+			{Loc: []interface{}{"query", "limit"}, Msg: "ensure this value is less than or equal to 100"},
+		},
+	}}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+
+	m, _ = m.Update(m.fetchItems()())
+
+	view := m.View()
+	if !strings.Contains(view, "limit: ensure this value is less than or equal to 100") {
+		t.Errorf("expected field-level validation message in view, got:\n%s", view)
+	}
+}
+
+func TestItemsModelRefreshesRowOnEvent(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse()}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+	m, _ = m.Update(m.fetchItems()())
+
+	if cmd := m.handleEvent(models.Event{Type: "item_update", Data: map[string]interface{}{"item_id": "99"}}); cmd != nil {
+		t.Error("expected events for items off the page to be ignored")
+	}
+
+	fake.item = &models.MediaItem{ID: "2", Title: "Severance", Type: "show", State: models.StateCompleted, Year: intPtr(2022)}
+	cmd := m.handleEvent(models.Event{Type: "item_update", Data: map[string]interface{}{"item_id": "2"}})
+	if cmd == nil {
+		t.Fatal("expected a row fetch for an item on the page")
+	}
+	// A second event while the fetch is in flight is coalesced
+	if again := m.handleEvent(models.Event{Type: "item_update", Data: map[string]interface{}{"item_id": "2"}}); again != nil {
+		t.Error("expected the second event to be coalesced")
+	}
+
+	m, cmd = m.Update(cmd())
+	if cmd == nil {
+		t.Fatal("expected a follow-up fetch for the coalesced event")
+	}
+	m, _ = m.Update(cmd())
+
+	if len(fake.itemCalls) != 2 || len(fake.itemsCalls) != 1 {
+		t.Errorf("expected 2 item fetches and no extra page fetch, got %d item and %d page fetches", len(fake.itemCalls), len(fake.itemsCalls))
+	}
+	if rows := m.table.Rows(); rows[1][3] != "Completed" {
+		t.Errorf("expected the row to be updated, got %v", rows[1])
+	}
+}
+
+func TestItemsModelDropsSupersededPages(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse()}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+	m, _ = m.Update(m.fetchItems()())
+
+	// Page forward and back before the first fetch returns
+	m, first := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m, second := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+	stale := first()
+	if err := fake.itemsCtxs[len(fake.itemsCtxs)-1].Err(); err != context.Canceled {
+		t.Errorf("expected the superseded fetch to be cancelled, got %v", err)
+	}
+	m, _ = m.Update(stale)
+	if !m.loading {
+		t.Error("expected the superseded result to be dropped")
+	}
+
+	m, _ = m.Update(second())
+	if m.loading {
+		t.Error("expected the current result to be applied")
+	}
+	if err := fake.itemsCtxs[len(fake.itemsCtxs)-1].Err(); err != context.Canceled {
+		t.Errorf("expected the finished fetch to release its context, got %v", err)
+	}
+}
+
+func TestItemsModelBulkRetry(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse(), actionIDs: []string{"1"}}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+	m, _ = m.Update(m.fetchItems()())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if len(m.selectedItems) != 2 {
+		t.Fatalf("expected the whole page to be selected, got %v", m.selectedItems)
+	}
+	if rows := m.table.Rows(); !strings.HasPrefix(rows[0][0], "✓") {
+		t.Errorf("expected a selection marker, got %q", rows[0][0])
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if !m.capturesInput() {
+		t.Error("expected the actions panel to capture keys")
+	}
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	confirm, ok := cmd().(confirmMsg)
+	if !ok {
+		t.Fatal("expected the action to ask for confirmation")
+	}
+	if len(fake.actionCalls) != 0 {
+		t.Fatal("expected nothing to run before confirmation")
+	}
+
+	m, cmd = m.Update(confirm.onConfirm())
+	m, _ = m.Update(cmd())
+
+	if len(fake.actionCalls) != 1 || fake.actionCalls[0] != "retry 1,2" {
+		t.Errorf("expected one batched retry, got %v", fake.actionCalls)
+	}
+	if m.bulkResult == nil {
+		t.Fatal("expected a result summary")
+	}
+	if got := m.bulkResult.summary(); got != "Retry: 1 of 2 items retried, 1 skipped" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	if !strings.Contains(m.View(), "Severance (2): not retried") {
+		t.Errorf("expected per-item results in the view, got:\n%s", m.View())
+	}
+}
+
+func TestItemsModelBulkFailureKeepsSelection(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse(), actionErr: &api.APIError{StatusCode: 500, Detail: "boom"}}
+	m := NewItemsModel(fake, context.Background())
+	m.SetSize(120, 40)
+	m, _ = m.Update(m.fetchItems()())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	target := []bulkTarget{{id: "1", title: "The Matrix"}}
+	action, _ := findBulkAction("d")
+	m, _ = m.Update(runBulkAction(context.Background(), fake, action, target)())
+
+	if _, ok := m.selectedItems["1"]; !ok {
+		t.Error("expected the failed item to stay selected")
+	}
+	if m.bulkResult.statusType() != StatusError {
+		t.Error("expected a total failure to be reported as an error")
+	}
+}
+
+func TestTruncateStringKeepsRunesWhole(t *testing.T) {
+	if got := truncateString("Shōgun", 6); got != "Shōgun" {
+		t.Errorf("expected a title that fits to stay whole, got %q", got)
+	}
+	if got := truncateString("Shōgun: The Series", 8); got != "Shōgu..." {
+		t.Errorf("expected the title cut by characters, got %q", got)
+	}
+}
+
+func TestAppActionsPanelCapturesGlobalKeys(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse(), actionIDs: []string{"1"}}
+	app := NewAppWithClient(config.DefaultConfig(), fake)
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	app.Update(app.items.fetchItems()())
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.currentScreen != ScreenItems {
+		t.Fatal("expected 'd' to remove rather than open the dashboard")
+	}
+
+	app.Update(cmd())
+	if !app.showConfirm || !strings.Contains(app.View(), "Remove \"The Matrix\"?") {
+		t.Fatalf("expected a confirmation dialog, got:\n%s", app.View())
+	}
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}); cmd != nil || app.showConfirm {
+		t.Error("expected declining to close the dialog without running anything")
+	}
+	if len(fake.actionCalls) != 0 {
+		t.Errorf("expected no action to run, got %v", fake.actionCalls)
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logLevels are Riven's log levels from least to most severe. Its custom
+// PROGRAM level sits with INFO.
+var logLevels = []string{"TRACE", "DEBUG", "INFO", "SUCCESS", "WARNING", "ERROR", "CRITICAL"}
+
+// logLevelRank orders levels, including custom ones
+var logLevelRank = map[string]int{
+	"TRACE":    0,
+	"DEBUG":    1,
+	"INFO":     2,
+	"PROGRAM":  2,
+	"SUCCESS":  3,
+	"WARNING":  4,
+	"ERROR":    5,
+	"CRITICAL": 6,
+}
+
+// logLinePattern matches Riven's log format:
+//
+//	24-10-16 02:13:54 | ❌ ERROR     | program.services.updaters.plex - message
+//
+// The level icon is optional.
+var logLinePattern = regexp.MustCompile(`^(\S+ \S+)\s+\|\s*(?:\S+\s+)?([A-Z]+)\s*\|\s*(\S+)\s+-\s?(.*)$`)
+
+// logTimeLayouts are the timestamp formats Riven has used in its log files
+var logTimeLayouts = []string{
+	"06-01-02 15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05.000",
+	"2006-01-02T15:04:05",
+}
+
+// logEntry is a parsed log line. Lines that do not match the log format,
+// such as traceback lines, continue the entry before them and share its
+// level, module and time.
+type logEntry struct {
+	seq          int // position in the log, for keeping the scroll position
+	raw          string
+	time         time.Time
+	level        string
+	module       string
+	message      string
+	continuation bool
+
+	// rendered caches the styled entry for the render generation gen
+	rendered string
+	gen      int
+}
+
+// rank returns the severity of the entry
+func (e logEntry) rank() int {
+	if rank, ok := logLevelRank[e.level]; ok {
+		return rank
+	}
+	return logLevelRank["INFO"]
+}
+
+// parseLogLines parses log lines into entries
+func parseLogLines(lines []string) []logEntry {
+	entries := make([]logEntry, 0, len(lines))
+	var prev *logEntry
+	for _, line := range lines {
+		entry := parseLogLine(line, prev)
+		entries = append(entries, entry)
+		prev = &entries[len(entries)-1]
+	}
+	return entries
+}
+
+// parseLogLine parses a log line, continuing prev if it is not a log line
+// of its own
+func parseLogLine(line string, prev *logEntry) logEntry {
+	line = strings.TrimRight(line, "\r\n")
+	if m := logLinePattern.FindStringSubmatch(line); m != nil {
+		if at, ok := parseLogTime(m[1]); ok {
+			return logEntry{raw: line, time: at, level: m[2], module: m[3], message: m[4]}
+		}
+	}
+
+	entry := logEntry{raw: line, message: line, continuation: true, level: "INFO"}
+	if prev != nil {
+		entry.time = prev.time
+		entry.level = prev.level
+		entry.module = prev.module
+	}
+	return entry
+}
+
+// parseLogTime parses a log timestamp. Riven writes them in the server's
+// zone, which is not known here, so they are read as local times and only
+// compared with each other.
+func parseLogTime(s string) (time.Time, bool) {
+	for _, layout := range logTimeLayouts {
+		if at, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compileSearch compiles a search pattern. It is case-insensitive unless
+// it contains an upper case letter.
+func compileSearch(pattern string) (*regexp.Regexp, error) {
+	if pattern == strings.ToLower(pattern) {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// logRing holds the most recent log entries in a fixed-size ring, so a
+// followed log uses bounded memory however long it runs
+type logRing struct {
+	entries []logEntry
+	start   int
+	size    int
+	next    int // sequence number of the next entry
+}
+
+// newLogRing creates a ring holding up to capacity entries
+func newLogRing(capacity int) *logRing {
+	return &logRing{entries: make([]logEntry, capacity)}
+}
+
+// push appends a line, parsed as a continuation of the last entry if it is
+// not a log line of its own. It reports whether the oldest entry was
+// dropped to make room.
+func (r *logRing) push(line string) bool {
+	var prev *logEntry
+	if r.size > 0 {
+		prev = r.at(r.size - 1)
+	}
+	entry := parseLogLine(line, prev)
+	entry.seq = r.next
+	r.next++
+
+	if r.size < len(r.entries) {
+		r.entries[(r.start+r.size)%len(r.entries)] = entry
+		r.size++
+		return false
+	}
+	r.entries[r.start] = entry
+	r.start = (r.start + 1) % len(r.entries)
+	return true
+}
+
+// at returns the i-th entry, oldest first
+func (r *logRing) at(i int) *logEntry {
+	return &r.entries[(r.start+i)%len(r.entries)]
+}
+
+// find returns the entry with a sequence number, or nil if it is not in
+// the ring
+func (r *logRing) find(seq int) *logEntry {
+	i := seq - (r.next - r.size)
+	if i < 0 || i >= r.size {
+		return nil
+	}
+	return r.at(i)
+}
+
+// len returns the number of entries
+func (r *logRing) len() int {
+	return r.size
+}
+
+// tail returns the raw lines of the newest n entries, oldest first
+func (r *logRing) tail(n int) []string {
+	n = min(n, r.size)
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = r.at(r.size - n + i).raw
+	}
+	return lines
+}
+
+// logOverlap is how many of the newest known lines anchor a fetched log
+const logOverlap = 5
+
+// unseenLines returns the lines of a fetched log that come after the
+// newest known lines. If the known lines are not in it, the server log was
+// rotated or restarted and every fetched line is new.
+func unseenLines(known, fetched []string) []string {
+	if len(known) == 0 {
+		return fetched
+	}
+	anchor := known[max(len(known)-logOverlap, 0):]
+
+	// Search from the end: the newest occurrence is the one we last saw
+	for end := len(fetched); end >= len(anchor); end-- {
+		if equalLines(fetched[end-len(anchor):end], anchor) {
+			return fetched[end:]
+		}
+	}
+	return fetched
+}
+
+// parseLogWindow parses an export time window: a duration such as 30m or
+// 2h, or a number of days such as 1d. Empty means no window.
+func parseLogWindow(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("%q is not a duration such as 30m, 2h or 1d", s)
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
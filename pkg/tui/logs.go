@@ -2,44 +2,137 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
 	"riven-tui/pkg/api"
 	"riven-tui/pkg/models"
 )
 
-// LogsModel represents the logs screen
+// LogsModel represents the logs screen: the parsed Riven log, filtered by
+// level and module and searchable with a regular expression
 type LogsModel struct {
-	client   *api.Client
-	ctx      context.Context
-	width    int
-	height   int
-	loading  bool
-	error    string
-	
-	// Data
-	logs     *models.LogsResponse
+	client  api.SystemAPI
+	ctx     context.Context
+	width   int
+	height  int
+	loading bool
+	error   string
+
+	// Data; buffer keeps the newest lines once the log is loaded
+	buffer   *logRing
+	loaded   bool
 	viewport viewport.Model
+
+	// Follow mode appends new lines from the event stream, or by polling
+	// while no log lines arrive on it: Riven streams its log separately, so
+	// a connected stream may carry none. newLines counts the lines that
+	// arrived while scrolled up.
+	following    bool
+	live         bool
+	lastLogEvent time.Time
+	pollGen      int
+	newLines     int
+
+	// Filters
+	minLevel int // index in logLevels
+	module   string
+
+	// Search; matches are indices in visible
+	search     *regexp.Regexp
+	matches    []int
+	matchIndex int
+
+	// visible are the sequence numbers of the entries that pass the
+	// filters, and lineOf the first viewport line of each of them. Sequence
+	// numbers stay valid while the buffer drops its oldest entries.
+	visible []int
+	lineOf  []int
+	wrap    bool
+
+	// renderGen invalidates the rendered entries when their styling changes
+	renderGen int
+
+	// Prompt for the search, module filter or export
+	prompt      logsPrompt
+	promptInput textinput.Model
+	promptError string
+
+	// Export asks for a time window, then a file
+	exportWindow time.Duration
+	now          func() time.Time
+
+	// uploadURL is the link of the last upload
+	uploading bool
+	uploadURL string
+
+	// Request in flight; cancelled when the screen is left
+	logsFetch fetchSlot
 }
 
+const (
+	// logBufferSize bounds the lines kept in memory
+	logBufferSize = 10000
+
+	// logPollInterval is how often a followed log is polled while no log
+	// lines arrive on the event stream
+	logPollInterval = 2 * time.Second
+)
+
+// logsPrompt is what the prompt of the logs screen edits
+type logsPrompt int
+
+const (
+	promptNone logsPrompt = iota
+	promptSearch
+	promptModule
+	promptWindow
+	promptExport
+)
+
 // NewLogsModel creates a new logs model
-func NewLogsModel(client *api.Client, ctx context.Context) *LogsModel {
+func NewLogsModel(client api.SystemAPI, ctx context.Context) *LogsModel {
 	vp := viewport.New(80, 20)
 	vp.Style = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("62")).
-		Padding(1, 2)
-	
+		Padding(0, 1)
+	vp.SetHorizontalStep(8)
+	// Letters are filters and searches here; keep paging on the page keys
+	vp.KeyMap.PageDown = key.NewBinding(key.WithKeys("pgdown", " "))
+	vp.KeyMap.PageUp = key.NewBinding(key.WithKeys("pgup"))
+	vp.KeyMap.HalfPageDown = key.NewBinding(key.WithKeys("ctrl+d"))
+	vp.KeyMap.HalfPageUp = key.NewBinding(key.WithKeys("ctrl+u"))
+	vp.KeyMap.Left = key.NewBinding(key.WithKeys("left"))
+	vp.KeyMap.Right = key.NewBinding(key.WithKeys("right"))
+
+	promptInput := textinput.New()
+	promptInput.CharLimit = 200
+
 	return &LogsModel{
-		client:   client,
-		ctx:      ctx,
-		loading:  true,
-		viewport: vp,
+		client:      client,
+		ctx:         ctx,
+		loading:     true,
+		buffer:      newLogRing(logBufferSize),
+		viewport:    vp,
+		minLevel:    1, // DEBUG; TRACE is rarely wanted
+		promptInput: promptInput,
+		now:         time.Now,
 	}
 }
 
@@ -47,56 +140,496 @@ func NewLogsModel(client *api.Client, ctx context.Context) *LogsModel {
 func (m *LogsModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height - 3 // Account for navigation bar
-	
+
 	// Update viewport size
-	m.viewport.Width = width - 8  // Account for padding and borders
-	m.viewport.Height = height - 10 // Account for title and controls
+	m.viewport.Width = width - 4    // Account for padding
+	m.viewport.Height = height - 10 // Account for title, status and controls
+	m.promptInput.Width = min(width-20, 80)
+	m.renderGen++
+	m.render(false)
 }
 
 // Init implements tea.Model
 func (m *LogsModel) Init() tea.Cmd {
+	if m.following {
+		return tea.Batch(m.fetchLogs(), m.startPolling())
+	}
 	return m.fetchLogs()
 }
 
+// capturesInput implements inputCapturer
+func (m *LogsModel) capturesInput() bool {
+	return m.prompt != promptNone
+}
+
 // Update implements tea.Model
 func (m *LogsModel) Update(msg tea.Msg) (*LogsModel, tea.Cmd) {
 	var cmd tea.Cmd
-	
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			return m, m.updatePrompt(msg)
+		}
 		switch msg.String() {
 		case "r":
-			m.loading = true
+			m.loading = !m.loaded
 			return m, m.fetchLogs()
+		case "t":
+			m.following = !m.following
+			if !m.following {
+				m.pollGen++
+				return m, nil
+			}
+			m.newLines = 0
+			m.viewport.GotoBottom()
+			return m, tea.Batch(m.fetchLogs(), m.startPolling())
+		case "f":
+			m.minLevel = (m.minLevel + 1) % len(logLevels)
+			m.render(false)
+			return m, nil
+		case "F":
+			m.minLevel = (m.minLevel + len(logLevels) - 1) % len(logLevels)
+			m.render(false)
+			return m, nil
+		case "o":
+			return m, m.openPrompt(promptModule, m.module)
+		case "/":
+			return m, m.openPrompt(promptSearch, "")
+		case "n":
+			m.nextMatch(1)
+			return m, nil
+		case "N":
+			m.nextMatch(-1)
+			return m, nil
+		case "w":
+			m.wrap = !m.wrap
+			m.viewport.SetXOffset(0)
+			m.renderGen++
+			m.render(false)
+			return m, nil
+		case "g", "home":
+			m.viewport.GotoTop()
+			return m, nil
+		case "G", "end":
+			m.viewport.GotoBottom()
+			m.newLines = 0
+			return m, nil
+		case "x":
+			if !m.loaded {
+				return m, nil
+			}
+			return m, m.openPrompt(promptWindow, "")
+		case "u":
+			if m.uploading {
+				return m, nil
+			}
+			return m, confirmCmd("Upload logs",
+				"Upload the Riven log to paste.c-net.org? Anyone with the link can read it for 180 days.",
+				func() tea.Msg { return startUploadMsg{} })
+		case "y":
+			if m.uploadURL == "" {
+				return m, toastCmd("Nothing to copy: upload the logs with u first", StatusWarning)
+			}
+			return m, copyCmd(m.uploadURL)
+		case "esc":
+			if m.search != nil || m.module != "" {
+				m.search = nil
+				m.module = ""
+				m.renderGen++
+				m.render(false)
+			}
+			return m, nil
 		}
-		
+
 	case logsMsg:
+		if !m.logsFetch.finish(msg.gen) {
+			return m, nil
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.error = fmt.Sprintf("Failed to fetch logs: %v", msg.err)
-		} else {
-			m.logs = msg.logs
-			m.error = ""
-			m.updateViewport()
+			m.error = fmt.Sprintf("Failed to fetch logs: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.error = ""
+		// Fetches are merged, so lines streamed meanwhile are not repeated
+		lines := unseenLines(m.buffer.tail(logOverlap), msg.logs.Logs)
+		if !m.loaded {
+			m.loaded = true
+			m.appendLines(lines)
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+		m.appendLines(lines)
+		return m, nil
+
+	case startUploadMsg:
+		m.uploading = true
+		return m, m.uploadLogs()
+
+	case logsUploadedMsg:
+		m.uploading = false
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Upload failed: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		m.uploadURL = msg.url
+		return m, copyCmd(msg.url)
+
+	case logsExportedMsg:
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Export failed: %s", firstLine(describeError(msg.err))), StatusError)
 		}
+		return m, toastCmd(fmt.Sprintf("Exported %d lines to %s", msg.lines, msg.path), StatusSuccess)
+
+	case clipboardMsg:
+		if msg.terminal {
+			return m, toastCmd("Sent the link to the terminal clipboard", StatusSuccess)
+		}
+		return m, toastCmd("Copied the link to the clipboard", StatusSuccess)
+
+	case logsPollMsg:
+		if msg.gen != m.pollGen || !m.following {
+			return m, nil
+		}
+		if m.streamingLogs() || m.logsFetch.busy() {
+			return m, m.poll()
+		}
+		return m, tea.Batch(m.fetchLogs(), m.poll())
 	}
-	
+
 	// Update viewport
 	m.viewport, cmd = m.viewport.Update(msg)
-	
+	if m.viewport.AtBottom() {
+		m.newLines = 0
+	}
+
 	return m, cmd
 }
 
+// handleEvent implements eventSubscriber. Log events are appended while
+// following.
+func (m *LogsModel) handleEvent(event models.Event) tea.Cmd {
+	if event.Type != "logging" {
+		return nil
+	}
+	m.lastLogEvent = m.now()
+	if !m.following || !m.loaded {
+		return nil
+	}
+	line, _ := event.Data["message"].(string)
+	if line == "" {
+		line = event.Message
+	}
+	if line != "" {
+		m.appendLines(strings.Split(strings.TrimRight(line, "\n"), "\n"))
+	}
+	return nil
+}
+
+// streamStateChanged implements eventSubscriber
+func (m *LogsModel) streamStateChanged(state api.StreamState) {
+	m.live = state == api.StreamConnected
+}
+
+// streamingLogs reports whether log lines are arriving on the event
+// stream, so polling can pause
+func (m *LogsModel) streamingLogs() bool {
+	return m.live && !m.lastLogEvent.IsZero() && m.now().Sub(m.lastLogEvent) < logPollInterval
+}
+
+// appendLines adds new lines to the buffer. The view follows them while
+// at the bottom; otherwise it stays put and counts them.
+func (m *LogsModel) appendLines(lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	atBottom := m.viewport.AtBottom()
+	added := 0
+	for _, line := range lines {
+		m.buffer.push(line)
+		if m.matchesFilters(*m.buffer.at(m.buffer.len() - 1)) {
+			added++
+		}
+	}
+	if !atBottom {
+		m.newLines += added
+	}
+	m.render(atBottom)
+}
+
+// startPolling starts a new poll loop, stopping any previous one
+func (m *LogsModel) startPolling() tea.Cmd {
+	m.pollGen++
+	return m.poll()
+}
+
+// poll schedules the next poll of the current loop
+func (m *LogsModel) poll() tea.Cmd {
+	gen := m.pollGen
+	return tea.Tick(logPollInterval, func(time.Time) tea.Msg {
+		return logsPollMsg{gen: gen}
+	})
+}
+
+// openPrompt focuses the prompt for a search or the module filter
+func (m *LogsModel) openPrompt(prompt logsPrompt, value string) tea.Cmd {
+	m.prompt = prompt
+	m.promptError = ""
+	m.promptInput.Placeholder = map[logsPrompt]string{
+		promptSearch: "regular expression, case-insensitive unless it has capitals",
+		promptModule: "module, e.g. scrapers.torrentio (empty for all)",
+		promptWindow: "time window, e.g. 30m, 2h or 1d (empty for the whole buffer)",
+		promptExport: "file to write",
+	}[prompt]
+	m.promptInput.SetValue(value)
+	m.promptInput.CursorEnd()
+	return m.promptInput.Focus()
+}
+
+// updatePrompt handles keys in the search or module prompt
+func (m *LogsModel) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.prompt = promptNone
+		m.promptError = ""
+		m.promptInput.Blur()
+		return nil
+	case "enter":
+		value := strings.TrimSpace(m.promptInput.Value())
+		switch m.prompt {
+		case promptSearch:
+			if value == "" {
+				m.search = nil
+				break
+			}
+			re, err := compileSearch(value)
+			if err != nil {
+				m.promptError = fmt.Sprintf("Invalid regular expression: %v", err)
+				return nil
+			}
+			m.search = re
+		case promptModule:
+			m.module = value
+		case promptWindow:
+			window, err := parseLogWindow(value)
+			if err != nil {
+				m.promptError = fmt.Sprintf("Invalid time window: %v", err)
+				return nil
+			}
+			m.exportWindow = window
+			return m.openPrompt(promptExport, m.now().Format("riven-20060102-150405.log"))
+		case promptExport:
+			if value == "" {
+				m.promptError = "Enter a file to write"
+				return nil
+			}
+			m.prompt = promptNone
+			m.promptError = ""
+			m.promptInput.Blur()
+			return m.exportLogs(value)
+		}
+		m.prompt = promptNone
+		m.promptError = ""
+		m.promptInput.Blur()
+		m.render(false)
+		if m.search != nil && len(m.matches) > 0 {
+			// Jump to the first match from the bottom, where the newest lines are
+			m.matchIndex = len(m.matches) - 1
+			m.showMatch()
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return cmd
+}
+
+// nextMatch moves to the next (1) or previous (-1) search match
+func (m *LogsModel) nextMatch(dir int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchIndex = (m.matchIndex + dir + len(m.matches)) % len(m.matches)
+	m.render(false)
+	m.showMatch()
+}
+
+// showMatch scrolls the current match into view, a third from the top
+func (m *LogsModel) showMatch() {
+	if m.matchIndex >= len(m.matches) {
+		return
+	}
+	line := m.lineOf[m.matches[m.matchIndex]]
+	m.viewport.SetYOffset(max(line-m.viewport.Height/3, 0))
+}
+
+// matchesFilters reports whether an entry passes the level and module
+// filters
+func (m *LogsModel) matchesFilters(e logEntry) bool {
+	if e.rank() < logLevelRank[logLevels[m.minLevel]] {
+		return false
+	}
+	return m.module == "" || strings.Contains(strings.ToLower(e.module), strings.ToLower(m.module))
+}
+
+// render rebuilds the viewport content from the buffer. The view stays
+// on the same lines, unless bottom is set.
+func (m *LogsModel) render(bottom bool) {
+	if !m.loaded {
+		return
+	}
+	anchor, delta := m.topEntry()
+
+	current := -1
+	if m.matchIndex < len(m.matches) {
+		current = m.visible[m.matches[m.matchIndex]]
+	}
+
+	m.visible = m.visible[:0]
+	m.lineOf = m.lineOf[:0]
+	m.matches = m.matches[:0]
+	m.matchIndex = 0
+
+	var lines []string
+	width := m.viewport.Width - m.viewport.Style.GetHorizontalFrameSize()
+	marker := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("▶ ")
+	for i := 0; i < m.buffer.len(); i++ {
+		e := m.buffer.at(i)
+		if !m.matchesFilters(*e) {
+			continue
+		}
+		isCurrent := false
+		if m.search != nil && m.search.MatchString(e.raw) {
+			if e.seq == current {
+				m.matchIndex = len(m.matches)
+				isCurrent = true
+			}
+			m.matches = append(m.matches, len(m.visible))
+		}
+
+		m.visible = append(m.visible, e.seq)
+		m.lineOf = append(m.lineOf, len(lines))
+
+		if e.gen != m.renderGen || e.rendered == "" {
+			e.rendered = m.renderEntry(*e)
+			if m.wrap && width > 2 {
+				e.rendered = lipgloss.NewStyle().Width(width - 2).Render(e.rendered)
+			}
+			e.gen = m.renderGen
+		}
+		for j, line := range strings.Split(e.rendered, "\n") {
+			prefix := "  "
+			if isCurrent && j == 0 {
+				prefix = marker
+			}
+			lines = append(lines, prefix+line)
+		}
+	}
+	if len(lines) == 0 {
+		lines = []string{lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("No log lines match the filters")}
+	}
+
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+	if bottom {
+		m.viewport.GotoBottom()
+		return
+	}
+	m.viewport.SetYOffset(m.lineOfSeq(anchor) + delta)
+}
+
+// topEntry returns the sequence number of the entry at the top of the
+// view, and how many of its lines are scrolled past
+func (m *LogsModel) topEntry() (int, int) {
+	if len(m.lineOf) == 0 {
+		return 0, 0
+	}
+	offset := m.viewport.YOffset
+	i := sort.Search(len(m.lineOf), func(i int) bool { return m.lineOf[i] > offset }) - 1
+	i = max(i, 0)
+	return m.visible[i], offset - m.lineOf[i]
+}
+
+// lineOfSeq returns the first line of the first visible entry at or after
+// a sequence number
+func (m *LogsModel) lineOfSeq(seq int) int {
+	i := sort.Search(len(m.visible), func(i int) bool { return m.visible[i] >= seq })
+	if i == len(m.visible) {
+		return len(m.lineOf)
+	}
+	return m.lineOf[i]
+}
+
+// logLevelColors colours the levels
+var logLevelColors = map[string]lipgloss.Color{
+	"TRACE":    lipgloss.Color("240"),
+	"DEBUG":    lipgloss.Color("244"),
+	"INFO":     lipgloss.Color("39"),
+	"PROGRAM":  lipgloss.Color("141"),
+	"SUCCESS":  lipgloss.Color("46"),
+	"WARNING":  lipgloss.Color("226"),
+	"ERROR":    lipgloss.Color("196"),
+	"CRITICAL": lipgloss.Color("201"),
+}
+
+// renderEntry renders an entry with its level coloured and search matches
+// highlighted
+func (m *LogsModel) renderEntry(e logEntry) string {
+	color, ok := logLevelColors[e.level]
+	if !ok {
+		color = logLevelColors["INFO"]
+	}
+	plain := lipgloss.NewStyle()
+	if e.rank() >= logLevelRank["ERROR"] {
+		plain = plain.Foreground(color)
+	}
+
+	if e.continuation {
+		return m.highlight(e.message, plain.Faint(true))
+	}
+	return strings.Join([]string{
+		m.highlight(e.time.Format("01-02 15:04:05"), lipgloss.NewStyle().Foreground(lipgloss.Color("243"))),
+		lipgloss.NewStyle().Foreground(color).Bold(true).Render(fmt.Sprintf("%-8s", e.level)),
+		m.highlight(e.module, lipgloss.NewStyle().Foreground(lipgloss.Color("73"))),
+		m.highlight(e.message, plain),
+	}, " ")
+}
+
+// highlight renders text with the search matches in it highlighted
+func (m *LogsModel) highlight(text string, base lipgloss.Style) string {
+	if m.search == nil {
+		return base.Render(text)
+	}
+	matches := m.search.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return base.Render(text)
+	}
+
+	match := lipgloss.NewStyle().Background(lipgloss.Color("226")).Foreground(lipgloss.Color("16"))
+	var b strings.Builder
+	last := 0
+	for _, loc := range matches {
+		if loc[0] == loc[1] {
+			continue
+		}
+		b.WriteString(base.Render(text[last:loc[0]]))
+		b.WriteString(match.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(base.Render(text[last:]))
+	return b.String()
+}
+
 // View implements tea.Model
 func (m *LogsModel) View() string {
-	if m.loading {
+	if m.loading && !m.loaded {
 		return m.renderLoading()
 	}
-	
-	if m.error != "" {
+
+	if m.error != "" && !m.loaded {
 		return m.renderError()
 	}
-	
+
 	return m.renderLogs()
 }
 
@@ -106,7 +639,7 @@ func (m *LogsModel) renderLoading() string {
 		Width(m.width).
 		Height(m.height).
 		Align(lipgloss.Center, lipgloss.Center)
-	
+
 	return style.Render("Loading logs...")
 }
 
@@ -117,70 +650,249 @@ func (m *LogsModel) renderError() string {
 		Height(m.height).
 		Align(lipgloss.Center, lipgloss.Center).
 		Foreground(lipgloss.Color("196"))
-	
+
 	return style.Render(fmt.Sprintf("Error: %s\n\nPress 'r' to refresh", m.error))
 }
 
 // renderLogs renders the logs viewer
 func (m *LogsModel) renderLogs() string {
 	var sections []string
-	
+
 	// Title
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("39")).
-		Margin(0, 0, 1, 0).
 		Render("📝 System Logs")
-	
-	sections = append(sections, title)
-	
+
+	sections = append(sections, title, m.renderStatus())
+
 	// Viewport with logs
 	sections = append(sections, m.viewport.View())
-	
-	// Controls
-	controls := "Controls: ↑/↓ scroll | r refresh | j/k scroll line by line"
-	controlsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Italic(true).
-		Margin(1, 0)
-	
-	sections = append(sections, controlsStyle.Render(controls))
-	
+
+	// Prompt or controls
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	switch m.prompt {
+	case promptSearch:
+		sections = append(sections, "Search: "+m.promptInput.View())
+	case promptModule:
+		sections = append(sections, "Module: "+m.promptInput.View())
+	case promptWindow:
+		sections = append(sections, "Export the last: "+m.promptInput.View())
+	case promptExport:
+		sections = append(sections, "Export to: "+m.promptInput.View())
+	default:
+		controls := "Controls: ↑/↓ pgup/pgdn scroll | g/G top/bottom | t follow | f/F level | o module | / search | n/N next/prev | w wrap | x export | u upload | esc clear | r refresh"
+		if !m.wrap {
+			controls += " | ←/→ pan"
+		}
+		sections = append(sections, dim.Italic(true).Render(controls))
+	}
+	if m.promptError != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.promptError))
+	} else if m.uploading {
+		sections = append(sections, dim.Render("Uploading logs..."))
+	} else if m.uploadURL != "" {
+		sections = append(sections, dim.Render("Uploaded: ")+
+			lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Underline(true).Render(m.uploadURL)+
+			dim.Render("  (y copy)"))
+	} else if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
+
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	
+
 	style := lipgloss.NewStyle().
 		Width(m.width).
 		Height(m.height).
 		Padding(1, 2)
-	
+
 	return style.Render(content)
 }
 
-// updateViewport updates the viewport with current logs
-func (m *LogsModel) updateViewport() {
-	if m.logs == nil {
-		return
+// renderStatus summarises the filters and the search
+func (m *LogsModel) renderStatus() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	level := logLevels[m.minLevel]
+	parts := []string{
+		fmt.Sprintf("%d of %d lines", len(m.visible), m.buffer.len()),
+		"level ≥ " + lipgloss.NewStyle().Foreground(logLevelColors[level]).Render(level),
+	}
+	if m.module != "" {
+		parts = append(parts, fmt.Sprintf("module ~ %q", m.module))
+	}
+	if m.search != nil {
+		if len(m.matches) == 0 {
+			parts = append(parts, fmt.Sprintf("/%s: no matches", strings.TrimPrefix(m.search.String(), "(?i)")))
+		} else {
+			parts = append(parts, fmt.Sprintf("/%s: match %d of %d", strings.TrimPrefix(m.search.String(), "(?i)"), m.matchIndex+1, len(m.matches)))
+		}
+	}
+	if m.wrap {
+		parts = append(parts, "wrap")
+	}
+	status := dim.Render(strings.Join(parts, " · "))
+
+	if m.following {
+		source := "polling"
+		if m.streamingLogs() {
+			source = "live"
+		}
+		status += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render("● following ("+source+")")
+	}
+	if m.newLines > 0 {
+		status += "  " + lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).
+			Render(fmt.Sprintf(" %d new lines ↓ G ", m.newLines))
 	}
-	
-	// Join all log lines
-	content := strings.Join(m.logs.Logs, "\n")
-	
-	m.viewport.SetContent(content)
-	
-	// Scroll to bottom to show latest logs
-	m.viewport.GotoBottom()
+	return status
 }
 
 // Message types
 type logsMsg struct {
+	gen  int
 	logs *models.LogsResponse
 	err  error
 }
 
+// logsPollMsg polls a followed log
+type logsPollMsg struct {
+	gen int
+}
+
+// startUploadMsg starts a confirmed upload
+type startUploadMsg struct{}
+
+// logsUploadedMsg reports the link of an upload
+type logsUploadedMsg struct {
+	url string
+	err error
+}
+
+// logsExportedMsg reports a local export
+type logsExportedMsg struct {
+	path  string
+	lines int
+	err   error
+}
+
+// clipboardMsg reports a copy to the clipboard
+type clipboardMsg struct {
+	text     string
+	terminal bool // copied with an escape sequence, which cannot be confirmed
+}
+
 // fetchLogs fetches system logs
 func (m *LogsModel) fetchLogs() tea.Cmd {
+	ctx, gen := m.logsFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		logs, err := m.client.GetLogs(m.ctx)
-		return logsMsg{logs: logs, err: err}
+		logs, err := m.client.GetLogs(ctx)
+		return logsMsg{gen: gen, logs: logs, err: err}
 	})
 }
+
+// uploadLogs has the server upload its log file and return a link. The
+// upload runs on the server, so it covers the whole log file rather than
+// the lines on screen.
+func (m *LogsModel) uploadLogs() tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		resp, err := m.client.UploadLogs(ctx)
+		if err == nil && (!resp.Success || resp.URL == "") {
+			err = fmt.Errorf("the server did not return a link")
+		}
+		if err != nil {
+			return logsUploadedMsg{err: err}
+		}
+		return logsUploadedMsg{url: resp.URL}
+	}
+}
+
+// exportLogs writes the filtered lines within the export window to a file,
+// as they appear in the Riven log. The window ends at the newest line rather
+// than the local clock: Riven writes its timestamps in the server's zone,
+// which is often not the zone of this machine.
+func (m *LogsModel) exportLogs(path string) tea.Cmd {
+	var since time.Time
+	if newest := m.newestTime(); m.exportWindow > 0 && !newest.IsZero() {
+		since = newest.Add(-m.exportWindow)
+	}
+	var lines []string
+	for _, seq := range m.visible {
+		e := m.buffer.find(seq)
+		if e == nil || e.time.Before(since) {
+			continue
+		}
+		lines = append(lines, e.raw)
+	}
+	return func() tea.Msg {
+		if len(lines) == 0 {
+			return logsExportedMsg{path: path, err: fmt.Errorf("no lines in the time window")}
+		}
+		path, err := expandHome(path)
+		if err != nil {
+			return logsExportedMsg{path: path, err: err}
+		}
+		return logsExportedMsg{path: path, lines: len(lines), err: writeNewFile(path, strings.Join(lines, "\n")+"\n")}
+	}
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, fmt.Errorf("failed to expand %s: %w", path, err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// writeNewFile writes data to a file that must not exist yet, so an export
+// never replaces an earlier one or anything else at the path. Logs can carry
+// tokens and paths, so the file is kept private.
+func writeNewFile(path, data string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists; choose another name", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := f.WriteString(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// newestTime returns the timestamp of the newest buffered line, or the zero
+// time if none has one
+func (m *LogsModel) newestTime() time.Time {
+	for i := m.buffer.len() - 1; i >= 0; i-- {
+		if at := m.buffer.at(i).time; !at.IsZero() {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// copyCmd copies text to the system clipboard, falling back to the
+// terminal's clipboard (OSC 52) where there is no clipboard tool, such as
+// over SSH
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			termenv.Copy(text)
+			return clipboardMsg{text: text, terminal: true}
+		}
+		return clipboardMsg{text: text}
+	}
+}
+
+// cancelRequests cancels the request in flight and drops its result
+func (m *LogsModel) cancelRequests() {
+	m.logsFetch.stop()
+}
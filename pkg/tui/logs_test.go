@@ -0,0 +1,379 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+var testLogLines = []string{
+	"24-10-16 02:00:00 | 📰 INFO      | program.program - Riven is running",
+	"24-10-16 02:00:05 | 🤖 DEBUG     | program.services.scrapers.torrentio - Scraping tt0133093",
+	"24-10-16 02:00:07 | ❌ ERROR     | program.services.scrapers.torrentio - Torrentio timed out after 30s",
+	"Traceback (most recent call last):",
+	"  File \"torrentio.py\", line 42, in scrape",
+	"24-10-16 02:00:09 | ⚠️ WARNING   | program.services.scrapers.jackett - Jackett is not configured",
+	"24-10-16 02:00:10 | ✔️ SUCCESS   | program.services.downloaders.realdebrid - Downloaded The Matrix",
+}
+
+func TestParseLogLines(t *testing.T) {
+	entries := parseLogLines(testLogLines)
+
+	e := entries[2]
+	if e.level != "ERROR" || e.module != "program.services.scrapers.torrentio" || e.message != "Torrentio timed out after 30s" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if got := e.time.Format("2006-01-02 15:04:05"); got != "2024-10-16 02:00:07" {
+		t.Errorf("unexpected time %s", got)
+	}
+
+	// Traceback lines belong to the error before them
+	if tb := entries[4]; !tb.continuation || tb.level != "ERROR" || tb.module != e.module {
+		t.Errorf("expected a continuation of the error, got %+v", tb)
+	}
+
+	// Icons with variation selectors do not hide the level
+	if entries[5].level != "WARNING" || entries[6].level != "SUCCESS" {
+		t.Errorf("unexpected levels %q, %q", entries[5].level, entries[6].level)
+	}
+}
+
+func newTestLogs(t *testing.T) *LogsModel {
+	t.Helper()
+	m := NewLogsModel(nil, context.Background())
+	m.SetSize(160, 40)
+	_, gen := m.logsFetch.begin(context.Background())
+	m, _ = m.Update(logsMsg{gen: gen, logs: &models.LogsResponse{Logs: testLogLines}})
+	return m
+}
+
+func TestLogsFilters(t *testing.T) {
+	m := newTestLogs(t)
+	if len(m.visible) != len(testLogLines) {
+		t.Fatalf("expected every line from DEBUG up, got %d", len(m.visible))
+	}
+
+	// WARNING and up keeps the error with its traceback
+	m.minLevel = 4
+	m.render(false)
+	if len(m.visible) != 4 {
+		t.Errorf("expected 4 lines from WARNING up, got %d", len(m.visible))
+	}
+	m.minLevel = 1
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if !m.capturesInput() {
+		t.Fatal("expected the module prompt to capture input")
+	}
+	m.promptInput.SetValue("scrapers")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.visible) != 5 {
+		t.Errorf("expected the 5 scraper lines, got %d", len(m.visible))
+	}
+}
+
+func TestLogsSearch(t *testing.T) {
+	m := newTestLogs(t)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.promptInput.SetValue("timed out|not CONF")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.matches) != 1 {
+		t.Fatalf("expected capitals to make the search case-sensitive, got %d matches", len(m.matches))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.promptInput.SetValue(`torrentio\.py|jackett`)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.matches) != 2 || m.matchIndex != 1 {
+		t.Fatalf("expected 2 matches starting from the newest, got %d at %d", len(m.matches), m.matchIndex)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.matchIndex != 0 {
+		t.Errorf("expected n to wrap around to the first match, got %d", m.matchIndex)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m.matchIndex != 1 {
+		t.Errorf("expected N to go back, got %d", m.matchIndex)
+	}
+	if view := m.View(); !strings.Contains(view, "match 2 of 2") {
+		t.Errorf("expected the match position in the view:\n%s", view)
+	}
+
+	// Invalid expressions are reported and keep the prompt open
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.promptInput.SetValue("(")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.capturesInput() || !strings.Contains(m.promptError, "Invalid regular expression") {
+		t.Errorf("expected an error for an invalid expression, got %q", m.promptError)
+	}
+}
+
+func TestLogsWrap(t *testing.T) {
+	m := newTestLogs(t)
+	m.SetSize(50, 40)
+	lines := m.viewport.TotalLineCount()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if !m.wrap || m.viewport.TotalLineCount() <= lines {
+		t.Errorf("expected wrapping to add lines, got %d then %d", lines, m.viewport.TotalLineCount())
+	}
+}
+
+func TestE2ELogsParseDemoLog(t *testing.T) {
+	m := NewLogsModel(newDemoClient(t), context.Background())
+	m.SetSize(160, 40)
+	m, _ = m.Update(m.Init()())
+
+	if m.buffer.len() == 0 {
+		t.Fatalf("expected the demo log, got error %q", m.error)
+	}
+	for i := 0; i < m.buffer.len(); i++ {
+		if e := m.buffer.at(i); e.continuation {
+			t.Errorf("expected every demo line to parse, got %q", e.raw)
+		}
+	}
+}
+
+func TestLogRing(t *testing.T) {
+	r := newLogRing(3)
+	for i, line := range testLogLines[:5] {
+		if dropped := r.push(line); dropped != (i >= 3) {
+			t.Errorf("push %d: expected dropped=%v", i, i >= 3)
+		}
+	}
+	if r.len() != 3 || r.at(0).seq != 2 || r.at(2).seq != 4 {
+		t.Fatalf("expected the newest 3 entries, got %d from seq %d", r.len(), r.at(0).seq)
+	}
+	// Continuations still follow the entry before them across the wrap
+	if e := r.at(2); !e.continuation || e.level != "ERROR" {
+		t.Errorf("expected a continuation of the error, got %+v", e)
+	}
+	if tail := r.tail(2); len(tail) != 2 || tail[1] != testLogLines[4] {
+		t.Errorf("unexpected tail %q", tail)
+	}
+}
+
+func TestUnseenLines(t *testing.T) {
+	known := testLogLines[:4]
+	if got := unseenLines(known, testLogLines); !equalLines(got, testLogLines[4:]) {
+		t.Errorf("expected the lines after the known ones, got %q", got)
+	}
+	if got := unseenLines(testLogLines, testLogLines); len(got) != 0 {
+		t.Errorf("expected nothing new, got %q", got)
+	}
+	// A rotated log shares nothing with what was seen
+	rotated := []string{"24-10-17 00:00:00 | 📰 INFO      | program.program - Riven is running"}
+	if got := unseenLines(known, rotated); !equalLines(got, rotated) {
+		t.Errorf("expected every line of a rotated log, got %q", got)
+	}
+}
+
+func TestLogsFollow(t *testing.T) {
+	m := NewLogsModel(nil, context.Background())
+	m.SetSize(160, 20)
+	_, gen := m.logsFetch.begin(context.Background())
+	var lines []string
+	for i := 0; i < 40; i++ {
+		lines = append(lines, testLogLines[i%len(testLogLines)])
+	}
+	m, _ = m.Update(logsMsg{gen: gen, logs: &models.LogsResponse{Logs: lines}})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m.streamStateChanged(api.StreamConnected)
+	if !m.following || !m.viewport.AtBottom() {
+		t.Fatal("expected follow mode at the bottom")
+	}
+
+	event := func(line string) models.Event {
+		return models.Event{Type: "logging", Data: map[string]interface{}{"message": line}}
+	}
+
+	// At the bottom new lines scroll into view
+	m.handleEvent(event(testLogLines[0]))
+	if !m.viewport.AtBottom() || m.newLines != 0 {
+		t.Errorf("expected to stay at the bottom, got %d new lines", m.newLines)
+	}
+
+	// Scrolled up the view stays put and counts them
+	m.viewport.GotoTop()
+	m.viewport.LineDown(2)
+	top := m.viewport.YOffset
+	m.handleEvent(event(testLogLines[1]))
+	m.handleEvent(event(testLogLines[2] + "\n" + testLogLines[3]))
+	if m.viewport.YOffset != top || m.newLines != 3 {
+		t.Errorf("expected 3 new lines at offset %d, got %d at %d", top, m.newLines, m.viewport.YOffset)
+	}
+	if view := m.View(); !strings.Contains(view, "3 new lines") {
+		t.Errorf("expected the new lines indicator:\n%s", view)
+	}
+
+	// A poll that overlaps the streamed lines adds nothing twice
+	n := m.buffer.len()
+	_, gen = m.logsFetch.begin(context.Background())
+	m, _ = m.Update(logsMsg{gen: gen, logs: &models.LogsResponse{Logs: m.buffer.tail(10)}})
+	if m.buffer.len() != n {
+		t.Errorf("expected no duplicates, got %d lines instead of %d", m.buffer.len(), n)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if m.newLines != 0 {
+		t.Errorf("expected G to clear the indicator, got %d", m.newLines)
+	}
+
+	// Nothing is appended once follow mode is off
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m.handleEvent(event(testLogLines[0]))
+	if m.buffer.len() != n {
+		t.Errorf("expected no lines outside follow mode, got %d", m.buffer.len())
+	}
+}
+
+func TestLogsFollowPollsWithoutLogEvents(t *testing.T) {
+	fake := &fakeAPI{logs: &models.LogsResponse{Logs: testLogLines[:2]}}
+	m := NewLogsModel(fake, context.Background())
+	m.SetSize(160, 20)
+	now := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m, _ = m.Update(m.fetchLogs()())
+
+	// fetched runs the fetch of a batch, leaving its poll tick alone
+	fetched := func(cmd tea.Cmd) tea.Msg {
+		return cmd().(tea.BatchMsg)[0]()
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m, _ = m.Update(fetched(cmd))
+
+	// The stream is up but carries no log lines, so the log is polled
+	m.streamStateChanged(api.StreamConnected)
+	fake.logs = &models.LogsResponse{Logs: testLogLines[:3]}
+	_, cmd = m.Update(logsPollMsg{gen: m.pollGen})
+	if !m.logsFetch.busy() {
+		t.Fatal("expected a poll while no log lines stream in")
+	}
+	m, _ = m.Update(fetched(cmd))
+	if fake.logsCalls != 3 || m.buffer.len() != 3 {
+		t.Fatalf("expected the poll to append the new line, got %d calls and %d lines", fake.logsCalls, m.buffer.len())
+	}
+	if !strings.Contains(m.View(), "following (polling)") {
+		t.Errorf("expected the log to be polled, got:\n%s", m.View())
+	}
+
+	// Once log lines stream in, polling pauses until they stop
+	m.handleEvent(models.Event{Type: "logging", Data: map[string]interface{}{"message": testLogLines[3]}})
+	m.Update(logsPollMsg{gen: m.pollGen})
+	if m.logsFetch.busy() || m.buffer.len() != 4 {
+		t.Errorf("expected the streamed line and no poll, got %d lines", m.buffer.len())
+	}
+	now = now.Add(logPollInterval)
+	m.Update(logsPollMsg{gen: m.pollGen})
+	if !m.logsFetch.busy() {
+		t.Error("expected polling to resume once log lines stop")
+	}
+}
+
+func TestLogsExport(t *testing.T) {
+	m := newTestLogs(t)
+	// The window follows the log, not a clock in another zone
+	m.now = func() time.Time { return m.buffer.at(m.buffer.len() - 1).time.Add(5 * time.Hour) }
+	m.minLevel = 4
+	m.render(false)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m.promptInput.SetValue("1 week")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.promptError, "Invalid time window") {
+		t.Fatalf("expected an invalid window, got %q", m.promptError)
+	}
+
+	// The last 2 minutes from WARNING up: the error with its traceback and
+	// the warning
+	m.promptInput.SetValue("2m")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompt != promptExport || !strings.HasPrefix(m.promptInput.Value(), "riven-") {
+		t.Fatalf("expected the file prompt with a default name, got %q", m.promptInput.Value())
+	}
+	path := filepath.Join(t.TempDir(), "riven.log")
+	m.promptInput.SetValue(path)
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmd().(logsExportedMsg)
+	if msg.err != nil || msg.lines != 4 {
+		t.Fatalf("expected 4 lines exported, got %d (%v)", msg.lines, msg.err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(testLogLines[2:6], "\n") + "\n"; string(data) != want {
+		t.Errorf("unexpected export:\n%s", data)
+	}
+
+	// 2s before the newest line, the success, leaves the warning
+	m.exportWindow = 2 * time.Second
+	path = filepath.Join(t.TempDir(), "riven.log")
+	if msg := m.exportLogs(path)().(logsExportedMsg); msg.err != nil || msg.lines != 1 {
+		t.Errorf("expected the warning alone, got %d lines (%v)", msg.lines, msg.err)
+	}
+
+	m.exportWindow = time.Second / 2
+	if msg := m.exportLogs(path)().(logsExportedMsg); msg.err == nil {
+		t.Error("expected an empty window to fail")
+	}
+}
+
+func TestLogsExportExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	m := newTestLogs(t)
+
+	msg := m.exportLogs("~/riven.log")().(logsExportedMsg)
+	path := filepath.Join(home, "riven.log")
+	if msg.err != nil || msg.path != path {
+		t.Fatalf("expected an export to %s, got %s (%v)", path, msg.path, msg.err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLogsExportKeepsExistingFile(t *testing.T) {
+	m := newTestLogs(t)
+	path := filepath.Join(t.TempDir(), "riven.log")
+	if err := os.WriteFile(path, []byte("keep me\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := m.exportLogs(path)().(logsExportedMsg)
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "already exists") {
+		t.Errorf("expected the export to refuse an existing file, got %v", msg.err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "keep me\n" {
+		t.Errorf("existing file was changed:\n%s", data)
+	}
+}
+
+func TestE2ELogsUpload(t *testing.T) {
+	m := NewLogsModel(newDemoClient(t), context.Background())
+	m.SetSize(160, 40)
+	m, _ = m.Update(m.Init()())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	m, cmd = m.Update(cmd().(confirmMsg).onConfirm())
+	if !m.uploading {
+		t.Fatal("expected the upload to start")
+	}
+	m, _ = m.Update(cmd())
+	if m.uploading || !strings.HasPrefix(m.uploadURL, "https://paste.c-net.org/") {
+		t.Fatalf("expected a paste link, got %q", m.uploadURL)
+	}
+	if view := m.View(); !strings.Contains(view, m.uploadURL) {
+		t.Errorf("expected the link in the view:\n%s", view)
+	}
+}
@@ -0,0 +1,679 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// mountAPI is what the mount screen needs: the mount, and the library to
+// link its files to
+type mountAPI interface {
+	api.SystemAPI
+	api.ItemsAPI
+}
+
+// mountView is the list the mount screen shows
+type mountView int
+
+const (
+	mountViewTree    mountView = iota
+	mountViewSearch            // files matching the search
+	mountViewMissing           // Completed items without a file
+)
+
+// mountResult is a file matching the search
+type mountResult struct {
+	node      *mountNode
+	score     int
+	positions []int
+}
+
+// MountModel represents the mount screen: the files of the Riven VFS mount
+// as a directory tree, linked to the items they belong to
+type MountModel struct {
+	client  mountAPI
+	ctx     context.Context
+	width   int
+	height  int
+	loading bool
+	loaded  bool
+	error   string
+	theme   Theme
+
+	root     *mountNode
+	files    int
+	expanded map[string]bool
+	rows     []mountRow
+
+	// The library, to link files to items and find Completed items
+	// without a file
+	index        *libraryIndex
+	libraryError string
+	owners       map[string]libraryEntry // by file path
+	missing      []libraryEntry
+
+	view   mountView
+	cursor int
+	offset int
+
+	// Fuzzy search over the paths
+	searching   bool
+	searchInput textinput.Model
+	query       string
+	results     []mountResult
+
+	// Requests in flight; cancelled when the screen is left
+	mountFetch   fetchSlot
+	libraryFetch fetchSlot
+}
+
+// NewMountModel creates a new mount model
+func NewMountModel(client mountAPI, ctx context.Context) *MountModel {
+	searchInput := textinput.New()
+	searchInput.Placeholder = "fuzzy search, e.g. sev s01e03"
+	searchInput.CharLimit = 200
+
+	return &MountModel{
+		client:      client,
+		ctx:         ctx,
+		loading:     true,
+		theme:       DefaultTheme(),
+		expanded:    make(map[string]bool),
+		searchInput: searchInput,
+	}
+}
+
+// SetSize sets the size of the mount screen
+func (m *MountModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height - 3 // Account for navigation bar
+	m.searchInput.Width = min(width-20, 80)
+	m.clamp()
+}
+
+// SetTheme sets the theme of the state badges
+func (m *MountModel) SetTheme(theme Theme) {
+	m.theme = theme
+}
+
+// Init implements tea.Model. The library is fetched on the first visit
+// only; it takes a request per page, so later visits reuse it until `r`.
+func (m *MountModel) Init() tea.Cmd {
+	if m.index != nil {
+		return m.fetchMount()
+	}
+	return tea.Batch(m.fetchMount(), m.fetchLibrary())
+}
+
+// capturesInput implements inputCapturer
+func (m *MountModel) capturesInput() bool {
+	return m.searching
+}
+
+// Update implements tea.Model
+func (m *MountModel) Update(msg tea.Msg) (*MountModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.searching {
+			return m, m.updateSearch(msg)
+		}
+		return m, m.updateKeys(msg)
+
+	case mountMsg:
+		if !m.mountFetch.finish(msg.gen) {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to fetch the mount: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.error = ""
+		first := !m.loaded
+		m.loaded = true
+		m.root = buildMountTree(msg.mount.Files)
+		m.files = m.root.files
+		if first {
+			// Open the top level, such as movies and shows
+			for _, child := range m.root.children {
+				m.expanded[child.path] = child.dir
+			}
+		}
+		m.link()
+		m.refresh()
+
+	case libraryMsg:
+		if !m.libraryFetch.finish(msg.gen) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.libraryError = fmt.Sprintf("Files cannot be linked to items: %s", firstLine(describeError(msg.err)))
+			return m, nil
+		}
+		m.libraryError = ""
+		m.index = newLibraryIndex(msg.items)
+		m.link()
+		m.refresh()
+	}
+
+	return m, nil
+}
+
+// updateKeys handles keys while the search prompt is closed
+func (m *MountModel) updateKeys(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "r":
+		m.loading = !m.loaded
+		return tea.Batch(m.fetchMount(), m.fetchLibrary())
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.listHeight())
+	case "pgdown":
+		m.moveCursor(m.listHeight())
+	case "g", "home":
+		m.moveCursor(-m.listLen())
+	case "G", "end":
+		m.moveCursor(m.listLen())
+	case "right":
+		if node := m.selectedNode(); node != nil && node.dir && m.view == mountViewTree {
+			m.expanded[node.path] = true
+			m.refresh()
+		}
+	case "left":
+		m.collapse()
+	case "enter", " ":
+		if node := m.selectedNode(); node != nil && node.dir {
+			m.expanded[node.path] = !m.expanded[node.path]
+			m.refresh()
+			return nil
+		}
+		return m.openSelected()
+	case "o":
+		return m.openSelected()
+	case "/":
+		m.searching = true
+		m.searchInput.SetValue(m.query)
+		m.searchInput.CursorEnd()
+		return m.searchInput.Focus()
+	case "f":
+		if m.view == mountViewMissing {
+			m.setView(mountViewTree)
+		} else {
+			m.setView(mountViewMissing)
+		}
+	case "esc":
+		if m.view != mountViewTree {
+			m.query = ""
+			m.setView(mountViewTree)
+		}
+	}
+	return nil
+}
+
+// updateSearch handles keys in the search prompt. Results follow the
+// query as it is typed.
+func (m *MountModel) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		m.query = ""
+		m.setView(mountViewTree)
+		return nil
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		if m.query == "" {
+			m.setView(mountViewTree)
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if query := strings.TrimSpace(m.searchInput.Value()); query != m.query {
+		m.query = query
+		if query == "" {
+			m.setView(mountViewTree)
+		} else {
+			m.setView(mountViewSearch)
+		}
+	}
+	return cmd
+}
+
+// link resolves every file to the item it belongs to, and finds the
+// Completed items that have no file
+func (m *MountModel) link() {
+	m.owners = make(map[string]libraryEntry)
+	m.missing = nil
+	if m.root == nil || m.index == nil {
+		return
+	}
+
+	linked := make(map[string]bool)
+	for _, file := range mountFiles(m.root) {
+		if entry, ok := m.index.resolve(file.path); ok {
+			m.owners[file.path] = entry
+			linked[entry.item.ID] = true
+		}
+	}
+	for _, entry := range m.index.completed {
+		if !linked[entry.item.ID] {
+			m.missing = append(m.missing, entry)
+		}
+	}
+	sort.Slice(m.missing, func(i, j int) bool {
+		return strings.ToLower(m.missing[i].title) < strings.ToLower(m.missing[j].title)
+	})
+}
+
+// setView shows a list, starting at its top
+func (m *MountModel) setView(view mountView) {
+	m.view = view
+	m.cursor, m.offset = 0, 0
+	m.refresh()
+}
+
+// refresh rebuilds the tree rows and search results, keeping the cursor in
+// range
+func (m *MountModel) refresh() {
+	if m.root == nil {
+		return
+	}
+	m.rows = flattenMount(m.root, m.expanded)
+
+	m.results = nil
+	if m.query != "" {
+		for _, file := range mountFiles(m.root) {
+			if score, positions, ok := fuzzyMatch(m.query, file.path); ok {
+				m.results = append(m.results, mountResult{node: file, score: score, positions: positions})
+			}
+		}
+		sort.SliceStable(m.results, func(i, j int) bool {
+			if m.results[i].score != m.results[j].score {
+				return m.results[i].score > m.results[j].score
+			}
+			return m.results[i].node.path < m.results[j].node.path
+		})
+	}
+	m.clamp()
+}
+
+// collapse closes the selected directory, or moves to the directory of
+// the selected row
+func (m *MountModel) collapse() {
+	if m.view != mountViewTree || m.cursor >= len(m.rows) {
+		return
+	}
+	row := m.rows[m.cursor]
+	if row.node.dir && m.expanded[row.node.path] {
+		m.expanded[row.node.path] = false
+		m.refresh()
+		return
+	}
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].depth < row.depth {
+			m.cursor = i
+			m.clamp()
+			return
+		}
+	}
+}
+
+// listLen returns the number of rows of the current list
+func (m *MountModel) listLen() int {
+	switch m.view {
+	case mountViewSearch:
+		return len(m.results)
+	case mountViewMissing:
+		return len(m.missing)
+	}
+	return len(m.rows)
+}
+
+// selectedNode returns the directory or file under the cursor, if any
+func (m *MountModel) selectedNode() *mountNode {
+	switch m.view {
+	case mountViewTree:
+		if m.cursor < len(m.rows) {
+			return m.rows[m.cursor].node
+		}
+	case mountViewSearch:
+		if m.cursor < len(m.results) {
+			return m.results[m.cursor].node
+		}
+	}
+	return nil
+}
+
+// selectedItem returns the item of the selected file or missing entry
+func (m *MountModel) selectedItem() (libraryEntry, bool) {
+	if m.view == mountViewMissing {
+		if m.cursor < len(m.missing) {
+			return m.missing[m.cursor], true
+		}
+		return libraryEntry{}, false
+	}
+	node := m.selectedNode()
+	if node == nil || node.dir {
+		return libraryEntry{}, false
+	}
+	entry, ok := m.owners[node.path]
+	return entry, ok
+}
+
+// openSelected opens the item of the selected file or missing entry
+func (m *MountModel) openSelected() tea.Cmd {
+	if entry, ok := m.selectedItem(); ok {
+		return showItemDetailCmd(entry.item.ID)
+	}
+	if node := m.selectedNode(); node != nil && !node.dir {
+		if m.index == nil {
+			return toastCmd("The library is not loaded yet", StatusWarning)
+		}
+		return toastCmd("No library item found for this file", StatusWarning)
+	}
+	return nil
+}
+
+// moveCursor moves the cursor by delta rows
+func (m *MountModel) moveCursor(delta int) {
+	m.cursor += delta
+	m.clamp()
+}
+
+// listHeight returns the number of rows that fit on screen
+func (m *MountModel) listHeight() int {
+	return max(m.height-8, 1) // title, status, border, details and controls
+}
+
+// clamp keeps the cursor on a row and in view
+func (m *MountModel) clamp() {
+	m.cursor = max(0, min(m.cursor, m.listLen()-1))
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = max(0, min(m.offset, m.listLen()-height))
+}
+
+// View implements tea.Model
+func (m *MountModel) View() string {
+	if m.loading && !m.loaded {
+		return m.renderLoading()
+	}
+
+	if m.error != "" && !m.loaded {
+		return m.renderError()
+	}
+
+	return m.renderMount()
+}
+
+// renderLoading renders the loading state
+func (m *MountModel) renderLoading() string {
+	style := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return style.Render("Loading mount...")
+}
+
+// renderError renders the error state
+func (m *MountModel) renderError() string {
+	style := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Foreground(lipgloss.Color("196"))
+
+	return style.Render(fmt.Sprintf("Error: %s\n\nPress 'r' to refresh", m.error))
+}
+
+// renderMount renders the mount browser
+func (m *MountModel) renderMount() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	var sections []string
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Render("🗂 VFS Mount")
+	sections = append(sections, title, m.renderStatus())
+
+	theme := m.theme
+	width := max(m.width-8, 40)
+	var lines []string
+	for i := m.offset; i < m.listLen() && i < m.offset+m.listHeight(); i++ {
+		selected := i == m.cursor
+		switch m.view {
+		case mountViewTree:
+			lines = append(lines, m.renderTreeRow(m.rows[i], selected, width, theme))
+		case mountViewSearch:
+			lines = append(lines, m.renderResult(m.results[i], selected, width, theme))
+		case mountViewMissing:
+			entry := m.missing[i]
+			lines = append(lines, mountCursor(selected)+fmt.Sprintf("%-60s", truncateString(entry.title, 60))+"  "+warn.Render("no file in the mount"))
+		}
+	}
+	if m.listLen() == 0 {
+		switch m.view {
+		case mountViewSearch:
+			lines = append(lines, dim.Render("No files match"))
+		case mountViewMissing:
+			if m.index == nil {
+				lines = append(lines, dim.Render("Loading the library..."))
+			} else {
+				lines = append(lines, dim.Render("Every Completed item has a file in the mount"))
+			}
+		default:
+			lines = append(lines, dim.Render("The mount is empty"))
+		}
+	}
+	list := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Width(max(m.width-4, 20)).
+		Height(m.listHeight()).
+		Render(strings.Join(lines, "\n"))
+	sections = append(sections, list, m.renderDetails())
+
+	if m.searching {
+		sections = append(sections, "Search: "+m.searchInput.View())
+	} else {
+		controls := "Controls: ↑/↓ select | →/← expand/collapse | enter toggle/open | o open item | / search | f missing files | esc back | r refresh"
+		sections = append(sections, dim.Italic(true).Render(controls))
+	}
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderStatus renders the file count and what linking found
+func (m *MountModel) renderStatus() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	parts := []string{fileCount(m.files)}
+	switch {
+	case m.libraryError != "":
+		parts = append(parts, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.libraryError))
+	case m.index == nil:
+		parts = append(parts, dim.Render("linking files to items..."))
+	default:
+		parts = append(parts, fmt.Sprintf("%d linked to items", len(m.owners)))
+		if len(m.missing) > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(
+				fmt.Sprintf("⚠ %d Completed items without a file (f)", len(m.missing))))
+		}
+	}
+	switch m.view {
+	case mountViewSearch:
+		parts = append(parts, fmt.Sprintf("%d matching %q", len(m.results), m.query))
+	case mountViewMissing:
+		parts = append(parts, "[missing files]")
+	}
+	return strings.Join(parts, "  ·  ")
+}
+
+// renderTreeRow renders a directory with its file count, or a file with
+// its target and the state of its item
+func (m *MountModel) renderTreeRow(row mountRow, selected bool, width int, theme Theme) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	prefix := mountCursor(selected) + strings.Repeat("  ", row.depth)
+	node := row.node
+	if node.dir {
+		marker := "▸ "
+		if m.expanded[node.path] {
+			marker = "▾ "
+		}
+		return prefix + marker + lipgloss.NewStyle().Bold(true).Render(node.name+"/") + dim.Render("  "+fileCount(node.files))
+	}
+
+	nameWidth := max(min(width/2, 60)-2*row.depth, 20)
+	line := prefix + "  " + fmt.Sprintf("%-*s", nameWidth, truncateString(node.name, nameWidth))
+	line += dim.Render("  → " + truncateString(node.target, max(width-nameWidth-2*row.depth-20, 10)))
+	return line + m.renderOwner(node, theme)
+}
+
+// renderResult renders a search result with the matched characters
+// highlighted, keeping the end of long paths
+func (m *MountModel) renderResult(result mountResult, selected bool, width int, theme Theme) string {
+	match := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	runes := []rune(result.node.path)
+	from := 0
+	if avail := width - 16; len(runes) > avail {
+		from = len(runes) - avail + 1
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	next := 0
+	for i := from; i < len(runes); i++ {
+		for next < len(result.positions) && result.positions[next] < i {
+			next++
+		}
+		if next < len(result.positions) && result.positions[next] == i {
+			b.WriteString(match.Render(string(runes[i])))
+		} else {
+			b.WriteRune(runes[i])
+		}
+	}
+	return mountCursor(selected) + b.String() + m.renderOwner(result.node, theme)
+}
+
+// renderOwner renders the state of the item a file belongs to
+func (m *MountModel) renderOwner(node *mountNode, theme Theme) string {
+	entry, ok := m.owners[node.path]
+	if !ok {
+		return ""
+	}
+	state := string(entry.item.State)
+	if state == "" {
+		state = string(models.StateUnknown)
+	}
+	return "  " + theme.StateStyle(state).Render(state)
+}
+
+// renderDetails describes the selected row in full
+func (m *MountModel) renderDetails() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	width := max(m.width-20, 20)
+
+	var target, item string
+	if m.view == mountViewMissing {
+		if entry, ok := m.selectedItem(); ok {
+			target = "Completed in Riven, but no file in the mount links to it"
+			item = fmt.Sprintf("%s (#%s)", entry.title, entry.item.ID)
+		}
+	} else if node := m.selectedNode(); node != nil && node.dir {
+		target = fmt.Sprintf("%s: %s", node.path, fileCount(node.files))
+	} else if node != nil {
+		target = "→ " + node.target
+		item = "not in the library"
+		if entry, ok := m.owners[node.path]; ok {
+			item = fmt.Sprintf("%s (#%s)", entry.title, entry.item.ID)
+		} else if m.index == nil {
+			item = "..."
+		}
+	}
+
+	lines := []string{dim.Render(truncateString(target, width))}
+	if item != "" {
+		lines = append(lines, dim.Render("Item: "+truncateString(item, width)))
+	} else {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fileCount returns "1 file" or "N files"
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// mountCursor renders the cursor column of a row
+func mountCursor(selected bool) string {
+	if selected {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render("›") + " "
+	}
+	return "  "
+}
+
+// Message types for async operations
+type mountMsg struct {
+	gen   int
+	mount *models.MountResponse
+	err   error
+}
+
+type libraryMsg struct {
+	gen   int
+	items []models.MediaItem
+	err   error
+}
+
+// fetchMount fetches the files of the mount
+func (m *MountModel) fetchMount() tea.Cmd {
+	ctx, gen := m.mountFetch.begin(m.ctx)
+	return tea.Cmd(func() tea.Msg {
+		mount, err := m.client.GetMount(ctx)
+		return mountMsg{gen: gen, mount: mount, err: err}
+	})
+}
+
+// fetchLibrary fetches the library to link the files to
+func (m *MountModel) fetchLibrary() tea.Cmd {
+	ctx, gen := m.libraryFetch.begin(m.ctx)
+	return tea.Cmd(func() tea.Msg {
+		items, err := fetchLibrary(ctx, m.client)
+		return libraryMsg{gen: gen, items: items, err: err}
+	})
+}
+
+// cancelRequests cancels every request in flight and drops their results
+func (m *MountModel) cancelRequests() {
+	m.mountFetch.stop()
+	m.libraryFetch.stop()
+}
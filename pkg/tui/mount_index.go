@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// libraryPageSize is the page size of the library fetched to resolve the
+// mount against
+const libraryPageSize = 100
+
+var (
+	// mountIDPattern matches the external IDs Riven and media servers put in
+	// folder names, such as {tmdb-603} or [tvdbid-371980]
+	mountIDPattern = regexp.MustCompile(`(?i)[{\[](tmdb|tvdb|imdb)(?:id)?-([^}\]]+)[}\]]`)
+	// mountNamePattern matches a "Title (Year)" folder name
+	mountNamePattern = regexp.MustCompile(`^(.+?) \((\d{4})\)`)
+	// mountEpisodePattern matches the season and episode of a file name
+	mountEpisodePattern = regexp.MustCompile(`(?i)s(\d{1,2})e(\d{1,3})`)
+)
+
+// libraryEntry is a movie, show or episode of the library
+type libraryEntry struct {
+	item  *models.MediaItem
+	title string // with the show and episode number for episodes
+}
+
+// episodeKey identifies an episode by its show and numbers
+type episodeKey struct {
+	showID  string
+	season  int
+	episode int
+}
+
+// libraryIndex resolves mount paths to the items of the library
+type libraryIndex struct {
+	byExternalID map[string]libraryEntry // "tmdb:603", for movies and shows
+	byName       map[string]libraryEntry // "the matrix (1999)"
+	episodes     map[episodeKey]libraryEntry
+	// completed are the Completed movies and episodes, which should have a
+	// file in the mount
+	completed []libraryEntry
+}
+
+// newLibraryIndex indexes movies and shows, with their seasons and episodes
+func newLibraryIndex(items []models.MediaItem) *libraryIndex {
+	x := &libraryIndex{
+		byExternalID: make(map[string]libraryEntry),
+		byName:       make(map[string]libraryEntry),
+		episodes:     make(map[episodeKey]libraryEntry),
+	}
+	for i := range items {
+		item := &items[i]
+		entry := libraryEntry{item: item, title: itemTitle(*item)}
+		for kind, id := range map[string]string{"tmdb": item.TMDBId, "tvdb": item.TVDBId, "imdb": item.IMDBId} {
+			if id != "" {
+				x.byExternalID[kind+":"+strings.ToLower(id)] = entry
+			}
+		}
+		if item.Year != nil {
+			x.byName[fmt.Sprintf("%s (%d)", strings.ToLower(item.Title), *item.Year)] = entry
+		}
+
+		switch item.Type {
+		case "movie":
+			if item.State == models.StateCompleted {
+				x.completed = append(x.completed, entry)
+			}
+		case "show":
+			for s := range item.Seasons {
+				season := &item.Seasons[s]
+				for e := range season.Episodes {
+					episode := &season.Episodes[e]
+					ep := libraryEntry{item: episode, title: fmt.Sprintf("%s S%02dE%02d", entry.title, season.Number, episode.Number)}
+					x.episodes[episodeKey{item.ID, season.Number, episode.Number}] = ep
+					if episode.State == models.StateCompleted {
+						x.completed = append(x.completed, ep)
+					}
+				}
+			}
+		}
+	}
+	return x
+}
+
+// resolve returns the item a mount path belongs to: the movie, the episode,
+// or the show when the episode is not known. Folders are matched by their
+// external IDs, then by title and year.
+func (x *libraryIndex) resolve(p string) (libraryEntry, bool) {
+	var owner libraryEntry
+	found := false
+	for _, segment := range strings.Split(p, "/") {
+		if owner, found = x.resolveFolder(segment); found {
+			break
+		}
+	}
+	if !found {
+		return libraryEntry{}, false
+	}
+	if owner.item.Type != "show" {
+		return owner, true
+	}
+
+	m := mountEpisodePattern.FindStringSubmatch(path.Base(p))
+	if m == nil {
+		return owner, true
+	}
+	season, _ := strconv.Atoi(m[1])
+	episode, _ := strconv.Atoi(m[2])
+	if ep, ok := x.episodes[episodeKey{owner.item.ID, season, episode}]; ok {
+		return ep, true
+	}
+	return owner, true
+}
+
+// resolveFolder matches a folder name to a movie or show
+func (x *libraryIndex) resolveFolder(name string) (libraryEntry, bool) {
+	for _, m := range mountIDPattern.FindAllStringSubmatch(name, -1) {
+		if entry, ok := x.byExternalID[strings.ToLower(m[1])+":"+strings.ToLower(m[2])]; ok {
+			return entry, true
+		}
+	}
+	if m := mountNamePattern.FindStringSubmatch(name); m != nil {
+		entry, ok := x.byName[strings.ToLower(m[1])+" ("+m[2]+")"]
+		return entry, ok
+	}
+	return libraryEntry{}, false
+}
+
+// fetchLibrary fetches every movie and show of the library, with their
+// seasons and episodes, a page at a time. A partial library would leave
+// files unlinked and hide Completed items without a file.
+func fetchLibrary(ctx context.Context, client api.ItemsAPI) ([]models.MediaItem, error) {
+	limit, kinds, extended := libraryPageSize, "movie,show", true
+	var items []models.MediaItem
+	for page := 1; ; page++ {
+		p := page
+		resp, err := client.GetItems(ctx, &api.ItemsParams{Limit: &limit, Page: &p, Type: &kinds, Extended: &extended})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Items...)
+		if page >= resp.TotalPages || len(resp.Items) == 0 {
+			break
+		}
+	}
+	return items, nil
+}
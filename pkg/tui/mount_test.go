@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+// testMount returns a mount with a movie, two episodes and a stray file
+func testMount() *models.MountResponse {
+	return &models.MountResponse{Files: map[string]string{
+		"/movies/The Matrix (1999) {tmdb-603}/The Matrix (1999).mkv":                    "/mnt/zurg/__all__/The.Matrix.1999/The.Matrix.1999.mkv",
+		"/shows/Severance (2022) {tvdb-371980}/Season 01/Severance (2022) - s01e01.mkv": "/mnt/zurg/__all__/Severance.S01E01/Severance.S01E01.mkv",
+		"/shows/Severance (2022) {tvdb-371980}/Season 01/Severance (2022) - s01e03.mkv": "/mnt/zurg/__all__/Severance.S01E03/Severance.S01E03.mkv",
+		"/movies/Inception (2010)/Inception (2010).mkv":                                 "/mnt/zurg/__all__/Inception.2010/Inception.2010.mkv",
+		"/movies/Unknown Movie (2001) {tmdb-1}/Unknown Movie (2001).mkv":                "/mnt/zurg/__all__/Unknown/Unknown.mkv",
+	}}
+}
+
+// testLibrary returns the library behind testMount: Severance S01E02 is
+// Completed but has no file
+func testLibrary() []models.MediaItem {
+	episode := func(id string, n int, state models.States) models.MediaItem {
+		return models.MediaItem{ID: id, Type: "episode", Number: n, State: state}
+	}
+	return []models.MediaItem{
+		{ID: "1", Title: "The Matrix", Type: "movie", TMDBId: "603", Year: intPtr(1999), State: models.StateCompleted},
+		{ID: "2", Title: "Inception", Type: "movie", TMDBId: "27205", Year: intPtr(2010), State: models.StateCompleted},
+		{ID: "3", Title: "Severance", Type: "show", TVDBId: "371980", Year: intPtr(2022), Seasons: []models.Season{
+			{ID: "4", Type: "season", Number: 1, Episodes: []models.Episode{
+				episode("5", 1, models.StateCompleted),
+				episode("6", 2, models.StateCompleted),
+				episode("7", 3, models.StateCompleted),
+			}},
+		}},
+	}
+}
+
+func TestBuildMountTree(t *testing.T) {
+	root := buildMountTree(testMount().Files)
+	if root.files != 5 || len(root.children) != 2 || root.children[0].name != "movies" {
+		t.Fatalf("unexpected root %+v", root)
+	}
+
+	rows := flattenMount(root, map[string]bool{"/movies": true})
+	var names []string
+	for _, row := range rows {
+		names = append(names, row.node.name)
+	}
+	want := "movies, Inception (2010), The Matrix (1999) {tmdb-603}, Unknown Movie (2001) {tmdb-1}, shows"
+	if got := strings.Join(names, ", "); got != want {
+		t.Errorf("got rows %s, want %s", got, want)
+	}
+	if rows[1].depth != 1 || !rows[1].node.dir || rows[1].node.files != 1 {
+		t.Errorf("unexpected row %+v", rows[1])
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	paths := []string{
+		"/shows/Severance (2022)/Season 01/Severance (2022) - s01e03.mkv",
+		"/shows/Severance (2022)/Season 02/Severance (2022) - s02e03.mkv",
+	}
+	first, _, ok1 := fuzzyMatch("sev s02e03", paths[0])
+	second, positions, ok2 := fuzzyMatch("sev s02e03", paths[1])
+	if !ok1 || !ok2 || second <= first {
+		t.Errorf("expected the exact episode to rank first, got %d and %d", first, second)
+	}
+	if len(positions) != 9 || string([]rune(paths[1])[positions[3]:positions[8]+1]) != "s02e03" {
+		t.Errorf("unexpected positions %v", positions)
+	}
+	if _, _, ok := fuzzyMatch("matrix", paths[0]); ok {
+		t.Error("expected no match")
+	}
+}
+
+func TestLibraryIndexResolve(t *testing.T) {
+	index := newLibraryIndex(testLibrary())
+
+	for path, want := range map[string]string{
+		"/movies/The Matrix (1999) {tmdb-603}/The Matrix (1999).mkv":                    "The Matrix",
+		"/movies/Inception (2010)/Inception (2010).mkv":                                 "Inception",
+		"/shows/Severance (2022) [tvdbid-371980]/Season 01/Severance - S01E03.mkv":      "Severance S01E03",
+		"/shows/Severance (2022) {tvdb-371980}/Season 02/Severance (2022) - s02e01.mkv": "Severance",
+	} {
+		entry, ok := index.resolve(path)
+		if !ok || entry.title != want {
+			t.Errorf("%s: got %q, want %q", path, entry.title, want)
+		}
+	}
+	if _, ok := index.resolve("/movies/Unknown Movie (2001) {tmdb-1}/Unknown Movie (2001).mkv"); ok {
+		t.Error("expected an unknown movie not to resolve")
+	}
+	if len(index.completed) != 5 {
+		t.Errorf("expected the Completed movies and episodes, got %d", len(index.completed))
+	}
+}
+
+func TestFetchLibraryReadsEveryPage(t *testing.T) {
+	// More pages than a cap would allow; the fake returns the same page
+	fake := &fakeAPI{items: &models.ItemsResponse{Success: true, Items: testLibrary()[:1], TotalPages: 150}}
+	items, err := fetchLibrary(context.Background(), fake)
+	if err != nil || len(items) != 150 || len(fake.itemsCalls) != 150 {
+		t.Fatalf("expected all 150 pages, got %d items in %d calls (%v)", len(items), len(fake.itemsCalls), err)
+	}
+	if page := fake.itemsCalls[149].Page; page == nil || *page != 150 {
+		t.Errorf("expected the last page to be 150, got %v", page)
+	}
+}
+
+func TestMountModel(t *testing.T) {
+	fake := &fakeAPI{
+		mount: testMount(),
+		items: &models.ItemsResponse{Success: true, Items: testLibrary(), Page: 1, TotalItems: 3, TotalPages: 1},
+	}
+	m := NewMountModel(fake, context.Background())
+	m.SetSize(160, 40)
+	m, _ = m.Update(m.fetchMount()())
+	m, _ = m.Update(m.fetchLibrary()())
+
+	if len(m.owners) != 4 || len(m.missing) != 1 || m.missing[0].title != "Severance S01E02" {
+		t.Fatalf("expected 4 linked files and one missing episode, got %d and %+v", len(m.owners), m.missing)
+	}
+	last := fake.itemsCalls[len(fake.itemsCalls)-1]
+	if last.Extended == nil || !*last.Extended || *last.Type != "movie,show" {
+		t.Errorf("expected the library with seasons and episodes, got %+v", last)
+	}
+
+	// The top level starts open; expand the first movie and open its item
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if node := m.selectedNode(); node == nil || node.name != "Inception (2010).mkv" {
+		t.Fatalf("expected the movie file, got %+v", node)
+	}
+	if !strings.Contains(m.View(), "→ /mnt/zurg/__all__/Inception.2010/Inception.2010.mkv") {
+		t.Errorf("expected the target of the file, got:\n%s", m.View())
+	}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if open, ok := cmd().(showItemDetailMsg); !ok || open.itemID != "2" {
+		t.Errorf("expected the movie to open, got %+v", open)
+	}
+
+	// Search
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !m.capturesInput() {
+		t.Fatal("expected the search to capture keys")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sev e03")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.results) == 0 || m.owners[m.results[0].node.path].item.ID != "7" {
+		t.Errorf("expected the third episode first, got %+v", m.results)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.view != mountViewTree || m.query != "" {
+		t.Error("expected esc to clear the search")
+	}
+
+	// Completed items without a file
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if !strings.Contains(m.View(), "Severance S01E02") {
+		t.Errorf("expected the missing episode, got:\n%s", m.View())
+	}
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if open, ok := cmd().(showItemDetailMsg); !ok || open.itemID != "6" {
+		t.Errorf("expected the missing episode to open, got %+v", open)
+	}
+}
+
+func TestMountModelKeepsLibrary(t *testing.T) {
+	fake := &fakeAPI{
+		mount: testMount(),
+		items: &models.ItemsResponse{Success: true, Items: testLibrary(), Page: 1, TotalItems: 3, TotalPages: 1},
+	}
+	m := NewMountModel(fake, context.Background())
+	m.SetSize(160, 40)
+	m, _ = m.Update(m.fetchMount()())
+	m, _ = m.Update(m.fetchLibrary()())
+	calls := len(fake.itemsCalls)
+
+	// Coming back to the screen only fetches the mount
+	if _, ok := m.Init()().(mountMsg); !ok {
+		t.Fatal("expected a revisit to fetch the mount alone")
+	}
+	if len(fake.itemsCalls) != calls {
+		t.Errorf("expected the library to be kept, got %d more calls", len(fake.itemsCalls)-calls)
+	}
+
+	// r fetches both again
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("expected the mount and the library, got %T", cmd())
+	}
+	for _, c := range batch {
+		m, _ = m.Update(c())
+	}
+	if len(fake.itemsCalls) == calls || m.index == nil {
+		t.Error("expected r to fetch the library again")
+	}
+}
+
+func TestAppPassesThemeToMount(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.Theme = "light"
+	app := NewAppWithClient(cfg, &fakeAPI{})
+	if app.mount.theme != LightTheme() {
+		t.Error("expected the mount screen to use the configured theme")
+	}
+}
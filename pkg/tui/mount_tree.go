@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// mountNode is a directory or a file of the VFS mount
+type mountNode struct {
+	name     string
+	path     string
+	dir      bool
+	target   string       // files: the path the file maps to
+	children []*mountNode // directories: subdirectories first, then files
+	files    int          // directories: the number of files below
+}
+
+// mountRow is a visible row of the mount tree
+type mountRow struct {
+	node  *mountNode
+	depth int
+}
+
+// buildMountTree builds the directory tree of the mount from its flat list
+// of file paths and their targets
+func buildMountTree(files map[string]string) *mountNode {
+	root := &mountNode{dir: true}
+	dirs := map[string]*mountNode{"": root}
+
+	for path, target := range files {
+		parts := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+		if len(parts) == 0 {
+			continue
+		}
+		parent := root
+		dirPath := ""
+		for _, part := range parts[:len(parts)-1] {
+			dirPath += "/" + part
+			dir, ok := dirs[dirPath]
+			if !ok {
+				dir = &mountNode{name: part, path: dirPath, dir: true}
+				dirs[dirPath] = dir
+				parent.children = append(parent.children, dir)
+			}
+			parent = dir
+		}
+		parent.children = append(parent.children, &mountNode{name: parts[len(parts)-1], path: path, target: target})
+	}
+
+	root.sortAndCount()
+	return root
+}
+
+// sortAndCount orders the children of a directory and counts its files
+func (n *mountNode) sortAndCount() int {
+	sort.Slice(n.children, func(i, j int) bool {
+		a, b := n.children[i], n.children[j]
+		if a.dir != b.dir {
+			return a.dir
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+	n.files = 0
+	for _, child := range n.children {
+		if child.dir {
+			n.files += child.sortAndCount()
+		} else {
+			n.files++
+		}
+	}
+	return n.files
+}
+
+// flattenMount returns the visible rows of the tree below root
+func flattenMount(root *mountNode, expanded map[string]bool) []mountRow {
+	var rows []mountRow
+	var walk func(n *mountNode, depth int)
+	walk = func(n *mountNode, depth int) {
+		for _, child := range n.children {
+			rows = append(rows, mountRow{node: child, depth: depth})
+			if child.dir && expanded[child.path] {
+				walk(child, depth+1)
+			}
+		}
+	}
+	walk(root, 0)
+	return rows
+}
+
+// mountFiles returns the files below a node
+func mountFiles(n *mountNode) []*mountNode {
+	if !n.dir {
+		return []*mountNode{n}
+	}
+	var files []*mountNode
+	for _, child := range n.children {
+		files = append(files, mountFiles(child)...)
+	}
+	return files
+}
+
+// fuzzyMatch reports whether every word of pattern matches s: its
+// characters appear in s in order, ignoring case. The score favours runs of
+// consecutive characters and matches at the start of a path segment or a
+// word; positions are the indices of the matched runes of s, in order.
+func fuzzyMatch(pattern, s string) (score int, positions []int, ok bool) {
+	// Lower case rune by rune, so positions index the runes of s
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	for _, word := range strings.Fields(strings.ToLower(pattern)) {
+		wordScore, wordPositions, ok := fuzzyMatchWord([]rune(word), runes)
+		if !ok {
+			return 0, nil, false
+		}
+		score += wordScore
+		positions = append(positions, wordPositions...)
+	}
+	sort.Ints(positions)
+	return score, positions, true
+}
+
+// fuzzyMatchWord matches a word from each place its first character
+// appears and keeps the best score
+func fuzzyMatchWord(word, runes []rune) (best int, bestPositions []int, found bool) {
+	for start, r := range runes {
+		if r != word[0] {
+			continue
+		}
+		score, positions := 0, make([]int, 0, len(word))
+		for i := start; i < len(runes) && len(positions) < len(word); i++ {
+			if runes[i] != word[len(positions)] {
+				continue
+			}
+			score++
+			if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+				score += 4
+			}
+			if i == 0 || strings.ContainsRune("/ ._-([{", runes[i-1]) {
+				score += 3
+			}
+			positions = append(positions, i)
+		}
+		if len(positions) < len(word) {
+			// Later starts cannot match either
+			break
+		}
+		// Prefer matches that are close together
+		score -= (positions[len(positions)-1] - positions[0]) / 8
+		if !found || score > best {
+			best, bestPositions, found = score, positions, true
+		}
+	}
+	return best, bestPositions, found
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshMsg asks a screen to refresh its data. It is tagged with the
+// screen it was scheduled for and the generation of that screen's timer, so
+// ticks never reach another screen and ticks from a stopped timer are dropped.
+type refreshMsg struct {
+	screen Screen
+	gen    int
+}
+
+// RefreshScheduler owns the auto-refresh timers of the screens. Each screen
+// has at most one timer; only visible screens are refreshed.
+type RefreshScheduler struct {
+	interval time.Duration
+	gens     map[Screen]int
+	running  map[Screen]bool
+}
+
+// NewRefreshScheduler creates a scheduler that refreshes every interval.
+// A zero interval disables auto-refresh.
+func NewRefreshScheduler(interval time.Duration) *RefreshScheduler {
+	return &RefreshScheduler{
+		interval: interval,
+		gens:     make(map[Screen]int),
+		running:  make(map[Screen]bool),
+	}
+}
+
+// Start (re)starts the timer of a screen, replacing any running timer
+func (s *RefreshScheduler) Start(screen Screen) tea.Cmd {
+	s.gens[screen]++
+	s.running[screen] = true
+	return s.tick(screen)
+}
+
+// Stop stops the timer of a screen. A tick already in flight is dropped.
+func (s *RefreshScheduler) Stop(screen Screen) {
+	s.gens[screen]++
+	s.running[screen] = false
+}
+
+// Accept reports whether a refresh message comes from the screen's current
+// timer. If so, the timer is re-armed with the returned command.
+func (s *RefreshScheduler) Accept(msg refreshMsg) (tea.Cmd, bool) {
+	if !s.running[msg.screen] || msg.gen != s.gens[msg.screen] {
+		return nil, false
+	}
+	return s.tick(msg.screen), true
+}
+
+// tick schedules the next refresh of a screen
+func (s *RefreshScheduler) tick(screen Screen) tea.Cmd {
+	if s.interval <= 0 {
+		return nil
+	}
+	gen := s.gens[screen]
+	return tea.Tick(s.interval, func(time.Time) tea.Msg {
+		return refreshMsg{screen: screen, gen: gen}
+	})
+}
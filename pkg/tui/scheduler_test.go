@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/config"
+)
+
+func TestRefreshSchedulerGenerations(t *testing.T) {
+	s := NewRefreshScheduler(time.Second)
+
+	if s.Start(ScreenDashboard) == nil {
+		t.Fatal("expected a tick command")
+	}
+	first := refreshMsg{screen: ScreenDashboard, gen: s.gens[ScreenDashboard]}
+
+	// Restarting replaces the running timer
+	s.Start(ScreenDashboard)
+	if _, ok := s.Accept(first); ok {
+		t.Error("tick from a replaced timer was accepted")
+	}
+
+	current := refreshMsg{screen: ScreenDashboard, gen: s.gens[ScreenDashboard]}
+	next, ok := s.Accept(current)
+	if !ok || next == nil {
+		t.Fatal("tick from the current timer was rejected")
+	}
+
+	s.Stop(ScreenDashboard)
+	if _, ok := s.Accept(current); ok {
+		t.Error("tick from a stopped timer was accepted")
+	}
+
+	if _, ok := s.Accept(refreshMsg{screen: ScreenItems}); ok {
+		t.Error("tick for a screen without a timer was accepted")
+	}
+}
+
+func TestRefreshSchedulerDisabled(t *testing.T) {
+	s := NewRefreshScheduler(0)
+	if s.Start(ScreenDashboard) != nil {
+		t.Error("expected no tick with a zero interval")
+	}
+}
+
+func TestAppRunsOneTimerPerVisibleScreen(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.RefreshInterval = time.Minute
+
+	fake := &fakeAPI{}
+	app := NewAppWithClient(cfg, fake)
+	app.scheduler.Start(ScreenDashboard)
+	oldTick := refreshMsg{screen: ScreenDashboard, gen: app.scheduler.gens[ScreenDashboard]}
+
+	// Dashboard -> Media -> Dashboard
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if app.scheduler.running[ScreenDashboard] {
+		t.Error("hidden dashboard still has a running timer")
+	}
+	if !app.scheduler.running[ScreenItems] {
+		t.Error("items screen has no timer")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.scheduler.running[ScreenItems] {
+		t.Error("hidden items screen still has a running timer")
+	}
+
+	// The tick armed before leaving the dashboard must not start a second chain
+	if _, cmd := app.Update(oldTick); cmd != nil {
+		t.Error("stale tick was not dropped")
+	}
+
+	tick := refreshMsg{screen: ScreenDashboard, gen: app.scheduler.gens[ScreenDashboard]}
+	if _, cmd := app.Update(tick); cmd == nil {
+		t.Error("current tick did not refresh the dashboard")
+	}
+}
+
+func TestAppCancelsRequestsOnLeaveAndQuit(t *testing.T) {
+	fake := &fakeAPI{items: testItemsResponse()}
+	app := NewAppWithClient(config.DefaultConfig(), fake)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if cmd == nil {
+		t.Fatal("expected the items screen to fetch")
+	}
+	fetch := app.items.fetchItems()
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+
+	fetch()
+	if err := fake.itemsCtxs[0].Err(); err != context.Canceled {
+		t.Errorf("expected leaving the screen to cancel its fetch, got %v", err)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if app.ctx.Err() == nil {
+		t.Error("expected quitting to cancel every request")
+	}
+}
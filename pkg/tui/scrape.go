@@ -0,0 +1,547 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// resolutionOrder is the order the resolution filter cycles through
+var resolutionOrder = []string{"2160p", "1440p", "1080p", "720p", "480p", "360p"}
+
+// ScrapeModel runs a scrape for an item or an external ID and browses the
+// streams it finds
+type ScrapeModel struct {
+	client api.ScrapingAPI
+	ctx    context.Context
+	width  int
+	height int
+	theme  Theme
+	now    func() time.Time
+
+	// Search form; it has focus while editing
+	idInput textinput.Model
+	movie   bool
+	editing bool
+
+	// The scrape
+	target      scrapeTarget
+	scraping    bool
+	scraped     bool // the last scrape finished
+	started     time.Time
+	spinner     LoadingComponent
+	scrapeFetch fetchSlot
+	error       string
+	message     string
+
+	// streams holds every stream found, best ranked first; rows the ones
+	// passing the filters, one per table row
+	streams []models.Stream
+	rows    []models.Stream
+	table   table.Model
+
+	// Filters
+	resolution string // "" for any
+	language   string // "" for any
+	cachedOnly bool
+	hideTrash  bool
+	hideAdult  bool
+}
+
+// scrapeResultMsg reports the outcome of a scrape
+type scrapeResultMsg struct {
+	gen  int
+	resp *models.ScrapeItemResponse
+	err  error
+}
+
+// NewScrapeModel creates a new scrape screen
+func NewScrapeModel(client api.ScrapingAPI, ctx context.Context) *ScrapeModel {
+	idInput := textinput.New()
+	idInput.Placeholder = "tt0133093, TMDB or TVDB ID"
+	idInput.CharLimit = 20
+	idInput.Width = 30
+	idInput.Focus()
+
+	columns := []table.Column{
+		{Title: "Rank", Width: 6},
+		{Title: "Title", Width: 50},
+		{Title: "Res", Width: 7},
+		{Title: "Size", Width: 9},
+		{Title: "Cached", Width: 6},
+		{Title: "Languages", Width: 10},
+		{Title: "Flags", Width: 11},
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("240")).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	return &ScrapeModel{
+		client:  client,
+		ctx:     ctx,
+		theme:   DefaultTheme(),
+		now:     time.Now,
+		idInput: idInput,
+		movie:   true,
+		editing: true,
+		table:   t,
+	}
+}
+
+// SetSize sets the size of the scrape screen
+func (m *ScrapeModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height - 3 // Account for navigation bar
+
+	m.table.SetHeight(m.height - 14) // Leave space for the form and filters
+}
+
+// SetTheme sets the theme of the scrape spinner
+func (m *ScrapeModel) SetTheme(theme Theme) {
+	m.theme = theme
+}
+
+// Init implements tea.Model
+func (m *ScrapeModel) Init() tea.Cmd {
+	if m.editing {
+		return textinput.Blink
+	}
+	return nil
+}
+
+// capturesInput implements inputCapturer
+func (m *ScrapeModel) capturesInput() bool {
+	return m.editing
+}
+
+// Update implements tea.Model
+func (m *ScrapeModel) Update(msg tea.Msg) (*ScrapeModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case scrapeResultMsg:
+		if !m.scrapeFetch.finish(msg.gen) {
+			return m, nil
+		}
+		m.scraping = false
+		m.scraped = true
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Scrape failed: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.setStreams(msg.resp.Streams)
+		m.message = fmt.Sprintf("%s in %s", msg.resp.Message, m.now().Sub(m.started).Round(100*time.Millisecond))
+		return m, nil
+
+	case spinner.TickMsg:
+		if !m.scraping {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case tea.KeyMsg:
+		if m.editing {
+			return m, m.handleFormKey(msg)
+		}
+		if m.scraping {
+			if msg.String() == "esc" {
+				m.cancelRequests()
+			}
+			return m, nil
+		}
+		if cmd, ok := m.handleResultsKey(msg); ok {
+			return m, cmd
+		}
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// handleFormKey handles keys while the search form has focus
+func (m *ScrapeModel) handleFormKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		id := strings.TrimSpace(m.idInput.Value())
+		if _, ok := parseExternalID(id, m.movie); !ok {
+			m.error = externalIDHint
+			return nil
+		}
+		itemType := "show"
+		if m.movie {
+			itemType = "movie"
+		}
+		return m.scrapeItem(scrapeTarget{title: id, itemType: itemType, externalID: id})
+	case "esc":
+		if m.target == (scrapeTarget{}) {
+			return nil
+		}
+		m.editing = false
+		m.idInput.Blur()
+		m.error = ""
+		return nil
+	case "ctrl+t":
+		m.movie = !m.movie
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.idInput, cmd = m.idInput.Update(msg)
+	return cmd
+}
+
+// handleResultsKey handles the keys of the results list. It reports false
+// for keys that move the table.
+func (m *ScrapeModel) handleResultsKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "/":
+		m.editing = true
+		return m.idInput.Focus(), true
+	case "r":
+		if m.target == (scrapeTarget{}) {
+			return nil, true
+		}
+		return m.scrapeItem(m.target), true
+	case "f":
+		m.resolution = nextValue(m.resolutions(), m.resolution)
+	case "L":
+		m.language = nextValue(m.languages(), m.language)
+	case "c":
+		m.cachedOnly = !m.cachedOnly
+	case "t":
+		m.hideTrash = !m.hideTrash
+	case "a":
+		m.hideAdult = !m.hideAdult
+	case "x":
+		m.resolution, m.language = "", ""
+		m.cachedOnly, m.hideTrash, m.hideAdult = false, false, false
+	case "enter":
+		cursor := m.table.Cursor()
+		if cursor < 0 || cursor >= len(m.rows) {
+			return nil, true
+		}
+		// Hand the stream over to a manual session
+		target := m.target
+		target.magnet = m.rows[cursor].InfoHash
+		return openScrapeWizardCmd(target), true
+	default:
+		return nil, false
+	}
+
+	m.applyFilters()
+	return nil, true
+}
+
+// scrapeItem starts a scrape for the target, replacing the one running
+func (m *ScrapeModel) scrapeItem(target scrapeTarget) tea.Cmd {
+	params := &api.ScrapeItemParams{}
+	if target.itemID != "" {
+		params.ItemID = models.StringPtr(target.itemID)
+	} else {
+		movie := target.itemType != "show"
+		id, ok := parseExternalID(target.externalID, movie)
+		if !ok {
+			m.error = externalIDHint
+			return nil
+		}
+		params.IMDBId, params.TMDBId, params.TVDBId = id.imdb, id.tmdb, id.tvdb
+		params.MediaType = mediaTypeOf(movie)
+	}
+
+	m.target = target
+	m.editing = false
+	m.idInput.Blur()
+	m.scraping = true
+	m.scraped = false
+	m.streams = nil
+	m.applyFilters()
+	m.started = m.now()
+	m.error = ""
+	m.message = ""
+	m.spinner = NewLoadingComponent(fmt.Sprintf("Scraping %s...", target.title), m.theme)
+
+	ctx, gen := m.scrapeFetch.begin(m.ctx)
+	return tea.Batch(
+		m.spinner.Init(),
+		func() tea.Msg {
+			resp, err := m.client.ScrapeItem(ctx, params)
+			return scrapeResultMsg{gen: gen, resp: resp, err: err}
+		},
+	)
+}
+
+// setStreams replaces the results, best ranked first
+func (m *ScrapeModel) setStreams(streams map[string]models.Stream) {
+	m.streams = make([]models.Stream, 0, len(streams))
+	for infoHash, stream := range streams {
+		if stream.InfoHash == "" {
+			stream.InfoHash = infoHash
+		}
+		m.streams = append(m.streams, stream)
+	}
+	sort.Slice(m.streams, func(i, j int) bool {
+		if m.streams[i].Rank != m.streams[j].Rank {
+			return m.streams[i].Rank > m.streams[j].Rank
+		}
+		return m.streams[i].InfoHash < m.streams[j].InfoHash
+	})
+
+	// Filters for values the new results lack would hide everything
+	if !contains(m.resolutions(), m.resolution) {
+		m.resolution = ""
+	}
+	if !contains(m.languages(), m.language) {
+		m.language = ""
+	}
+	m.applyFilters()
+	m.table.SetCursor(0)
+}
+
+// matches reports whether a stream passes the filters
+func (m *ScrapeModel) matches(stream models.Stream) bool {
+	parsed := stream.ParsedData
+	switch {
+	case m.resolution != "" && parsed.Resolution != m.resolution,
+		m.language != "" && !contains(parsed.Languages, m.language),
+		m.cachedOnly && !stream.IsCached,
+		m.hideTrash && parsed.Trash,
+		m.hideAdult && parsed.Adult:
+		return false
+	}
+	return true
+}
+
+// applyFilters rebuilds the table from the streams passing the filters
+func (m *ScrapeModel) applyFilters() {
+	m.rows = nil
+	tableRows := []table.Row{}
+	for _, stream := range m.streams {
+		if !m.matches(stream) {
+			continue
+		}
+		m.rows = append(m.rows, stream)
+
+		parsed := stream.ParsedData
+		title := stream.RawTitle
+		if title == "" {
+			title = parsed.RawTitle
+		}
+		cached := "No"
+		if stream.IsCached {
+			cached = "Yes"
+		}
+		var flags []string
+		if parsed.Trash {
+			flags = append(flags, "trash")
+		}
+		if parsed.Adult {
+			flags = append(flags, "adult")
+		}
+
+		tableRows = append(tableRows, table.Row{
+			strconv.Itoa(stream.Rank),
+			truncateString(title, 48),
+			orDash(parsed.Resolution),
+			orDash(derefString(parsed.Size)),
+			cached,
+			orDash(strings.Join(parsed.Languages, ",")),
+			strings.Join(flags, ","),
+		})
+	}
+
+	m.table.SetRows(tableRows)
+	if cursor := m.table.Cursor(); cursor >= len(tableRows) && len(tableRows) > 0 {
+		m.table.SetCursor(len(tableRows) - 1)
+	}
+}
+
+// resolutions returns the resolutions found, best first
+func (m *ScrapeModel) resolutions() []string {
+	found := map[string]bool{}
+	for _, stream := range m.streams {
+		if stream.ParsedData.Resolution != "" {
+			found[stream.ParsedData.Resolution] = true
+		}
+	}
+
+	var values []string
+	for _, res := range resolutionOrder {
+		if found[res] {
+			values = append(values, res)
+			delete(found, res)
+		}
+	}
+	var other []string
+	for res := range found {
+		other = append(other, res)
+	}
+	sort.Strings(other)
+	return append(values, other...)
+}
+
+// languages returns the languages found, sorted
+func (m *ScrapeModel) languages() []string {
+	found := map[string]bool{}
+	for _, stream := range m.streams {
+		for _, lang := range stream.ParsedData.Languages {
+			found[lang] = true
+		}
+	}
+	values := make([]string, 0, len(found))
+	for lang := range found {
+		values = append(values, lang)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// cancelRequests cancels the scrape in flight and drops its result
+func (m *ScrapeModel) cancelRequests() {
+	m.scrapeFetch.stop()
+	if m.scraping {
+		m.scraping = false
+		m.message = "Scrape cancelled"
+	}
+}
+
+// View implements tea.Model
+func (m *ScrapeModel) View() string {
+	var sections []string
+
+	title := "🔎 Scrape"
+	if m.target.title != "" {
+		title = fmt.Sprintf("🔎 Scrape: %s", m.target.title)
+	}
+	sections = append(sections, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39")).Render(title))
+
+	if m.editing {
+		mediaType := "tv show"
+		if m.movie {
+			mediaType = "movie"
+		}
+		sections = append(sections,
+			"IMDb, TMDB (movies) or TVDB (shows) ID:",
+			m.idInput.View(),
+			fmt.Sprintf("Media type: %s [ctrl+t] toggle", mediaType),
+		)
+	}
+
+	switch {
+	case m.scraping:
+		elapsed := m.now().Sub(m.started).Truncate(time.Second)
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(
+			fmt.Sprintf("%s %s  [esc] cancel", m.spinner.View(), elapsed)))
+	case m.message != "":
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render(m.message))
+	}
+	if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
+
+	if len(m.streams) > 0 {
+		sections = append(sections, m.renderFilters())
+		sections = append(sections, fmt.Sprintf("Showing %d of %d streams", len(m.rows), len(m.streams)))
+		sections = append(sections, m.table.View())
+	} else if m.scraped && m.error == "" {
+		sections = append(sections, "No streams found.")
+	}
+
+	sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(m.controls()))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderFilters renders the filter bar
+func (m *ScrapeModel) renderFilters() string {
+	onOff := func(on bool, yes, no string) string {
+		if on {
+			return yes
+		}
+		return no
+	}
+	parts := []string{
+		fmt.Sprintf("[f] Resolution: %s", orValue(m.resolution, "any")),
+		fmt.Sprintf("[L] Language: %s", orValue(m.language, "any")),
+		fmt.Sprintf("[c] Cached: %s", onOff(m.cachedOnly, "only", "any")),
+		fmt.Sprintf("[t] Trash: %s", onOff(m.hideTrash, "hidden", "shown")),
+		fmt.Sprintf("[a] Adult: %s", onOff(m.hideAdult, "hidden", "shown")),
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Render(strings.Join(parts, " | "))
+}
+
+// controls returns the key help of the current state
+func (m *ScrapeModel) controls() string {
+	switch {
+	case m.editing:
+		return "Controls: [enter] scrape [ctrl+t] movie/tv [esc] back to results"
+	case m.scraping:
+		return "Controls: [esc] cancel scrape"
+	}
+	return "Controls: [enter] manual scrape with stream [/] new scrape [r] scrape again [x] clear filters"
+}
+
+// nextValue returns the value after current in values, cycling through ""
+func nextValue(values []string, current string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, v := range values {
+		if v == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+// orValue returns s, or fallback if it is empty
+func orValue(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// contains reports whether values holds v. The empty string is always held.
+func contains(values []string, v string) bool {
+	if v == "" {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+// scrapeResult returns the outcome of the scrape started by cmd
+func scrapeResult(t *testing.T, cmd tea.Cmd) scrapeResultMsg {
+	t.Helper()
+	for _, msg := range batchMsgs(cmd) {
+		if result, ok := msg.(scrapeResultMsg); ok {
+			return result
+		}
+	}
+	t.Fatal("expected a scrape request")
+	return scrapeResultMsg{}
+}
+
+func TestE2EScrapeScreenFiltersAndHandsOff(t *testing.T) {
+	m := NewScrapeModel(newDemoClient(t), context.Background())
+	m.SetSize(140, 50)
+
+	// Severance by TVDB ID
+	m.idInput.SetValue("371980")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.scraping || !strings.Contains(m.View(), "Scraping 371980...") {
+		t.Fatalf("expected scrape progress, got:\n%s", m.View())
+	}
+	m, _ = m.Update(scrapeResult(t, cmd))
+
+	if m.error != "" || len(m.streams) == 0 {
+		t.Fatalf("expected streams, got error %q", m.error)
+	}
+	for i := 1; i < len(m.streams); i++ {
+		if m.streams[i].Rank > m.streams[i-1].Rank {
+			t.Fatalf("streams not sorted by rank at %d", i)
+		}
+	}
+
+	// Cached 2160p only
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if m.resolution != "2160p" || len(m.rows) == 0 || len(m.rows) >= len(m.streams) {
+		t.Fatalf("expected the filters to narrow the results, got %d of %d for %q", len(m.rows), len(m.streams), m.resolution)
+	}
+	for _, stream := range m.rows {
+		if !stream.IsCached || stream.ParsedData.Resolution != "2160p" {
+			t.Errorf("stream %s does not match the filters", stream.RawTitle)
+		}
+	}
+	if len(m.table.Rows()) != len(m.rows) {
+		t.Error("table out of sync with the filtered streams")
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	open, ok := cmd().(openScrapeWizardMsg)
+	if !ok {
+		t.Fatal("expected enter to open the manual scrape wizard")
+	}
+	if open.target.magnet != m.rows[0].InfoHash || open.target.externalID != "371980" || open.target.itemType != "show" {
+		t.Errorf("unexpected wizard target %+v", open.target)
+	}
+
+	// Clearing the filters shows everything again
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(m.rows) != len(m.streams) {
+		t.Errorf("expected all %d streams after clearing, got %d", len(m.streams), len(m.rows))
+	}
+}
+
+func TestScrapeScreenFilters(t *testing.T) {
+	m := NewScrapeModel(&fakeAPI{}, context.Background())
+	m.setStreams(map[string]models.Stream{
+		"a": {Rank: 10, ParsedData: models.ParsedData{Resolution: "1080p", Languages: []string{"en"}}},
+		"b": {Rank: 30, ParsedData: models.ParsedData{Resolution: "720p", Languages: []string{"fr"}, Trash: true}},
+		"c": {Rank: 20, ParsedData: models.ParsedData{Resolution: "1080p", Adult: true}},
+	})
+
+	if m.rows[0].InfoHash != "b" || m.rows[2].InfoHash != "a" {
+		t.Fatalf("expected the streams best ranked first, got %s, %s, %s", m.rows[0].InfoHash, m.rows[1].InfoHash, m.rows[2].InfoHash)
+	}
+
+	tests := []struct {
+		name  string
+		setup func()
+		want  int
+	}{
+		{"language", func() { m.language = "fr" }, 1},
+		{"trash", func() { m.hideTrash = true }, 2},
+		{"adult", func() { m.hideAdult = true }, 2},
+		{"trash and adult", func() { m.hideTrash, m.hideAdult = true, true }, 1},
+	}
+	for _, tt := range tests {
+		m.resolution, m.language, m.cachedOnly, m.hideTrash, m.hideAdult = "", "", false, false, false
+		tt.setup()
+		m.applyFilters()
+		if len(m.rows) != tt.want {
+			t.Errorf("%s: expected %d streams, got %d", tt.name, tt.want, len(m.rows))
+		}
+	}
+
+	if got := m.resolutions(); strings.Join(got, ",") != "1080p,720p" {
+		t.Errorf("expected resolutions best first, got %v", got)
+	}
+}
+
+func TestScrapeScreenCancel(t *testing.T) {
+	fake := &fakeAPI{scrape: &models.ScrapeItemResponse{Streams: map[string]models.Stream{"a": {Rank: 1}}}}
+	m := NewScrapeModel(fake, context.Background())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.error != externalIDHint {
+		t.Fatalf("expected an invalid ID to be refused, got %q", m.error)
+	}
+
+	cmd = m.scrapeItem(scrapeTarget{itemID: "1", title: "The Matrix"})
+	result := scrapeResult(t, cmd)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.scraping || fake.scrapeCtxs[0].Err() != context.Canceled {
+		t.Fatal("expected esc to cancel the scrape")
+	}
+
+	m, _ = m.Update(result)
+	if len(m.streams) != 0 {
+		t.Error("result of a cancelled scrape was shown")
+	}
+	if *fake.scrapeCalls[0].ItemID != "1" {
+		t.Errorf("expected the item to be scraped, got %+v", fake.scrapeCalls[0])
+	}
+}
+
+func TestAppPassesThemeToScrape(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.UI.Theme = "light"
+	app := NewAppWithClient(cfg, &fakeAPI{})
+	if app.scrape.theme != LightTheme() {
+		t.Error("expected the scrape screen to use the configured theme")
+	}
+}
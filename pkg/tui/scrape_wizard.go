@@ -0,0 +1,967 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/models"
+)
+
+// wizardStep is a step of the manual scrape wizard
+type wizardStep int
+
+const (
+	stepSource wizardStep = iota
+	stepFiles
+	stepMapping
+	stepReview
+)
+
+var wizardStepNames = []string{"Source", "Files", "Match", "Review"}
+
+const (
+	// wizardSessionTTL is how long Riven keeps a manual session; it sets
+	// the deadline when the session's expiry cannot be read
+	wizardSessionTTL = 5 * time.Minute
+	// quitAbortTimeout bounds the abort sent when the app quits
+	quitAbortTimeout = 3 * time.Second
+)
+
+var (
+	imdbIDPattern     = regexp.MustCompile(`^tt\d{5,}$`)
+	numericIDPattern  = regexp.MustCompile(`^\d+$`)
+	episodeTagPattern = regexp.MustCompile(`(?i)s(\d{1,2})[ ._-]?e(\d{1,3})|\b(\d{1,2})x(\d{2,3})\b`)
+	videoExtensions   = map[string]bool{".mkv": true, ".mp4": true, ".avi": true, ".m4v": true, ".ts": true, ".wmv": true, ".mov": true}
+)
+
+// scrapeTarget is what a manual scrape session is started for
+type scrapeTarget struct {
+	itemID   string // Riven item ID; empty when scraping by external ID
+	title    string
+	itemType string // movie, show, season or episode
+	magnet   string // optional magnet link or infohash to start with
+
+	// externalID is an IMDb, TMDB or TVDB ID used when there is no item
+	externalID string
+}
+
+// externalID holds the ID typed in for something not in the library
+type externalID struct {
+	imdb *string
+	tmdb *string
+	tvdb *string
+}
+
+// parseExternalID works out which kind of ID was entered. Numeric IDs are
+// TMDB IDs for movies and TVDB IDs for shows, as Riven looks them up.
+func parseExternalID(id string, movie bool) (externalID, bool) {
+	id = strings.TrimSpace(id)
+	switch {
+	case imdbIDPattern.MatchString(id):
+		return externalID{imdb: models.StringPtr(id)}, true
+	case numericIDPattern.MatchString(id) && movie:
+		return externalID{tmdb: models.StringPtr(id)}, true
+	case numericIDPattern.MatchString(id):
+		return externalID{tvdb: models.StringPtr(id)}, true
+	}
+	return externalID{}, false
+}
+
+// externalIDHint explains the IDs parseExternalID accepts
+const externalIDHint = "Enter an IMDb ID (tt…), or a TMDB ID for movies or TVDB ID for shows"
+
+// fileMapping maps a selected file to an episode. Season 0 means the file
+// is not mapped and will be skipped.
+type fileMapping struct {
+	file    models.DebridFile
+	season  int
+	episode int
+}
+
+// ScrapeWizardModel walks through a manual scraping session: start it from
+// a magnet, pick the torrent files, match them to the item and complete it
+type ScrapeWizardModel struct {
+	client  api.ScrapingAPI
+	ctx     context.Context
+	width   int
+	height  int
+	now     func() time.Time
+	target  scrapeTarget
+	step    wizardStep
+	busy    string // description of the call in flight
+	call    fetchSlot
+	error   string
+	aborted bool // an abort has been sent; close once it returns
+	// starting is set while a session start is in flight. Leaving then
+	// waits for the session, so it can be aborted instead of left open.
+	starting bool
+
+	// Source step
+	magnetInput textinput.Model
+	idInput     textinput.Model
+	focus       int
+	movie       bool // media type when starting from an external ID
+
+	// Session
+	session   *models.StartSessionResponse
+	expiresAt time.Time
+	files     []models.DebridFile
+	selected  map[int]bool
+	cursor    int
+
+	// Match step
+	mapping   []fileMapping
+	movieFile int
+	editInput textinput.Model
+	editing   bool
+}
+
+// Wizard messages
+type openScrapeWizardMsg struct {
+	target scrapeTarget
+}
+
+type closeScrapeWizardMsg struct {
+	completed bool
+}
+
+type sessionStartedMsg struct {
+	gen     int
+	session *models.StartSessionResponse
+	err     error
+}
+
+type filesSelectedMsg struct {
+	gen int
+	err error
+}
+
+type attributesUpdatedMsg struct {
+	gen int
+	err error
+}
+
+type sessionCompletedMsg struct {
+	gen     int
+	message string
+	err     error
+}
+
+type sessionAbortedMsg struct {
+	err error
+}
+
+type wizardTickMsg struct {
+	sessionID string
+}
+
+// openScrapeWizardCmd opens the manual scrape wizard
+func openScrapeWizardCmd(target scrapeTarget) tea.Cmd {
+	return func() tea.Msg {
+		return openScrapeWizardMsg{target: target}
+	}
+}
+
+// NewScrapeWizardModel creates a wizard for the given target. Without an
+// item ID the source step also asks for an IMDb, TMDB or TVDB ID.
+func NewScrapeWizardModel(client api.ScrapingAPI, ctx context.Context, target scrapeTarget) *ScrapeWizardModel {
+	magnetInput := textinput.New()
+	magnetInput.Placeholder = "magnet:?xt=urn:btih:... or infohash"
+	magnetInput.CharLimit = 2048
+	magnetInput.Width = 60
+	magnetInput.SetValue(target.magnet)
+	magnetInput.Focus()
+
+	idInput := textinput.New()
+	idInput.Placeholder = "tt0133093, TMDB or TVDB ID"
+	idInput.CharLimit = 20
+	idInput.Width = 30
+	idInput.SetValue(target.externalID)
+
+	editInput := textinput.New()
+	editInput.Placeholder = "S01E01"
+	editInput.CharLimit = 10
+	editInput.Width = 10
+
+	return &ScrapeWizardModel{
+		client:      client,
+		ctx:         ctx,
+		now:         time.Now,
+		target:      target,
+		magnetInput: magnetInput,
+		idInput:     idInput,
+		editInput:   editInput,
+		movie:       target.itemType != "show",
+		selected:    make(map[int]bool),
+	}
+}
+
+// SetSize sets the size of the wizard
+func (m *ScrapeWizardModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height - 3 // Account for navigation bar
+
+	m.magnetInput.Width = min(width-20, 100)
+}
+
+// Init implements tea.Model
+func (m *ScrapeWizardModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// capturesInput implements inputCapturer. The wizard keeps every key until
+// it is finished or aborted, so a session is never left behind by switching
+// screens.
+func (m *ScrapeWizardModel) capturesInput() bool {
+	return true
+}
+
+// Update implements tea.Model
+func (m *ScrapeWizardModel) Update(msg tea.Msg) (*ScrapeWizardModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sessionStartedMsg:
+		if !m.call.finish(msg.gen) {
+			return m, nil
+		}
+		m.starting = false
+		if m.aborted {
+			if msg.err != nil {
+				return m, func() tea.Msg { return closeScrapeWizardMsg{} }
+			}
+			return m, m.abortSession(msg.session.SessionID)
+		}
+		m.busy = ""
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to start session: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.startSession(msg.session)
+		return m, m.tick()
+
+	case filesSelectedMsg:
+		if !m.call.finish(msg.gen) {
+			return m, nil
+		}
+		m.busy = ""
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to select files: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.buildMapping()
+		m.step = stepMapping
+		m.cursor = 0
+		return m, nil
+
+	case attributesUpdatedMsg:
+		if !m.call.finish(msg.gen) {
+			return m, nil
+		}
+		m.busy = ""
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to match files: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.step = stepReview
+		return m, nil
+
+	case sessionCompletedMsg:
+		if !m.call.finish(msg.gen) {
+			return m, nil
+		}
+		m.busy = ""
+		if msg.err != nil {
+			m.error = fmt.Sprintf("Failed to complete session: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.session = nil
+		return m, tea.Batch(
+			toastCmd(msg.message, StatusSuccess),
+			func() tea.Msg { return closeScrapeWizardMsg{completed: true} },
+		)
+
+	case sessionAbortedMsg:
+		if !m.aborted {
+			return m, nil
+		}
+		// An expired session is gone either way, so errors are only noted
+		cmd := toastCmd("Manual scrape session aborted", StatusInfo)
+		if msg.err != nil && !api.IsNotFound(msg.err) {
+			cmd = toastCmd(fmt.Sprintf("Failed to abort session: %s", firstLine(describeError(msg.err))), StatusWarning)
+		}
+		return m, tea.Batch(cmd, func() tea.Msg { return closeScrapeWizardMsg{} })
+
+	case wizardTickMsg:
+		if m.session == nil || msg.sessionID != m.session.SessionID {
+			return m, nil
+		}
+		if m.expiresAt.IsZero() || m.now().Before(m.expiresAt) {
+			return m, m.tick()
+		}
+		// The server drops expired sessions; abort anyway to release the
+		// torrent, and start over
+		cmd := m.abortSession(m.session.SessionID)
+		m.reset()
+		m.error = "The session expired. Start a new one to try again."
+		return m, cmd
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey handles keys for the current step
+func (m *ScrapeWizardModel) handleKey(msg tea.KeyMsg) tea.Cmd {
+	if m.editing {
+		return m.handleEditKey(msg)
+	}
+
+	if msg.String() == "esc" {
+		return m.abort()
+	}
+	if m.busy != "" {
+		return nil
+	}
+
+	switch m.step {
+	case stepSource:
+		return m.handleSourceKey(msg)
+	case stepFiles:
+		return m.handleFilesKey(msg)
+	case stepMapping:
+		return m.handleMappingKey(msg)
+	case stepReview:
+		if msg.String() == "enter" {
+			return m.completeSession()
+		}
+	}
+	return nil
+}
+
+func (m *ScrapeWizardModel) handleSourceKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		return m.startSessionCmd()
+	case "tab", "shift+tab", "up", "down":
+		if m.target.itemID == "" {
+			m.focus = 1 - m.focus
+			if m.focus == 0 {
+				m.idInput.Blur()
+				return m.magnetInput.Focus()
+			}
+			m.magnetInput.Blur()
+			return m.idInput.Focus()
+		}
+		return nil
+	case "ctrl+t":
+		m.movie = !m.movie
+		return nil
+	}
+
+	var cmd tea.Cmd
+	if m.focus == 0 {
+		m.magnetInput, cmd = m.magnetInput.Update(msg)
+	} else {
+		m.idInput, cmd = m.idInput.Update(msg)
+	}
+	return cmd
+}
+
+func (m *ScrapeWizardModel) handleFilesKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.files)-1 {
+			m.cursor++
+		}
+	case " ":
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case "a":
+		all := len(m.selectedFiles()) == len(m.files)
+		for i := range m.files {
+			m.selected[i] = !all
+		}
+	case "enter":
+		return m.selectFilesCmd()
+	}
+	return nil
+}
+
+func (m *ScrapeWizardModel) handleMappingKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.mapping)-1 {
+			m.cursor++
+		}
+	case " ":
+		if m.isShow() {
+			return nil
+		}
+		m.movieFile = m.cursor
+	case "e":
+		if !m.isShow() {
+			return nil
+		}
+		m.editing = true
+		m.editInput.SetValue(formatEpisode(m.mapping[m.cursor]))
+		m.editInput.CursorEnd()
+		return m.editInput.Focus()
+	case "x":
+		if m.isShow() {
+			m.mapping[m.cursor].season, m.mapping[m.cursor].episode = 0, 0
+		}
+	case "enter":
+		return m.updateAttributesCmd()
+	}
+	return nil
+}
+
+func (m *ScrapeWizardModel) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editInput.Blur()
+		return nil
+	case "enter":
+		value := strings.TrimSpace(m.editInput.Value())
+		if value == "" {
+			m.mapping[m.cursor].season, m.mapping[m.cursor].episode = 0, 0
+		} else if season, episode, ok := parseEpisodeTag(value); ok {
+			m.mapping[m.cursor].season, m.mapping[m.cursor].episode = season, episode
+		} else {
+			m.error = fmt.Sprintf("%q is not an episode, use S01E02 or 1x02", value)
+			return nil
+		}
+		m.error = ""
+		m.editing = false
+		m.editInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.editInput, cmd = m.editInput.Update(msg)
+	return cmd
+}
+
+// isShow reports whether files are matched to episodes rather than to a
+// single movie
+func (m *ScrapeWizardModel) isShow() bool {
+	if m.target.itemID != "" {
+		return m.target.itemType != "movie"
+	}
+	return !m.movie
+}
+
+// sessionID returns the ID of the running session, or ""
+func (m *ScrapeWizardModel) sessionID() string {
+	if m.session == nil {
+		return ""
+	}
+	return m.session.SessionID
+}
+
+// startSessionCmd validates the source step and starts a session
+func (m *ScrapeWizardModel) startSessionCmd() tea.Cmd {
+	magnet := strings.TrimSpace(m.magnetInput.Value())
+	if magnet == "" {
+		m.error = "Enter a magnet link or infohash"
+		return nil
+	}
+
+	params := &api.StartManualSessionParams{Magnet: models.StringPtr(magnet)}
+	if m.target.itemID != "" {
+		params.ItemID = models.StringPtr(m.target.itemID)
+	} else {
+		id, ok := parseExternalID(m.idInput.Value(), m.movie)
+		if !ok {
+			m.error = externalIDHint
+			return nil
+		}
+		params.IMDBId, params.TMDBId, params.TVDBId = id.imdb, id.tmdb, id.tvdb
+		params.MediaType = mediaTypeOf(m.movie)
+	}
+
+	m.error = ""
+	m.busy = "Starting session..."
+	m.starting = true
+	ctx, gen := m.call.begin(m.ctx)
+	return func() tea.Msg {
+		session, err := m.client.StartManualSession(ctx, params)
+		return sessionStartedMsg{gen: gen, session: session, err: err}
+	}
+}
+
+// startSession moves to the file step of a newly started session
+func (m *ScrapeWizardModel) startSession(session *models.StartSessionResponse) {
+	m.session = session
+	m.expiresAt = m.now().Add(wizardSessionTTL)
+	if t, ok := models.ParseTime(session.ExpiresAt); ok {
+		m.expiresAt = t
+	}
+
+	m.files = nil
+	if session.Containers != nil {
+		m.files = session.Containers.Files
+	}
+	m.selected = make(map[int]bool)
+	for i, f := range m.files {
+		m.selected[i] = isVideoFile(f.Filename)
+	}
+	m.cursor = 0
+	m.step = stepFiles
+}
+
+// selectedFiles returns the files picked in the file step
+func (m *ScrapeWizardModel) selectedFiles() []models.DebridFile {
+	var files []models.DebridFile
+	for i, f := range m.files {
+		if m.selected[i] {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// selectFilesCmd sends the picked files
+func (m *ScrapeWizardModel) selectFilesCmd() tea.Cmd {
+	files := m.selectedFiles()
+	if len(files) == 0 {
+		m.error = "Select at least one file"
+		return nil
+	}
+
+	container := models.Container{Files: files}
+	if m.session.Containers != nil {
+		container.InfoHash = m.session.Containers.InfoHash
+	}
+
+	m.error = ""
+	m.busy = "Selecting files..."
+	sessionID := m.sessionID()
+	ctx, gen := m.call.begin(m.ctx)
+	return func() tea.Msg {
+		_, err := m.client.SelectFiles(ctx, sessionID, container)
+		return filesSelectedMsg{gen: gen, err: err}
+	}
+}
+
+// buildMapping prepares the match step from the selected files, guessing
+// episodes from the file names and the movie file from the file sizes
+func (m *ScrapeWizardModel) buildMapping() {
+	m.mapping = nil
+	m.movieFile = 0
+	for _, f := range m.selectedFiles() {
+		mapping := fileMapping{file: f}
+		if season, episode, ok := parseEpisodeTag(path.Base(f.Filename)); ok {
+			mapping.season, mapping.episode = season, episode
+		}
+		if len(m.mapping) > 0 && f.Filesize > m.mapping[m.movieFile].file.Filesize {
+			m.movieFile = len(m.mapping)
+		}
+		m.mapping = append(m.mapping, mapping)
+	}
+}
+
+// showFileData builds the season/episode mapping sent for shows
+func (m *ScrapeWizardModel) showFileData() models.ShowFileData {
+	data := models.ShowFileData{}
+	for _, mapping := range m.mapping {
+		if mapping.season == 0 && mapping.episode == 0 {
+			continue
+		}
+		if data[mapping.season] == nil {
+			data[mapping.season] = map[int]models.DebridFile{}
+		}
+		data[mapping.season][mapping.episode] = mapping.file
+	}
+	return data
+}
+
+// updateAttributesCmd sends the file matches
+func (m *ScrapeWizardModel) updateAttributesCmd() tea.Cmd {
+	var data interface{}
+	if m.isShow() {
+		files := m.showFileData()
+		if len(files) == 0 {
+			m.error = "Match at least one file to an episode"
+			return nil
+		}
+		data = files
+	} else {
+		data = m.mapping[m.movieFile].file
+	}
+
+	m.error = ""
+	m.busy = "Matching files..."
+	sessionID := m.sessionID()
+	ctx, gen := m.call.begin(m.ctx)
+	return func() tea.Msg {
+		_, err := m.client.UpdateAttributes(ctx, sessionID, data)
+		return attributesUpdatedMsg{gen: gen, err: err}
+	}
+}
+
+// completeSession completes the session
+func (m *ScrapeWizardModel) completeSession() tea.Cmd {
+	m.error = ""
+	m.busy = "Completing session..."
+	sessionID := m.sessionID()
+	ctx, gen := m.call.begin(m.ctx)
+	return func() tea.Msg {
+		resp, err := m.client.CompleteManualSession(ctx, sessionID)
+		message := "Manual scrape completed"
+		if err == nil && resp.Message != "" {
+			message = resp.Message
+		}
+		return sessionCompletedMsg{gen: gen, message: message, err: err}
+	}
+}
+
+// abort leaves the wizard, aborting the session if one is running. The
+// server usually creates a session that is still starting, so the start is
+// left to finish and its session aborted.
+func (m *ScrapeWizardModel) abort() tea.Cmd {
+	if m.starting {
+		m.aborted = true
+		m.busy = "Aborting session..."
+		return nil
+	}
+
+	sessionID := m.sessionID()
+	m.call.stop()
+	if sessionID == "" {
+		return func() tea.Msg { return closeScrapeWizardMsg{} }
+	}
+
+	m.aborted = true
+	m.busy = "Aborting session..."
+	m.session = nil
+	return m.abortSession(sessionID)
+}
+
+// abortSession aborts a session. It is not tied to the wizard's calls, so
+// cancelling a step never prevents the abort from reaching the server.
+func (m *ScrapeWizardModel) abortSession(sessionID string) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		_, err := m.client.AbortManualSession(ctx, sessionID)
+		return sessionAbortedMsg{err: err}
+	}
+}
+
+// abortOnQuit aborts the open session, if any, before the app exits. The
+// app's context is cancelled on quit, so the abort gets a short-lived one
+// of its own.
+func (m *ScrapeWizardModel) abortOnQuit() {
+	if m.session == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), quitAbortTimeout)
+	defer cancel()
+	_, _ = m.client.AbortManualSession(ctx, m.session.SessionID)
+}
+
+// reset returns to the source step, keeping what was typed
+func (m *ScrapeWizardModel) reset() {
+	m.call.stop()
+	m.session = nil
+	m.busy = ""
+	m.step = stepSource
+	m.files = nil
+	m.mapping = nil
+	m.editing = false
+}
+
+// tick schedules the next expiry check of the session
+func (m *ScrapeWizardModel) tick() tea.Cmd {
+	sessionID := m.sessionID()
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return wizardTickMsg{sessionID: sessionID}
+	})
+}
+
+// View implements tea.Model
+func (m *ScrapeWizardModel) View() string {
+	var sections []string
+
+	title := "🧲 Manual Scrape"
+	if m.target.title != "" {
+		title = fmt.Sprintf("🧲 Manual Scrape: %s", m.target.title)
+	}
+	sections = append(sections, lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39")).Render(title))
+	sections = append(sections, m.renderSteps())
+
+	if m.session != nil && !m.expiresAt.IsZero() {
+		remaining := m.expiresAt.Sub(m.now()).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		info := fmt.Sprintf("Torrent: %s | Session expires in %s", m.session.TorrentInfo.Name, remaining)
+		color := lipgloss.Color("241")
+		if remaining < time.Minute {
+			color = lipgloss.Color("214")
+		}
+		sections = append(sections, lipgloss.NewStyle().Foreground(color).Render(info))
+	}
+
+	var body string
+	switch m.step {
+	case stepSource:
+		body = m.renderSource()
+	case stepFiles:
+		body = m.renderFiles()
+	case stepMapping:
+		body = m.renderMapping()
+	case stepReview:
+		body = m.renderReview()
+	}
+	sections = append(sections, lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(min(m.width-4, 120)).
+		Render(body))
+
+	if m.busy != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("⏳ "+m.busy))
+	}
+	if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
+	sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(m.controls()))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderSteps renders the step indicator
+func (m *ScrapeWizardModel) renderSteps() string {
+	var steps []string
+	for i, name := range wizardStepNames {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		if wizardStep(i) == m.step {
+			style = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Padding(0, 1)
+		}
+		steps = append(steps, style.Render(fmt.Sprintf("%d %s", i+1, name)))
+	}
+	return strings.Join(steps, " › ")
+}
+
+func (m *ScrapeWizardModel) renderSource() string {
+	lines := []string{"Magnet link or infohash:", m.magnetInput.View()}
+
+	if m.target.itemID == "" {
+		mediaType := "tv show"
+		if m.movie {
+			mediaType = "movie"
+		}
+		lines = append(lines,
+			"",
+			"IMDb, TMDB (movies) or TVDB (shows) ID:",
+			m.idInput.View(),
+			"",
+			fmt.Sprintf("Media type: %s [ctrl+t] toggle", mediaType),
+		)
+	} else {
+		lines = append(lines, "", fmt.Sprintf("Item: %s (%s %s)", m.target.title, m.target.itemType, m.target.itemID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *ScrapeWizardModel) renderFiles() string {
+	if len(m.files) == 0 {
+		return "The torrent has no files."
+	}
+
+	lines := []string{fmt.Sprintf("%d files, %d selected:", len(m.files), len(m.selectedFiles())), ""}
+	first, last := visibleRange(m.cursor, len(m.files), m.height-16)
+	for i := first; i < last; i++ {
+		f := m.files[i]
+		mark := "[ ]"
+		if m.selected[i] {
+			mark = "[x]"
+		}
+		line := fmt.Sprintf("%s %-9s %s", mark, formatBytes(f.Filesize), f.Filename)
+		lines = append(lines, m.cursorLine(i, line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *ScrapeWizardModel) renderMapping() string {
+	if !m.isShow() {
+		lines := []string{"Pick the movie file:", ""}
+		for i, mapping := range m.mapping {
+			mark := "( )"
+			if i == m.movieFile {
+				mark = "(•)"
+			}
+			lines = append(lines, m.cursorLine(i, fmt.Sprintf("%s %-9s %s", mark, formatBytes(mapping.file.Filesize), mapping.file.Filename)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	lines := []string{"Match files to episodes:", ""}
+	first, last := visibleRange(m.cursor, len(m.mapping), m.height-16)
+	for i := first; i < last; i++ {
+		mapping := m.mapping[i]
+		episode := formatEpisode(mapping)
+		if episode == "" {
+			episode = "skip"
+		}
+		if m.editing && i == m.cursor {
+			episode = m.editInput.View()
+		}
+		lines = append(lines, m.cursorLine(i, fmt.Sprintf("%-8s %s", episode, mapping.file.Filename)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *ScrapeWizardModel) renderReview() string {
+	lines := []string{"Ready to complete the session:", ""}
+	if !m.isShow() {
+		lines = append(lines, fmt.Sprintf("Movie file: %s", m.mapping[m.movieFile].file.Filename))
+		return strings.Join(lines, "\n")
+	}
+
+	data := m.showFileData()
+	seasons := make([]int, 0, len(data))
+	for season := range data {
+		seasons = append(seasons, season)
+	}
+	sort.Ints(seasons)
+	for _, season := range seasons {
+		episodes := make([]int, 0, len(data[season]))
+		for episode := range data[season] {
+			episodes = append(episodes, episode)
+		}
+		sort.Ints(episodes)
+		parts := make([]string, len(episodes))
+		for i, e := range episodes {
+			parts[i] = strconv.Itoa(e)
+		}
+		lines = append(lines, fmt.Sprintf("Season %d: episodes %s", season, strings.Join(parts, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cursorLine highlights the line under the cursor
+func (m *ScrapeWizardModel) cursorLine(i int, line string) string {
+	if i == m.cursor {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render("▸ " + line)
+	}
+	return "  " + line
+}
+
+// controls returns the key help of the current step
+func (m *ScrapeWizardModel) controls() string {
+	if m.editing {
+		return "Controls: [enter] apply (empty skips the file) [esc] cancel edit"
+	}
+	switch m.step {
+	case stepSource:
+		if m.target.itemID == "" {
+			return "Controls: [tab] next field [ctrl+t] movie/tv [enter] start session [esc] cancel"
+		}
+		return "Controls: [enter] start session [esc] cancel"
+	case stepFiles:
+		return "Controls: [space] toggle [a] all/none [enter] continue [esc] abort session"
+	case stepMapping:
+		if m.isShow() {
+			return "Controls: [e] edit episode [x] skip file [enter] continue [esc] abort session"
+		}
+		return "Controls: [space] pick file [enter] continue [esc] abort session"
+	}
+	return "Controls: [enter] complete session [esc] abort session"
+}
+
+// mediaTypeOf returns the media type to send along with an external ID
+func mediaTypeOf(movie bool) *models.MediaType {
+	mediaType := models.MediaTypeTV
+	if movie {
+		mediaType = models.MediaTypeMovie
+	}
+	return &mediaType
+}
+
+// parseEpisodeTag extracts the season and episode from tags such as S01E02
+// or 1x02
+func parseEpisodeTag(s string) (season, episode int, ok bool) {
+	match := episodeTagPattern.FindStringSubmatch(s)
+	if match == nil {
+		return 0, 0, false
+	}
+	if match[1] != "" {
+		season, _ = strconv.Atoi(match[1])
+		episode, _ = strconv.Atoi(match[2])
+	} else {
+		season, _ = strconv.Atoi(match[3])
+		episode, _ = strconv.Atoi(match[4])
+	}
+	return season, episode, true
+}
+
+// formatEpisode renders a mapping as S01E02, or "" if it is not mapped
+func formatEpisode(mapping fileMapping) string {
+	if mapping.season == 0 && mapping.episode == 0 {
+		return ""
+	}
+	return fmt.Sprintf("S%02dE%02d", mapping.season, mapping.episode)
+}
+
+// isVideoFile reports whether a torrent file looks like the actual media,
+// rather than a sample or an extra
+func isVideoFile(filename string) bool {
+	lower := strings.ToLower(filename)
+	return videoExtensions[path.Ext(lower)] && !strings.Contains(lower, "sample")
+}
+
+// formatBytes renders a size in bytes for humans
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// visibleRange returns the window of a list of n lines that keeps the cursor
+// visible within the given height
+func visibleRange(cursor, n, height int) (int, int) {
+	if height < 1 {
+		height = 1
+	}
+	if n <= height {
+		return 0, n
+	}
+	first := cursor - height/2
+	if first < 0 {
+		first = 0
+	}
+	if first+height > n {
+		first = n - height
+	}
+	return first, first + height
+}
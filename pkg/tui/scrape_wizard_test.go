@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/api"
+	"riven-tui/pkg/config"
+	"riven-tui/pkg/models"
+)
+
+const testInfoHash = "0123456789abcdef0123456789abcdef01234567"
+
+// startTestSession drives the source step of a wizard to a running session
+func startTestSession(t *testing.T, m *ScrapeWizardModel) *ScrapeWizardModel {
+	t.Helper()
+
+	cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected the session to start, got error %q", m.error)
+	}
+	// The expiry tick returned with the session is not run
+	m, _ = m.Update(cmd())
+	if m.step != stepFiles {
+		t.Fatalf("expected the file step, got step %d: %s", m.step, m.error)
+	}
+	return m
+}
+
+func TestE2EScrapeWizardShowByIMDbID(t *testing.T) {
+	m := NewScrapeWizardModel(newDemoClient(t), context.Background(), scrapeTarget{})
+	m.SetSize(140, 40)
+	m.magnetInput.SetValue("magnet:?xt=urn:btih:" + testInfoHash)
+	m.idInput.SetValue("tt11280740") // Severance
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = startTestSession(t, m)
+
+	// Episodes are preselected, the sample and NFO are not
+	selected := m.selectedFiles()
+	if len(selected) != 19 {
+		t.Fatalf("expected the 19 episodes to be preselected, got %d", len(selected))
+	}
+	for _, f := range selected {
+		if strings.Contains(f.Filename, "Sample") || strings.HasSuffix(f.Filename, ".nfo") {
+			t.Errorf("unexpected preselected file %s", f.Filename)
+		}
+	}
+
+	m, _ = m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})())
+	if m.step != stepMapping {
+		t.Fatalf("expected the match step, got %d: %s", m.step, m.error)
+	}
+	if got := formatEpisode(m.mapping[9]); got != "S02E01" {
+		t.Errorf("expected the 10th file to match S02E01, got %q", got)
+	}
+
+	// Skip the first file by clearing its episode
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	for range "S01E01" {
+		m.handleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.editing || formatEpisode(m.mapping[0]) != "" {
+		t.Fatalf("expected the first file to be skipped, got %q", formatEpisode(m.mapping[0]))
+	}
+	if _, ok := m.showFileData()[1][1]; ok {
+		t.Error("skipped file was sent")
+	}
+
+	m, _ = m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})())
+	if m.step != stepReview {
+		t.Fatalf("expected the review step, got %d: %s", m.step, m.error)
+	}
+	if view := m.View(); !strings.Contains(view, "Season 1: episodes 2, 3") {
+		t.Errorf("expected the review to list the matched episodes:\n%s", view)
+	}
+
+	_, cmd := m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})())
+	closed := false
+	for _, msg := range batchMsgs(cmd) {
+		if msg, ok := msg.(closeScrapeWizardMsg); ok && msg.completed {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("expected the wizard to close after completing")
+	}
+}
+
+func TestE2EScrapeWizardMoviePicksLargestFile(t *testing.T) {
+	m := NewScrapeWizardModel(newDemoClient(t), context.Background(), scrapeTarget{itemID: "1", title: "The Matrix", itemType: "movie", magnet: testInfoHash})
+	m.SetSize(140, 40)
+	m = startTestSession(t, m)
+
+	// Select the sample too; the movie itself is still picked
+	m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	m.handleKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m, _ = m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})())
+	if len(m.mapping) != 2 {
+		t.Fatalf("expected 2 selected files, got %d: %s", len(m.mapping), m.error)
+	}
+	if name := m.mapping[m.movieFile].file.Filename; strings.Contains(name, "sample") {
+		t.Errorf("expected the largest file to be picked, got %s", name)
+	}
+
+	m, _ = m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})())
+	if m.step != stepReview {
+		t.Fatalf("expected the review step, got %d: %s", m.step, m.error)
+	}
+}
+
+func TestE2EScrapeWizardAbortsExpiredSession(t *testing.T) {
+	client := newDemoClient(t)
+	m := NewScrapeWizardModel(client, context.Background(), scrapeTarget{itemID: "1", itemType: "movie", magnet: testInfoHash})
+	m = startTestSession(t, m)
+	sessionID := m.session.SessionID
+
+	// Riven sends the expiry without a zone
+	if deadline, ok := models.ParseTime(m.session.ExpiresAt); !ok || !deadline.Equal(m.expiresAt) {
+		t.Fatalf("expected the deadline %q, got %v", m.session.ExpiresAt, m.expiresAt)
+	}
+
+	// Ticks before the deadline only schedule the next check
+	m.now = func() time.Time { return m.expiresAt.Add(-time.Minute) }
+	m, _ = m.Update(wizardTickMsg{sessionID: sessionID})
+	if m.session == nil {
+		t.Fatal("session ended before it expired")
+	}
+
+	m.now = func() time.Time { return m.expiresAt.Add(time.Second) }
+	m, cmd := m.Update(wizardTickMsg{sessionID: sessionID})
+	if m.session != nil || m.step != stepSource || !strings.Contains(m.error, "expired") {
+		t.Fatalf("expected an expired session to return to the source step, got step %d: %q", m.step, m.error)
+	}
+	if cmd == nil {
+		t.Fatal("expected the expired session to be aborted")
+	}
+	if msg := cmd().(sessionAbortedMsg); msg.err != nil {
+		t.Fatalf("abort failed: %v", msg.err)
+	}
+
+	if _, err := client.CompleteManualSession(context.Background(), sessionID); !api.IsNotFound(err) {
+		t.Errorf("expected the aborted session to be gone, got %v", err)
+	}
+}
+
+func TestScrapeWizardSessionDeadline(t *testing.T) {
+	now := time.Date(2026, 10, 16, 2, 0, 0, 0, time.UTC)
+	m := NewScrapeWizardModel(newDemoClient(t), context.Background(), scrapeTarget{})
+	m.now = func() time.Time { return now }
+
+	m.startSession(&models.StartSessionResponse{SessionID: "1", ExpiresAt: "2026-10-16T02:05:00.123456"})
+	if want := time.Date(2026, 10, 16, 2, 5, 0, 123456000, time.UTC); !m.expiresAt.Equal(want) {
+		t.Errorf("expected a naive expiry to be read as UTC, got %v", m.expiresAt)
+	}
+
+	// An expiry that cannot be read falls back to Riven's session lifetime
+	m.startSession(&models.StartSessionResponse{SessionID: "2", ExpiresAt: "soon"})
+	if !m.expiresAt.Equal(now.Add(wizardSessionTTL)) {
+		t.Errorf("expected a local deadline, got %v", m.expiresAt)
+	}
+}
+
+func TestScrapeWizardEscAbortsSession(t *testing.T) {
+	m := NewScrapeWizardModel(newDemoClient(t), context.Background(), scrapeTarget{itemID: "1", itemType: "movie", magnet: testInfoHash})
+
+	// Before a session is started esc just closes
+	if _, ok := m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})().(closeScrapeWizardMsg); !ok {
+		t.Fatal("expected esc to close the wizard")
+	}
+
+	m = startTestSession(t, m)
+	_, cmd := m.Update(m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})())
+	closed := false
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(closeScrapeWizardMsg); ok {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("expected the wizard to close once the session is aborted")
+	}
+}
+
+func TestScrapeWizardEscWhileStartingAbortsSession(t *testing.T) {
+	client := newDemoClient(t)
+	m := NewScrapeWizardModel(client, context.Background(), scrapeTarget{itemID: "1", itemType: "movie", magnet: testInfoHash})
+
+	start := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if start == nil {
+		t.Fatalf("expected the session to start, got error %q", m.error)
+	}
+	// Esc while the start is in flight waits for the session
+	if cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil {
+		t.Fatal("expected esc to wait for the session being started")
+	}
+
+	started := start().(sessionStartedMsg)
+	if started.err != nil {
+		t.Fatal(started.err)
+	}
+	m, abort := m.Update(started)
+	if abort == nil || m.session != nil {
+		t.Fatal("expected the started session to be aborted")
+	}
+	_, cmd := m.Update(abort())
+	closed := false
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(closeScrapeWizardMsg); ok {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Error("expected the wizard to close once the session is aborted")
+	}
+	if _, err := client.CompleteManualSession(context.Background(), started.session.SessionID); !api.IsNotFound(err) {
+		t.Errorf("expected the session to be gone, got %v", err)
+	}
+}
+
+func TestParseEpisodeTag(t *testing.T) {
+	tests := []struct {
+		in              string
+		season, episode int
+		ok              bool
+	}{
+		{"Show.S01E02.1080p.mkv", 1, 2, true},
+		{"show s3e14 720p", 3, 14, true},
+		{"Show.S01.E05.mkv", 1, 5, true},
+		{"Show - 2x07 - Title.mkv", 2, 7, true},
+		{"Movie.2019.1080p.mkv", 0, 0, false},
+	}
+	for _, tt := range tests {
+		season, episode, ok := parseEpisodeTag(tt.in)
+		if season != tt.season || episode != tt.episode || ok != tt.ok {
+			t.Errorf("parseEpisodeTag(%q) = %d, %d, %v", tt.in, season, episode, ok)
+		}
+	}
+}
+
+func TestAppOpensScrapeWizardFromStreamsTab(t *testing.T) {
+	fake := &fakeAPI{streams: &models.ItemStreamsResponse{
+		Streams: []models.Stream{{ID: 7, InfoHash: testInfoHash, RawTitle: "The.Matrix.1999.2160p"}},
+	}}
+	app := NewAppWithClient(config.DefaultConfig(), fake)
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.itemDetail = newTestItemDetail(t, fake)
+	app.itemDetail.Update(app.itemDetail.fetchItemStreams()())
+	app.itemDetail.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	app.switchScreen(ScreenItemDetail)
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	app.Update(cmd())
+	if app.currentScreen != ScreenScrapeWizard {
+		t.Fatalf("expected the scrape wizard, got screen %d", app.currentScreen)
+	}
+	target := app.scrapeWizard.target
+	if target.itemID != "1" || target.itemType != "movie" || app.scrapeWizard.magnetInput.Value() != testInfoHash {
+		t.Errorf("expected the highlighted stream of the item, got %+v", target)
+	}
+
+	// Global keys go to the wizard while it is open
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if app.currentScreen != ScreenScrapeWizard {
+		t.Fatal("global key left the wizard")
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	app.Update(cmd())
+	if app.currentScreen != ScreenItemDetail || app.scrapeWizard != nil {
+		t.Errorf("expected esc to return to the item, got screen %d", app.currentScreen)
+	}
+}
+
+func TestAppQuitAbortsWizardSession(t *testing.T) {
+	client := newDemoClient(t)
+	app := NewAppWithClient(config.DefaultConfig(), client)
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	app.Update(openScrapeWizardMsg{target: scrapeTarget{itemID: "1", itemType: "movie", magnet: testInfoHash}})
+	app.scrapeWizard = startTestSession(t, app.scrapeWizard)
+	sessionID := app.scrapeWizard.session.SessionID
+
+	if _, cmd := app.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Fatal("expected ctrl+c to quit")
+	}
+	if _, err := client.CompleteManualSession(context.Background(), sessionID); !api.IsNotFound(err) {
+		t.Errorf("expected the session to be aborted on quit, got %v", err)
+	}
+}
@@ -3,41 +3,173 @@ package tui
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"riven-tui/pkg/api"
+	"riven-tui/pkg/settings"
 )
 
-// SettingsModel represents the settings screen
+// SettingsModel represents the settings screen: a tree of the Riven
+// settings whose fields are typed and validated by the settings schema.
+// Edits are staged and sent together.
 type SettingsModel struct {
-	client   *api.Client
+	client   api.SettingsAPI
 	ctx      context.Context
 	width    int
 	height   int
 	loading  bool
 	error    string
-	settings interface{}
+	settings map[string]interface{}
+
+	// schema types the fields; without it they are typed from their values
+	schema      *settings.Schema
+	schemaError string
+
+	// Tree
+	expanded map[string]bool
+	rows     []settingsRow
+	cursor   int
+	offset   int
+
+	// staged holds the pending edits by path
+	staged map[string]interface{}
+
+	// Field editor and picker
+	editing   bool
+	editInput textinput.Model
+	editError string
+	picker    *settingsPicker
+
+	// File prompt for export and import
+	fileMode  settingsFileMode
+	fileInput textinput.Model
+
+	// History of the writes, with the history view
+	history       *settings.History
+	showHistory   bool
+	snapshots     []settings.Snapshot
+	historyCursor int
+	historyError  string
 
 	// Auto-refresh
 	lastUpdate time.Time
+
+	// Requests in flight; cancelled when the screen is left
+	settingsFetch fetchSlot
+	schemaFetch   fetchSlot
+}
+
+// settingsRow is a line of the settings tree
+type settingsRow struct {
+	path   string
+	key    string
+	depth  int
+	object bool
+	value  interface{}
 }
 
+// settingsPicker chooses the value of an enum, or the elements of a list
+// restricted to an enum
+type settingsPicker struct {
+	field   settings.Field
+	options []string
+	cursor  int
+	multi   bool
+	chosen  map[string]bool
+}
+
+// settingsFileMode is what the file prompt is for
+type settingsFileMode int
+
+const (
+	fileNone settingsFileMode = iota
+	fileExport
+	fileExportSecrets
+	fileImport
+)
+
+// defaultSettingsFile is offered by the file prompt
+const defaultSettingsFile = "riven-settings.yaml"
+
 // settingsMsg represents messages for the settings screen
 type settingsMsg struct {
+	gen      int
 	settings interface{}
 	err      error
 }
 
-// NewSettingsModel creates a new settings model
-func NewSettingsModel(client *api.Client, ctx context.Context) *SettingsModel {
+// settingsSchemaMsg delivers the settings schema
+type settingsSchemaMsg struct {
+	gen    int
+	schema map[string]interface{}
+	err    error
+}
+
+// settingsAppliedMsg reports the outcome of applying the staged edits
+type settingsAppliedMsg struct {
+	applied map[string]interface{}
+	message string
+	err     error
+}
+
+// settingsSavedMsg reports the outcome of saving the settings to file, or
+// of reloading them from it
+type settingsSavedMsg struct {
+	message string
+	reload  bool
+	err     error
+}
+
+// settingsHistoryMsg delivers the snapshots of the settings history
+type settingsHistoryMsg struct {
+	snapshots []settings.Snapshot
+	err       error
+}
+
+// settingsExportedMsg reports the outcome of exporting to a local file
+type settingsExportedMsg struct {
+	path string
+	err  error
+}
+
+// settingsImportMsg delivers a local settings file to diff and stage
+type settingsImportMsg struct {
+	path   string
+	values map[string]interface{}
+	err    error
+}
+
+// NewSettingsModel creates a new settings model. Writes are recorded in the
+// history so they can be rolled back; a nil history records nothing.
+func NewSettingsModel(client api.SettingsAPI, ctx context.Context, history *settings.History) *SettingsModel {
+	if history != nil {
+		client = settings.Record(client, history)
+	}
+
+	editInput := textinput.New()
+	editInput.CharLimit = 0
+	editInput.Width = 60
+
+	fileInput := textinput.New()
+	fileInput.Placeholder = defaultSettingsFile
+	fileInput.Width = 60
+
 	return &SettingsModel{
-		client:  client,
-		ctx:     ctx,
-		loading: true,
+		client:    client,
+		ctx:       ctx,
+		loading:   true,
+		schema:    settings.ParseSchema(nil),
+		expanded:  map[string]bool{},
+		staged:    map[string]interface{}{},
+		editInput: editInput,
+		fileInput: fileInput,
+		history:   history,
 	}
 }
 
@@ -45,53 +177,657 @@ func NewSettingsModel(client *api.Client, ctx context.Context) *SettingsModel {
 func (m *SettingsModel) SetSize(width, height int) {
 	m.width = width
 	m.height = height - 3 // Account for navigation bar
+
+	m.editInput.Width = min(width-30, 80)
+	m.fileInput.Width = min(width-30, 80)
 }
 
 // Init implements tea.Model
 func (m *SettingsModel) Init() tea.Cmd {
-	return tea.Batch(
-		m.fetchSettings(),
-		m.autoRefresh(),
-	)
+	if m.schema.Len() == 0 {
+		return tea.Batch(m.fetchSettings(), m.fetchSchema())
+	}
+	return m.fetchSettings()
+}
+
+// capturesInput implements inputCapturer
+func (m *SettingsModel) capturesInput() bool {
+	return m.editing || m.picker != nil || m.fileMode != fileNone
 }
 
 // Update implements tea.Model
 func (m *SettingsModel) Update(msg tea.Msg) (*SettingsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case settingsMsg:
+		if !m.settingsFetch.finish(msg.gen) {
+			return m, nil
+		}
 		m.loading = false
 		if msg.err != nil {
-			m.error = fmt.Sprintf("Failed to fetch settings: %v", msg.err)
-		} else {
-			m.settings = msg.settings
-			m.error = ""
+			m.error = fmt.Sprintf("Failed to fetch settings: %s", describeError(msg.err))
+			return m, nil
+		}
+		values, ok := msg.settings.(map[string]interface{})
+		if !ok {
+			m.error = "Unexpected settings format"
+			return m, nil
 		}
+		m.settings = values
+		m.error = ""
+		m.dropAppliedEdits()
+		m.buildRows()
 
-	case refreshMsg:
-		m.lastUpdate = time.Now()
+	case settingsSchemaMsg:
+		if !m.schemaFetch.finish(msg.gen) {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.schemaError = fmt.Sprintf("Schema unavailable, fields are typed from their values: %s", firstLine(describeError(msg.err)))
+			return m, nil
+		}
+		m.schema = settings.ParseSchema(msg.schema)
+		m.schemaError = ""
+
+	case settingsAppliedMsg:
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Failed to apply settings: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		for path, value := range msg.applied {
+			if staged, ok := m.staged[path]; ok && settings.Equal(staged, value) {
+				delete(m.staged, path)
+			}
+		}
 		return m, tea.Batch(
+			toastCmd(msg.message, StatusSuccess),
 			m.fetchSettings(),
-			m.autoRefresh(),
+			m.loadHistory(),
+			confirmCmd("Save settings", "Settings applied. Save them to settings.json so they survive a restart?", m.saveSettings()),
 		)
 
+	case settingsSavedMsg:
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Settings file failed: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		if msg.reload {
+			return m, tea.Batch(toastCmd(msg.message, StatusSuccess), m.fetchSettings())
+		}
+		return m, toastCmd(msg.message, StatusSuccess)
+
+	case settingsHistoryMsg:
+		if msg.err != nil {
+			m.historyError = fmt.Sprintf("Failed to read history: %s", describeError(msg.err))
+			return m, nil
+		}
+		m.snapshots = msg.snapshots
+		m.historyError = ""
+		m.historyCursor = max(0, min(m.historyCursor, len(m.snapshots)-1))
+
+	case settingsExportedMsg:
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Export failed: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		return m, toastCmd(fmt.Sprintf("Exported settings to %s", msg.path), StatusSuccess)
+
+	case settingsImportMsg:
+		if msg.err != nil {
+			return m, toastCmd(fmt.Sprintf("Import failed: %s", firstLine(describeError(msg.err))), StatusError)
+		}
+		return m, m.stageFile(msg.path, msg.values)
+
+	case refreshMsg:
+		m.lastUpdate = time.Now()
+		return m, m.fetchSettings()
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "r":
-			m.loading = true
-			return m, m.fetchSettings()
+		switch {
+		case m.showHistory:
+			return m, m.updateHistory(msg)
+		case m.fileMode != fileNone:
+			return m, m.updateFilePrompt(msg)
+		case m.picker != nil:
+			return m, m.updatePicker(msg)
+		case m.editing:
+			return m, m.updateEditor(msg)
 		}
+		return m, m.updateTree(msg)
 	}
 
 	return m, nil
 }
 
+// updateTree handles keys while browsing the tree
+func (m *SettingsModel) updateTree(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "r":
+		m.loading = true
+		return tea.Batch(m.fetchSettings(), m.fetchSchema())
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.treeHeight())
+	case "pgdown":
+		m.moveCursor(m.treeHeight())
+	case "right":
+		if row, ok := m.selectedRow(); ok && row.object {
+			m.expanded[row.path] = true
+			m.buildRows()
+		}
+	case "left":
+		row, ok := m.selectedRow()
+		if !ok {
+			return nil
+		}
+		if row.object && m.expanded[row.path] {
+			delete(m.expanded, row.path)
+			m.buildRows()
+		} else if i := strings.LastIndex(row.path, "."); i >= 0 {
+			m.selectPath(row.path[:i])
+		}
+	case "enter", " ":
+		row, ok := m.selectedRow()
+		if !ok {
+			return nil
+		}
+		if row.object {
+			m.expanded[row.path] = !m.expanded[row.path]
+			m.buildRows()
+			return nil
+		}
+		return m.editField(row)
+	case "u":
+		if row, ok := m.selectedRow(); ok {
+			for path := range m.staged {
+				if path == row.path || strings.HasPrefix(path, row.path+".") {
+					delete(m.staged, path)
+				}
+			}
+		}
+	case "U":
+		m.staged = map[string]interface{}{}
+	case "a":
+		return m.confirmApply()
+	case "w":
+		return confirmCmd("Save settings", "Save the current settings to settings.json?", m.saveSettings())
+	case "L":
+		return confirmCmd("Reload settings", "Reload the settings from settings.json? Changes applied since the last save are lost.", m.loadSettings())
+	case "H":
+		if m.history == nil {
+			return nil
+		}
+		m.showHistory = true
+		m.historyCursor = 0
+		return m.loadHistory()
+	case "x", "X", "i":
+		if m.settings == nil {
+			return nil
+		}
+		m.fileMode = map[string]settingsFileMode{"x": fileExport, "X": fileExportSecrets, "i": fileImport}[msg.String()]
+		m.fileInput.SetValue("")
+		return m.fileInput.Focus()
+	}
+	return nil
+}
+
+// updateHistory handles keys in the history view
+func (m *SettingsModel) updateHistory(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "H":
+		m.showHistory = false
+	case "up", "k":
+		m.historyCursor = max(m.historyCursor-1, 0)
+	case "down", "j":
+		m.historyCursor = max(0, min(m.historyCursor+1, len(m.snapshots)-1))
+	case "r":
+		return m.loadHistory()
+	case "R":
+		if m.historyCursor < len(m.snapshots) {
+			return m.confirmRollback(m.snapshots[m.historyCursor])
+		}
+	}
+	return nil
+}
+
+// confirmRollback asks to restore the values a snapshot replaced
+func (m *SettingsModel) confirmRollback(snapshot settings.Snapshot) tea.Cmd {
+	requests := settings.Rollback(snapshot)
+	if len(requests) == 0 {
+		return toastCmd("Nothing to roll back: the settings did not exist before", StatusWarning)
+	}
+
+	lines := []string{fmt.Sprintf("Roll back \"%s\" from %s?", snapshot.Description, snapshot.Time.Local().Format("2006-01-02 15:04:05")), ""}
+	for i, r := range requests {
+		if i == 15 {
+			lines = append(lines, fmt.Sprintf("… and %d more", len(requests)-i))
+			break
+		}
+		current, _ := settings.Lookup(m.settings, r.Key)
+		field := m.schema.FieldFor(r.Key, current)
+		lines = append(lines, fmt.Sprintf("%s: %s → %s", r.Key, truncateString(field.Format(current), 40), truncateString(field.Format(r.Value), 40)))
+	}
+
+	description := fmt.Sprintf("Rollback of %s (%s)", snapshot.ID, snapshot.Description)
+	return confirmCmd("Roll back settings", strings.Join(lines, "\n"), m.setSettings(requests, description))
+}
+
+// loadHistory reads the snapshots of the history
+func (m *SettingsModel) loadHistory() tea.Cmd {
+	if m.history == nil {
+		return nil
+	}
+	history := m.history
+	return func() tea.Msg {
+		snapshots, err := history.List()
+		return settingsHistoryMsg{snapshots: snapshots, err: err}
+	}
+}
+
+// updateFilePrompt handles keys in the export/import file prompt
+func (m *SettingsModel) updateFilePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.fileMode = fileNone
+		m.fileInput.Blur()
+		return nil
+	case "enter":
+		path := strings.TrimSpace(m.fileInput.Value())
+		if path == "" {
+			path = defaultSettingsFile
+		}
+		mode := m.fileMode
+		m.fileMode = fileNone
+		m.fileInput.Blur()
+		if mode == fileImport {
+			return importSettingsFile(path)
+		}
+		return m.exportSettingsFile(path, mode == fileExportSecrets)
+	}
+
+	var cmd tea.Cmd
+	m.fileInput, cmd = m.fileInput.Update(msg)
+	return cmd
+}
+
+// exportSettingsFile writes the settings to a local YAML or JSON file
+func (m *SettingsModel) exportSettingsFile(path string, secrets bool) tea.Cmd {
+	values := m.settings
+	if !secrets {
+		values = settings.Redact(values, m.schema)
+	}
+	return func() tea.Msg {
+		return settingsExportedMsg{path: path, err: settings.WriteFile(path, values)}
+	}
+}
+
+// importSettingsFile reads a local settings file
+func importSettingsFile(path string) tea.Cmd {
+	return func() tea.Msg {
+		values, err := settings.ReadFile(path)
+		return settingsImportMsg{path: path, values: values, err: err}
+	}
+}
+
+// stageFile stages the settings a file changes, so they are reviewed in
+// the pending changes and applied like any other edit
+func (m *SettingsModel) stageFile(path string, values map[string]interface{}) tea.Cmd {
+	var staged, unknown, invalid int
+	for _, c := range settings.Diff(m.settings, values) {
+		switch c.Kind {
+		case settings.Unknown:
+			unknown++
+		case settings.Changed:
+			if err := m.schema.FieldFor(c.Path, c.Old).Validate(c.New); err != nil {
+				invalid++
+				continue
+			}
+			m.staged[c.Path] = c.New
+			staged++
+		}
+	}
+
+	message := fmt.Sprintf("Staged %d change(s) from %s", staged, path)
+	if staged == 0 {
+		message = fmt.Sprintf("%s matches the live settings", path)
+	}
+	if unknown+invalid > 0 {
+		message += fmt.Sprintf("; skipped %d unknown and %d invalid setting(s)", unknown, invalid)
+		return toastCmd(message, StatusWarning)
+	}
+	return toastCmd(message, StatusSuccess)
+}
+
+// editField starts editing a setting: booleans are toggled, enums get a
+// picker and everything else a text input
+func (m *SettingsModel) editField(row settingsRow) tea.Cmd {
+	field := m.schema.FieldFor(row.path, row.value)
+	current := m.value(row.path)
+	m.editError = ""
+
+	if field.ReadOnly {
+		m.editError = fmt.Sprintf("%s is read-only", row.path)
+		return nil
+	}
+
+	if field.Kind == settings.KindBoolean {
+		b, _ := current.(bool)
+		m.stage(row.path, !b)
+		return nil
+	}
+
+	if options := field.Choices(); len(options) > 0 {
+		picker := &settingsPicker{field: field, options: options, multi: field.Kind == settings.KindArray, chosen: map[string]bool{}}
+		if picker.multi {
+			items, _ := current.([]interface{})
+			for _, item := range items {
+				picker.chosen[settings.FormatValue(item)] = true
+			}
+		} else {
+			for i, option := range options {
+				if option == settings.FormatValue(current) {
+					picker.cursor = i
+				}
+			}
+		}
+		m.picker = picker
+		return nil
+	}
+
+	m.editing = true
+	m.editInput.Placeholder = ""
+	m.editInput.SetValue(settings.FormatValue(current))
+	if field.Secret {
+		// Secrets are replaced, never shown
+		m.editInput.Placeholder = "new value"
+		m.editInput.SetValue("")
+	}
+	m.editInput.CursorEnd()
+	return m.editInput.Focus()
+}
+
+// updateEditor handles keys in the field editor
+func (m *SettingsModel) updateEditor(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		m.editError = ""
+		m.editInput.Blur()
+		return nil
+	case "enter":
+		row, ok := m.selectedRow()
+		if !ok {
+			return nil
+		}
+		field := m.schema.FieldFor(row.path, row.value)
+		value, err := field.Parse(m.editInput.Value())
+		if err != nil {
+			m.editError = err.Error()
+			return nil
+		}
+		m.stage(row.path, value)
+		m.editing = false
+		m.editError = ""
+		m.editInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.editInput, cmd = m.editInput.Update(msg)
+	return cmd
+}
+
+// updatePicker handles keys in the enum picker
+func (m *SettingsModel) updatePicker(msg tea.KeyMsg) tea.Cmd {
+	p := m.picker
+	switch msg.String() {
+	case "esc":
+		m.picker = nil
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.options)-1 {
+			p.cursor++
+		}
+	case " ":
+		if p.multi {
+			option := p.options[p.cursor]
+			p.chosen[option] = !p.chosen[option]
+		}
+	case "enter":
+		var value interface{} = p.options[p.cursor]
+		if p.multi {
+			items := []interface{}{}
+			for _, option := range p.options {
+				if p.chosen[option] {
+					items = append(items, option)
+				}
+			}
+			value = items
+		}
+		if err := p.field.Validate(value); err != nil {
+			m.editError = err.Error()
+			return nil
+		}
+		m.stage(p.field.Path, value)
+		m.picker = nil
+	}
+	return nil
+}
+
+// stage records an edit, or drops it if the value is back to the current one
+func (m *SettingsModel) stage(path string, value interface{}) {
+	current, _ := settings.Lookup(m.settings, path)
+	if settings.Equal(current, value) {
+		delete(m.staged, path)
+		return
+	}
+	m.staged[path] = value
+}
+
+// value returns the value of a setting, staged edits included
+func (m *SettingsModel) value(path string) interface{} {
+	if value, ok := m.staged[path]; ok {
+		return value
+	}
+	value, _ := settings.Lookup(m.settings, path)
+	return value
+}
+
+// dropAppliedEdits forgets staged edits the server already has
+func (m *SettingsModel) dropAppliedEdits() {
+	for path, value := range m.staged {
+		current, ok := settings.Lookup(m.settings, path)
+		if !ok || settings.Equal(current, value) {
+			delete(m.staged, path)
+		}
+	}
+}
+
+// stagedPaths returns the paths of the staged edits in order
+func (m *SettingsModel) stagedPaths() []string {
+	paths := make([]string, 0, len(m.staged))
+	for path := range m.staged {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// confirmApply asks to apply the staged edits, showing them as a diff
+func (m *SettingsModel) confirmApply() tea.Cmd {
+	if len(m.staged) == 0 {
+		return nil
+	}
+
+	paths := m.stagedPaths()
+	lines := []string{fmt.Sprintf("Apply %d change(s)?", len(paths)), ""}
+	for i, path := range paths {
+		if i == 15 {
+			lines = append(lines, fmt.Sprintf("… and %d more", len(paths)-i))
+			break
+		}
+		lines = append(lines, m.diffLine(path))
+	}
+
+	return confirmCmd("Apply settings", strings.Join(lines, "\n"), m.applySettings())
+}
+
+// applySettings sends the staged edits
+func (m *SettingsModel) applySettings() tea.Cmd {
+	paths := m.stagedPaths()
+	requests := make([]api.SetSettingsRequest, len(paths))
+	for i, path := range paths {
+		requests[i] = api.SetSettingsRequest{Key: path, Value: m.staged[path]}
+	}
+
+	description := fmt.Sprintf("Edited %s", strings.Join(paths, ", "))
+	if len(paths) > 3 {
+		description = fmt.Sprintf("Edited %s and %d more", strings.Join(paths[:3], ", "), len(paths)-3)
+	}
+	return m.setSettings(requests, description)
+}
+
+// setSettings sends settings, described as given in the history. Setting
+// a value is idempotent, so the request is marked retryable.
+func (m *SettingsModel) setSettings(requests []api.SetSettingsRequest, description string) tea.Cmd {
+	applied := make(map[string]interface{}, len(requests))
+	for _, r := range requests {
+		applied[r.Key] = r.Value
+	}
+
+	ctx := settings.WithDescription(api.WithRetry(m.ctx), description)
+	return func() tea.Msg {
+		resp, err := m.client.SetSettings(ctx, requests)
+		if err != nil {
+			return settingsAppliedMsg{err: err}
+		}
+		message := resp.Message
+		if message == "" {
+			message = fmt.Sprintf("Applied %d setting(s)", len(requests))
+		}
+		return settingsAppliedMsg{applied: applied, message: message}
+	}
+}
+
+// saveSettings saves the settings to the server's settings file
+func (m *SettingsModel) saveSettings() tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		resp, err := m.client.SaveSettings(ctx)
+		if err != nil {
+			return settingsSavedMsg{err: err}
+		}
+		return settingsSavedMsg{message: resp.Message}
+	}
+}
+
+// loadSettings reloads the settings from the server's settings file
+func (m *SettingsModel) loadSettings() tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		resp, err := m.client.LoadSettings(ctx)
+		if err != nil {
+			return settingsSavedMsg{err: err}
+		}
+		return settingsSavedMsg{message: resp.Message, reload: true}
+	}
+}
+
+// buildRows flattens the expanded part of the tree, keeping the cursor on
+// the same setting
+func (m *SettingsModel) buildRows() {
+	selected := ""
+	if row, ok := m.selectedRow(); ok {
+		selected = row.path
+	}
+
+	m.rows = nil
+	var walk func(prefix string, object map[string]interface{}, depth int)
+	walk = func(prefix string, object map[string]interface{}, depth int) {
+		for _, key := range settings.Keys(object) {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			child, isObject := object[key].(map[string]interface{})
+			m.rows = append(m.rows, settingsRow{path: path, key: key, depth: depth, object: isObject, value: object[key]})
+			if isObject && m.expanded[path] {
+				walk(path, child, depth+1)
+			}
+		}
+	}
+	walk("", m.settings, 0)
+
+	m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+	if selected != "" {
+		m.selectPath(selected)
+	}
+}
+
+// selectPath expands the parents of a setting and moves the cursor to it
+func (m *SettingsModel) selectPath(path string) bool {
+	parts := strings.Split(path, ".")
+	rebuild := false
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], ".")
+		if !m.expanded[parent] {
+			m.expanded[parent] = true
+			rebuild = true
+		}
+	}
+	if rebuild {
+		m.buildRows()
+	}
+	for i, row := range m.rows {
+		if row.path == path {
+			m.cursor = i
+			m.scrollToCursor()
+			return true
+		}
+	}
+	return false
+}
+
+// selectedRow returns the row under the cursor
+func (m *SettingsModel) selectedRow() (settingsRow, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return settingsRow{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// moveCursor moves the cursor by delta rows
+func (m *SettingsModel) moveCursor(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.rows)-1))
+	m.editError = ""
+	m.scrollToCursor()
+}
+
+// scrollToCursor keeps the cursor within the visible rows
+func (m *SettingsModel) scrollToCursor() {
+	height := m.treeHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+// treeHeight returns the number of tree rows that fit on screen
+func (m *SettingsModel) treeHeight() int {
+	return max(m.height-15-min(len(m.staged), 6), 5)
+}
+
 // View implements tea.Model
 func (m *SettingsModel) View() string {
-	if m.loading {
+	if m.loading && m.settings == nil {
 		return m.renderLoading()
 	}
 
-	if m.error != "" {
+	if m.error != "" && m.settings == nil {
 		return m.renderError()
 	}
 
@@ -119,81 +855,281 @@ func (m *SettingsModel) renderError() string {
 	return style.Render(fmt.Sprintf("Error: %s\n\nPress 'r' to refresh", m.error))
 }
 
-// renderSettings renders the main settings view
+// renderSettings renders the settings tree, the selected field and the
+// pending changes
 func (m *SettingsModel) renderSettings() string {
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("39")).
-		Margin(0, 0, 2, 0).
 		Render("⚙️ Riven Settings")
 
-	var content string
-	if m.settings != nil {
-		// Try to display some actual settings data
-		if settingsMap, ok := m.settings.(map[string]interface{}); ok {
-			var settingsInfo []string
-			settingsInfo = append(settingsInfo, "📊 Current Settings Overview:")
-			settingsInfo = append(settingsInfo, "")
-
-			// Display key settings categories
-			categories := []string{"general", "scraping", "content", "downloaders", "indexers"}
-			for _, category := range categories {
-				if categoryData, exists := settingsMap[category]; exists {
-					if categoryMap, ok := categoryData.(map[string]interface{}); ok {
-						settingsInfo = append(settingsInfo, fmt.Sprintf("• %s: %d settings configured",
-							strings.ToUpper(category[:1])+category[1:], len(categoryMap)))
-					}
-				}
-			}
+	sections := []string{title}
+	if m.schemaError != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(m.schemaError))
+	}
+	if m.error != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.error))
+	}
 
-			settingsInfo = append(settingsInfo, "")
-			settingsInfo = append(settingsInfo, "🔧 Available Actions:")
-			settingsInfo = append(settingsInfo, "• View detailed settings (coming soon)")
-			settingsInfo = append(settingsInfo, "• Export settings configuration")
-			settingsInfo = append(settingsInfo, "• Validate current settings")
-			settingsInfo = append(settingsInfo, "")
-			settingsInfo = append(settingsInfo, "Note: Settings editing requires the Riven web interface.")
-			settingsInfo = append(settingsInfo, "Press 'r' to refresh settings.")
+	if m.showHistory {
+		sections = append(sections, m.renderHistory())
+		return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	}
+
+	sections = append(sections, lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Width(min(m.width-4, 140)).
+		Render(m.renderTree()))
+
+	sections = append(sections, m.renderField())
+	if m.fileMode != fileNone {
+		prompt := map[settingsFileMode]string{
+			fileExport:        "Export to (secrets redacted): ",
+			fileExportSecrets: "Export to (with secrets): ",
+			fileImport:        "Import from: ",
+		}[m.fileMode]
+		sections = append(sections, prompt+m.fileInput.View())
+	}
+	sections = append(sections, m.renderPending())
+
+	controls := "Controls: [↑/↓] move [→/←] expand/collapse [enter] edit/toggle [u] undo edit [U] undo all [a] apply\n" +
+		"          [x/X] export without/with secrets [i] import file [w] save to settings.json [L] reload it [H] history [r] refresh"
+	switch {
+	case m.fileMode != fileNone:
+		controls = "Controls: [enter] confirm (.yaml, .yml or .json) [esc] cancel"
+	case m.picker != nil && m.picker.multi:
+		controls = "Controls: [space] toggle [enter] done [esc] cancel"
+	case m.picker != nil:
+		controls = "Controls: [↑/↓] choose [enter] done [esc] cancel"
+	case m.editing:
+		controls = "Controls: [enter] stage [esc] cancel"
+	}
+	sections = append(sections, lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render(controls))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
+
+// renderHistory renders the snapshots with the changes of the selected one
+func (m *SettingsModel) renderHistory() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+	controls := dim.Render("Controls: [↑/↓] move [R] roll back [r] refresh [esc/H] back to settings")
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Settings history"), dim.Render(m.history.Dir()), ""}
+	if m.historyError != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.historyError))
+	}
+	if len(m.snapshots) == 0 {
+		lines = append(lines, "No changes recorded yet. Settings written from riven-tui are recorded here.", "", controls)
+		return strings.Join(lines, "\n")
+	}
 
-			content = strings.Join(settingsInfo, "\n")
+	height := max(m.height/2-4, 3)
+	start := max(0, min(m.historyCursor-height/2, len(m.snapshots)-height))
+	for i := start; i < len(m.snapshots) && i < start+height; i++ {
+		snapshot := m.snapshots[i]
+		line := fmt.Sprintf("%s  %s (%d)", snapshot.Time.Local().Format("2006-01-02 15:04:05"), truncateString(snapshot.Description, 70), len(snapshot.Before))
+		if i == m.historyCursor {
+			line = selectedStyle.Render("▶ " + line)
 		} else {
-			content = "Settings loaded successfully!\n\n" +
-				"Note: Settings editing is read-only in this version.\n" +
-				"Use the Riven web interface to modify settings.\n\n" +
-				"Press 'r' to refresh settings."
+			line = "  " + line
 		}
-	} else {
-		content = "No settings data available.\n\nPress 'r' to refresh."
+		lines = append(lines, line)
 	}
 
-	contentStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
-		Padding(2, 4).
-		Margin(1, 0).
-		Height(m.height - 8)
+	selected := m.snapshots[m.historyCursor]
+	lines = append(lines, "", lipgloss.NewStyle().Bold(true).Render("Changes (before → after)"))
+	old := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	next := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	paths := selected.Paths()
+	for i, path := range paths {
+		if i == max(m.height/2-6, 3) {
+			lines = append(lines, fmt.Sprintf("  … and %d more", len(paths)-i))
+			break
+		}
+		field := m.schema.FieldFor(path, selected.After[path])
+		before := "(not set)"
+		if selected.HadValue(path) {
+			before = field.Format(selected.Before[path])
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s → %s", path, old.Render(truncateString(before, 40)), next.Render(truncateString(field.Format(selected.After[path]), 40))))
+	}
 
-	body := lipgloss.JoinVertical(lipgloss.Left, title, contentStyle.Render(content))
+	lines = append(lines, "", controls)
+	return strings.Join(lines, "\n")
+}
 
-	style := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Padding(1, 2)
+// renderTree renders the visible rows of the tree
+func (m *SettingsModel) renderTree() string {
+	if len(m.rows) == 0 {
+		return "No settings available."
+	}
+
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	stagedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57"))
+
+	height := m.treeHeight()
+	var lines []string
+	for i := m.offset; i < len(m.rows) && i < m.offset+height; i++ {
+		row := m.rows[i]
+		indent := strings.Repeat("  ", row.depth)
+
+		var line string
+		if row.object {
+			marker := "▸"
+			if m.expanded[row.path] {
+				marker = "▾"
+			}
+			line = fmt.Sprintf("%s%s %s", indent, marker, keyStyle.Render(row.key))
+			if n := m.stagedUnder(row.path); n > 0 {
+				line += stagedStyle.Render(fmt.Sprintf(" (%d pending)", n))
+			}
+		} else {
+			field := m.schema.FieldFor(row.path, row.value)
+			value := valueStyle.Render(truncateString(field.Format(row.value), 60))
+			if staged, ok := m.staged[row.path]; ok {
+				value = stagedStyle.Render("● " + truncateString(field.Format(staged), 60))
+			}
+			line = fmt.Sprintf("%s  %s: %s", indent, keyStyle.Render(row.key), value)
+		}
+
+		if i == m.cursor {
+			line = selectedStyle.Render("▶") + line
+		} else {
+			line = " " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(m.rows) > height {
+		lines = append(lines, valueStyle.Render(fmt.Sprintf(" %d-%d of %d", m.offset+1, min(m.offset+height, len(m.rows)), len(m.rows))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderField describes the selected setting, with the editor or picker
+// when open
+func (m *SettingsModel) renderField() string {
+	row, ok := m.selectedRow()
+	if !ok {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	if row.object {
+		return dim.Render(fmt.Sprintf("%s: %d settings", row.path, len(row.value.(map[string]interface{}))))
+	}
+
+	field := m.schema.FieldFor(row.path, row.value)
+	kind := string(field.Kind)
+	if field.Kind == settings.KindArray && field.ItemKind != "" {
+		kind = fmt.Sprintf("list of %s", field.ItemKind)
+	}
+	details := []string{row.path, kind}
+	if r := field.Range(); r != "" {
+		details = append(details, r)
+	}
+	if choices := field.Choices(); len(choices) > 0 {
+		details = append(details, "one of "+strings.Join(choices, ", "))
+	}
+	if field.Secret {
+		details = append(details, "secret")
+	}
+	if field.ReadOnly {
+		details = append(details, "read-only")
+	}
+
+	lines := []string{dim.Render(strings.Join(details, " | "))}
+	if field.Description != "" {
+		lines = append(lines, dim.Render(field.Description))
+	}
+
+	switch {
+	case m.picker != nil:
+		for i, option := range m.picker.options {
+			mark := "( )"
+			if m.picker.multi && m.picker.chosen[option] || !m.picker.multi && i == m.picker.cursor {
+				mark = "(•)"
+			}
+			if m.picker.multi {
+				mark = strings.NewReplacer("(", "[", ")", "]", "•", "x").Replace(mark)
+			}
+			line := fmt.Sprintf("%s %s", mark, option)
+			if i == m.picker.cursor {
+				line = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Render(line)
+			}
+			lines = append(lines, "  "+line)
+		}
+	case m.editing:
+		lines = append(lines, "New value: "+m.editInput.View())
+	}
+	if m.editError != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.editError))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderPending renders the staged edits as a diff
+func (m *SettingsModel) renderPending() string {
+	if len(m.staged) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("No pending changes")
+	}
+
+	paths := m.stagedPaths()
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Pending changes (%d) - [a] apply", len(paths)))}
+	for i, path := range paths {
+		if i == 6 {
+			lines = append(lines, fmt.Sprintf("  … and %d more", len(paths)-i))
+			break
+		}
+		lines = append(lines, "  "+m.diffLine(path))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffLine renders a staged edit as "path: old → new"
+func (m *SettingsModel) diffLine(path string) string {
+	current, _ := settings.Lookup(m.settings, path)
+	field := m.schema.FieldFor(path, current)
+	old := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(truncateString(field.Format(current), 40))
+	next := lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render(truncateString(field.Format(m.staged[path]), 40))
+	return fmt.Sprintf("%s: %s → %s", path, old, next)
+}
 
-	return style.Render(body)
+// stagedUnder returns the number of staged edits below a path
+func (m *SettingsModel) stagedUnder(path string) int {
+	n := 0
+	for p := range m.staged {
+		if strings.HasPrefix(p, path+".") {
+			n++
+		}
+	}
+	return n
 }
 
 // fetchSettings fetches settings from the API
 func (m *SettingsModel) fetchSettings() tea.Cmd {
+	ctx, gen := m.settingsFetch.begin(m.ctx)
 	return tea.Cmd(func() tea.Msg {
-		settings, err := m.client.GetAllSettings(m.ctx)
-		return settingsMsg{settings: settings, err: err}
+		values, err := m.client.GetAllSettings(ctx)
+		return settingsMsg{gen: gen, settings: values, err: err}
 	})
 }
 
-// autoRefresh sets up auto-refresh
-func (m *SettingsModel) autoRefresh() tea.Cmd {
-	return tea.Tick(60*time.Second, func(t time.Time) tea.Msg {
-		return refreshMsg{}
-	})
+// fetchSchema fetches the settings schema from the API
+func (m *SettingsModel) fetchSchema() tea.Cmd {
+	ctx, gen := m.schemaFetch.begin(m.ctx)
+	return func() tea.Msg {
+		schema, err := m.client.GetSettingsSchema(ctx)
+		return settingsSchemaMsg{gen: gen, schema: schema, err: err}
+	}
+}
+
+// cancelRequests cancels the requests in flight and drops their results
+func (m *SettingsModel) cancelRequests() {
+	m.settingsFetch.stop()
+	m.schemaFetch.stop()
 }
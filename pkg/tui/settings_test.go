@@ -0,0 +1,231 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"riven-tui/pkg/settings"
+)
+
+// loadSettings returns a settings screen with the demo settings and schema
+// loaded
+func loadSettings(t *testing.T) *SettingsModel {
+	t.Helper()
+	m := NewSettingsModel(newDemoClient(t), context.Background(), settings.NewHistory(t.TempDir(), 0))
+	m.SetSize(160, 50)
+	for _, msg := range batchMsgs(m.Init()) {
+		m, _ = m.Update(msg)
+	}
+	if m.settings == nil || m.schema.Len() == 0 {
+		t.Fatalf("expected settings and schema, got error %q / %q", m.error, m.schemaError)
+	}
+	return m
+}
+
+// keyPress returns the key message for a key name or typed text
+func keyPress(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestE2ESettingsApply(t *testing.T) {
+	m := loadSettings(t)
+
+	if !m.selectPath("scraping.torrentio.enabled") {
+		t.Fatal("expected scraping.torrentio.enabled in the tree")
+	}
+	m, _ = m.Update(keyPress("enter"))
+	if m.staged["scraping.torrentio.enabled"] != false {
+		t.Fatalf("expected the toggle to be staged, got %v", m.staged)
+	}
+	if !strings.Contains(m.View(), "Pending changes (1)") {
+		t.Errorf("expected the pending change in the view:\n%s", m.View())
+	}
+
+	// Toggling back drops the edit
+	m, _ = m.Update(keyPress("enter"))
+	if len(m.staged) != 0 {
+		t.Fatalf("expected no staged edits, got %v", m.staged)
+	}
+	m, _ = m.Update(keyPress("enter"))
+
+	_, cmd := m.Update(keyPress("a"))
+	confirm, ok := cmd().(confirmMsg)
+	if !ok || !strings.Contains(confirm.message, "scraping.torrentio.enabled") {
+		t.Fatalf("expected a confirmation with the diff, got %#v", confirm)
+	}
+	m, cmd = m.Update(confirm.onConfirm())
+	if len(m.staged) != 0 {
+		t.Fatalf("expected the applied edit to be cleared, got %v", m.staged)
+	}
+	for _, msg := range batchMsgs(cmd) {
+		if _, ok := msg.(confirmMsg); ok {
+			continue
+		}
+		m, _ = m.Update(msg)
+	}
+
+	got, err := m.client.GetSettings(context.Background(), "scraping.torrentio.enabled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["scraping.torrentio.enabled"] != false {
+		t.Errorf("expected the server to have the new value, got %v", got)
+	}
+	if value, _ := settings.Lookup(m.settings, "scraping.torrentio.enabled"); value != false {
+		t.Errorf("expected the screen to refetch the new value, got %v", value)
+	}
+}
+
+func TestSettingsEditValidation(t *testing.T) {
+	m := loadSettings(t)
+
+	m.selectPath("updaters.updater_interval")
+	m, _ = m.Update(keyPress("enter"))
+	if !m.editing || !m.capturesInput() {
+		t.Fatal("expected the field editor to open")
+	}
+	m.editInput.SetValue("5")
+	m, _ = m.Update(keyPress("enter"))
+	if !m.editing || m.editError != "must be at least 30" {
+		t.Fatalf("expected a range error, got %q", m.editError)
+	}
+	m.editInput.SetValue("300")
+	m, _ = m.Update(keyPress("enter"))
+	if m.editing || m.staged["updaters.updater_interval"] != float64(300) {
+		t.Fatalf("expected 300 to be staged, got %v (%q)", m.staged, m.editError)
+	}
+
+	// Read-only fields are not edited
+	m.selectPath("version")
+	m, _ = m.Update(keyPress("enter"))
+	if m.editing || !strings.Contains(m.editError, "read-only") {
+		t.Errorf("expected version to be read-only, got %q", m.editError)
+	}
+}
+
+func TestSettingsEnumPicker(t *testing.T) {
+	m := loadSettings(t)
+
+	m.selectPath("ranking.profile")
+	m, _ = m.Update(keyPress("enter"))
+	if m.picker == nil || strings.Join(m.picker.options, ",") != "default,best,custom" {
+		t.Fatalf("expected a picker with the profiles, got %+v", m.picker)
+	}
+	m, _ = m.Update(keyPress("down"))
+	m, _ = m.Update(keyPress("enter"))
+	if m.picker != nil || m.staged["ranking.profile"] != "best" {
+		t.Fatalf("expected best to be staged, got %v", m.staged)
+	}
+
+	// Lists of choices are multi-select
+	m.selectPath("notifications.on_item_type")
+	m, _ = m.Update(keyPress("enter"))
+	if m.picker == nil || !m.picker.multi || !m.picker.chosen["show"] {
+		t.Fatalf("expected a multi-select picker, got %+v", m.picker)
+	}
+	m, _ = m.Update(keyPress(" "))
+	m, _ = m.Update(keyPress("enter"))
+	if got := settings.FormatValue(m.staged["notifications.on_item_type"]); got != "show, season" {
+		t.Errorf("expected movie to be removed, got %q", got)
+	}
+}
+
+func TestSettingsMasksSecrets(t *testing.T) {
+	m := loadSettings(t)
+
+	m.selectPath("downloaders.real_debrid.api_key")
+	view := m.View()
+	if strings.Contains(view, "rd-demo-key") || !strings.Contains(view, settings.Mask) {
+		t.Errorf("expected the API key to be masked:\n%s", view)
+	}
+
+	m, _ = m.Update(keyPress("enter"))
+	if m.editInput.Value() != "" {
+		t.Errorf("expected the secret editor to start empty, got %q", m.editInput.Value())
+	}
+}
+
+func TestSettingsExportImport(t *testing.T) {
+	m := loadSettings(t)
+	path := filepath.Join(t.TempDir(), "riven.yaml")
+
+	m, _ = m.Update(keyPress("x"))
+	if !m.capturesInput() {
+		t.Fatal("expected the file prompt to capture input")
+	}
+	m.fileInput.SetValue(path)
+	m, cmd := m.Update(keyPress("enter"))
+	if exported := cmd().(settingsExportedMsg); exported.err != nil {
+		t.Fatal(exported.err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "rd-demo-key") || !strings.Contains(string(data), settings.Mask) {
+		t.Fatalf("expected the export to redact secrets:\n%s", data)
+	}
+
+	// Edit the file and import it: the changes are staged, not applied
+	edited := strings.Replace(string(data), "updater_interval: 120", "updater_interval: 600", 1)
+	if err := os.WriteFile(path, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = m.Update(keyPress("i"))
+	m.fileInput.SetValue(path)
+	m, cmd = m.Update(keyPress("enter"))
+	m, _ = m.Update(cmd())
+
+	if len(m.staged) != 1 || m.staged["updaters.updater_interval"] != float64(600) {
+		t.Fatalf("expected only the edited setting to be staged, got %v", m.staged)
+	}
+}
+
+func TestSettingsHistoryRollback(t *testing.T) {
+	m := loadSettings(t)
+
+	m.selectPath("ranking.profile")
+	m.staged["ranking.profile"] = "best"
+	_, cmd := m.Update(keyPress("a"))
+	m, _ = m.Update(cmd().(confirmMsg).onConfirm())
+
+	m, cmd = m.Update(keyPress("H"))
+	m, _ = m.Update(cmd())
+	if len(m.snapshots) != 1 || m.snapshots[0].Description != "Edited ranking.profile" {
+		t.Fatalf("expected the write in the history, got %+v", m.snapshots)
+	}
+	if view := m.View(); !strings.Contains(view, "Edited ranking.profile") {
+		t.Errorf("expected the snapshot in the view:\n%s", view)
+	}
+
+	_, cmd = m.Update(keyPress("R"))
+	confirm := cmd().(confirmMsg)
+	if !strings.Contains(confirm.message, "ranking.profile") {
+		t.Fatalf("expected the rollback diff, got %q", confirm.message)
+	}
+	m.Update(confirm.onConfirm())
+
+	got, err := m.client.GetSettings(context.Background(), "ranking.profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["ranking.profile"] != "default" {
+		t.Errorf("expected the rollback to restore default, got %v", got)
+	}
+	if snapshots, _ := m.history.List(); len(snapshots) != 2 {
+		t.Errorf("expected the rollback to be recorded too, got %d snapshots", len(snapshots))
+	}
+}